@@ -0,0 +1,3 @@
+package swiftcodesv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative swiftcodes/v1/swift_codes.proto
@@ -0,0 +1,651 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: swiftcodes/v1/swift_codes.proto
+
+package swiftcodesv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SwiftCode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SwiftCode     string                 `protobuf:"bytes,1,opt,name=swift_code,json=swiftCode,proto3" json:"swift_code,omitempty"`
+	IsHeadquarter bool                   `protobuf:"varint,2,opt,name=is_headquarter,json=isHeadquarter,proto3" json:"is_headquarter,omitempty"`
+	BankName      string                 `protobuf:"bytes,3,opt,name=bank_name,json=bankName,proto3" json:"bank_name,omitempty"`
+	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	CountryIso2   string                 `protobuf:"bytes,5,opt,name=country_iso2,json=countryIso2,proto3" json:"country_iso2,omitempty"`
+	CountryName   string                 `protobuf:"bytes,6,opt,name=country_name,json=countryName,proto3" json:"country_name,omitempty"`
+	// Increases with every change; 0 for records stored before revisions.
+	Revision      int64 `protobuf:"varint,7,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwiftCode) Reset() {
+	*x = SwiftCode{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwiftCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwiftCode) ProtoMessage() {}
+
+func (x *SwiftCode) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwiftCode.ProtoReflect.Descriptor instead.
+func (*SwiftCode) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SwiftCode) GetSwiftCode() string {
+	if x != nil {
+		return x.SwiftCode
+	}
+	return ""
+}
+
+func (x *SwiftCode) GetIsHeadquarter() bool {
+	if x != nil {
+		return x.IsHeadquarter
+	}
+	return false
+}
+
+func (x *SwiftCode) GetBankName() string {
+	if x != nil {
+		return x.BankName
+	}
+	return ""
+}
+
+func (x *SwiftCode) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SwiftCode) GetCountryIso2() string {
+	if x != nil {
+		return x.CountryIso2
+	}
+	return ""
+}
+
+func (x *SwiftCode) GetCountryName() string {
+	if x != nil {
+		return x.CountryName
+	}
+	return ""
+}
+
+func (x *SwiftCode) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type GetSwiftCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SwiftCode     string                 `protobuf:"bytes,1,opt,name=swift_code,json=swiftCode,proto3" json:"swift_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSwiftCodeRequest) Reset() {
+	*x = GetSwiftCodeRequest{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSwiftCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSwiftCodeRequest) ProtoMessage() {}
+
+func (x *GetSwiftCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSwiftCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetSwiftCodeRequest) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetSwiftCodeRequest) GetSwiftCode() string {
+	if x != nil {
+		return x.SwiftCode
+	}
+	return ""
+}
+
+type GetSwiftCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SwiftCode     *SwiftCode             `protobuf:"bytes,1,opt,name=swift_code,json=swiftCode,proto3" json:"swift_code,omitempty"`
+	Branches      []*SwiftCode           `protobuf:"bytes,2,rep,name=branches,proto3" json:"branches,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSwiftCodeResponse) Reset() {
+	*x = GetSwiftCodeResponse{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSwiftCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSwiftCodeResponse) ProtoMessage() {}
+
+func (x *GetSwiftCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSwiftCodeResponse.ProtoReflect.Descriptor instead.
+func (*GetSwiftCodeResponse) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSwiftCodeResponse) GetSwiftCode() *SwiftCode {
+	if x != nil {
+		return x.SwiftCode
+	}
+	return nil
+}
+
+func (x *GetSwiftCodeResponse) GetBranches() []*SwiftCode {
+	if x != nil {
+		return x.Branches
+	}
+	return nil
+}
+
+type ListByCountryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ISO 3166-1 alpha-2 country code, case-insensitive.
+	CountryIso2   string `protobuf:"bytes,1,opt,name=country_iso2,json=countryIso2,proto3" json:"country_iso2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListByCountryRequest) Reset() {
+	*x = ListByCountryRequest{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListByCountryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListByCountryRequest) ProtoMessage() {}
+
+func (x *ListByCountryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListByCountryRequest.ProtoReflect.Descriptor instead.
+func (*ListByCountryRequest) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListByCountryRequest) GetCountryIso2() string {
+	if x != nil {
+		return x.CountryIso2
+	}
+	return ""
+}
+
+type ListByCountryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CountryIso2   string                 `protobuf:"bytes,1,opt,name=country_iso2,json=countryIso2,proto3" json:"country_iso2,omitempty"`
+	CountryName   string                 `protobuf:"bytes,2,opt,name=country_name,json=countryName,proto3" json:"country_name,omitempty"`
+	SwiftCodes    []*SwiftCode           `protobuf:"bytes,3,rep,name=swift_codes,json=swiftCodes,proto3" json:"swift_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListByCountryResponse) Reset() {
+	*x = ListByCountryResponse{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListByCountryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListByCountryResponse) ProtoMessage() {}
+
+func (x *ListByCountryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListByCountryResponse.ProtoReflect.Descriptor instead.
+func (*ListByCountryResponse) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListByCountryResponse) GetCountryIso2() string {
+	if x != nil {
+		return x.CountryIso2
+	}
+	return ""
+}
+
+func (x *ListByCountryResponse) GetCountryName() string {
+	if x != nil {
+		return x.CountryName
+	}
+	return ""
+}
+
+func (x *ListByCountryResponse) GetSwiftCodes() []*SwiftCode {
+	if x != nil {
+		return x.SwiftCodes
+	}
+	return nil
+}
+
+type StreamSwiftCodesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Restricts the stream to one country when set.
+	CountryIso2   string `protobuf:"bytes,1,opt,name=country_iso2,json=countryIso2,proto3" json:"country_iso2,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSwiftCodesRequest) Reset() {
+	*x = StreamSwiftCodesRequest{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSwiftCodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSwiftCodesRequest) ProtoMessage() {}
+
+func (x *StreamSwiftCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSwiftCodesRequest.ProtoReflect.Descriptor instead.
+func (*StreamSwiftCodesRequest) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamSwiftCodesRequest) GetCountryIso2() string {
+	if x != nil {
+		return x.CountryIso2
+	}
+	return ""
+}
+
+type AddSwiftCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SwiftCode     *SwiftCode             `protobuf:"bytes,1,opt,name=swift_code,json=swiftCode,proto3" json:"swift_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSwiftCodeRequest) Reset() {
+	*x = AddSwiftCodeRequest{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSwiftCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSwiftCodeRequest) ProtoMessage() {}
+
+func (x *AddSwiftCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSwiftCodeRequest.ProtoReflect.Descriptor instead.
+func (*AddSwiftCodeRequest) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AddSwiftCodeRequest) GetSwiftCode() *SwiftCode {
+	if x != nil {
+		return x.SwiftCode
+	}
+	return nil
+}
+
+type AddSwiftCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddSwiftCodeResponse) Reset() {
+	*x = AddSwiftCodeResponse{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddSwiftCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddSwiftCodeResponse) ProtoMessage() {}
+
+func (x *AddSwiftCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddSwiftCodeResponse.ProtoReflect.Descriptor instead.
+func (*AddSwiftCodeResponse) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{7}
+}
+
+type DeleteSwiftCodeRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SwiftCode string                 `protobuf:"bytes,1,opt,name=swift_code,json=swiftCode,proto3" json:"swift_code,omitempty"`
+	// Makes the delete fail with ABORTED unless the record is still at this
+	// revision.
+	ExpectedRevision *int64 `protobuf:"varint,2,opt,name=expected_revision,json=expectedRevision,proto3,oneof" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeleteSwiftCodeRequest) Reset() {
+	*x = DeleteSwiftCodeRequest{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSwiftCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSwiftCodeRequest) ProtoMessage() {}
+
+func (x *DeleteSwiftCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSwiftCodeRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSwiftCodeRequest) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteSwiftCodeRequest) GetSwiftCode() string {
+	if x != nil {
+		return x.SwiftCode
+	}
+	return ""
+}
+
+func (x *DeleteSwiftCodeRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type DeleteSwiftCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSwiftCodeResponse) Reset() {
+	*x = DeleteSwiftCodeResponse{}
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSwiftCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSwiftCodeResponse) ProtoMessage() {}
+
+func (x *DeleteSwiftCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_swiftcodes_v1_swift_codes_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSwiftCodeResponse.ProtoReflect.Descriptor instead.
+func (*DeleteSwiftCodeResponse) Descriptor() ([]byte, []int) {
+	return file_swiftcodes_v1_swift_codes_proto_rawDescGZIP(), []int{9}
+}
+
+var File_swiftcodes_v1_swift_codes_proto protoreflect.FileDescriptor
+
+const file_swiftcodes_v1_swift_codes_proto_rawDesc = "" +
+	"\n" +
+	"\x1fswiftcodes/v1/swift_codes.proto\x12\rswiftcodes.v1\"\xea\x01\n" +
+	"\tSwiftCode\x12\x1d\n" +
+	"\n" +
+	"swift_code\x18\x01 \x01(\tR\tswiftCode\x12%\n" +
+	"\x0eis_headquarter\x18\x02 \x01(\bR\risHeadquarter\x12\x1b\n" +
+	"\tbank_name\x18\x03 \x01(\tR\bbankName\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12!\n" +
+	"\fcountry_iso2\x18\x05 \x01(\tR\vcountryIso2\x12!\n" +
+	"\fcountry_name\x18\x06 \x01(\tR\vcountryName\x12\x1a\n" +
+	"\brevision\x18\a \x01(\x03R\brevision\"4\n" +
+	"\x13GetSwiftCodeRequest\x12\x1d\n" +
+	"\n" +
+	"swift_code\x18\x01 \x01(\tR\tswiftCode\"\x85\x01\n" +
+	"\x14GetSwiftCodeResponse\x127\n" +
+	"\n" +
+	"swift_code\x18\x01 \x01(\v2\x18.swiftcodes.v1.SwiftCodeR\tswiftCode\x124\n" +
+	"\bbranches\x18\x02 \x03(\v2\x18.swiftcodes.v1.SwiftCodeR\bbranches\"9\n" +
+	"\x14ListByCountryRequest\x12!\n" +
+	"\fcountry_iso2\x18\x01 \x01(\tR\vcountryIso2\"\x98\x01\n" +
+	"\x15ListByCountryResponse\x12!\n" +
+	"\fcountry_iso2\x18\x01 \x01(\tR\vcountryIso2\x12!\n" +
+	"\fcountry_name\x18\x02 \x01(\tR\vcountryName\x129\n" +
+	"\vswift_codes\x18\x03 \x03(\v2\x18.swiftcodes.v1.SwiftCodeR\n" +
+	"swiftCodes\"<\n" +
+	"\x17StreamSwiftCodesRequest\x12!\n" +
+	"\fcountry_iso2\x18\x01 \x01(\tR\vcountryIso2\"N\n" +
+	"\x13AddSwiftCodeRequest\x127\n" +
+	"\n" +
+	"swift_code\x18\x01 \x01(\v2\x18.swiftcodes.v1.SwiftCodeR\tswiftCode\"\x16\n" +
+	"\x14AddSwiftCodeResponse\"\x7f\n" +
+	"\x16DeleteSwiftCodeRequest\x12\x1d\n" +
+	"\n" +
+	"swift_code\x18\x01 \x01(\tR\tswiftCode\x120\n" +
+	"\x11expected_revision\x18\x02 \x01(\x03H\x00R\x10expectedRevision\x88\x01\x01B\x14\n" +
+	"\x12_expected_revision\"\x19\n" +
+	"\x17DeleteSwiftCodeResponse2\xd4\x03\n" +
+	"\n" +
+	"SwiftCodes\x12W\n" +
+	"\fGetSwiftCode\x12\".swiftcodes.v1.GetSwiftCodeRequest\x1a#.swiftcodes.v1.GetSwiftCodeResponse\x12Z\n" +
+	"\rListByCountry\x12#.swiftcodes.v1.ListByCountryRequest\x1a$.swiftcodes.v1.ListByCountryResponse\x12V\n" +
+	"\x10StreamSwiftCodes\x12&.swiftcodes.v1.StreamSwiftCodesRequest\x1a\x18.swiftcodes.v1.SwiftCode0\x01\x12W\n" +
+	"\fAddSwiftCode\x12\".swiftcodes.v1.AddSwiftCodeRequest\x1a#.swiftcodes.v1.AddSwiftCodeResponse\x12`\n" +
+	"\x0fDeleteSwiftCode\x12%.swiftcodes.v1.DeleteSwiftCodeRequest\x1a&.swiftcodes.v1.DeleteSwiftCodeResponseB6Z4swift-codes-api/api/proto/swiftcodes/v1;swiftcodesv1b\x06proto3"
+
+var (
+	file_swiftcodes_v1_swift_codes_proto_rawDescOnce sync.Once
+	file_swiftcodes_v1_swift_codes_proto_rawDescData []byte
+)
+
+func file_swiftcodes_v1_swift_codes_proto_rawDescGZIP() []byte {
+	file_swiftcodes_v1_swift_codes_proto_rawDescOnce.Do(func() {
+		file_swiftcodes_v1_swift_codes_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_swiftcodes_v1_swift_codes_proto_rawDesc), len(file_swiftcodes_v1_swift_codes_proto_rawDesc)))
+	})
+	return file_swiftcodes_v1_swift_codes_proto_rawDescData
+}
+
+var file_swiftcodes_v1_swift_codes_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_swiftcodes_v1_swift_codes_proto_goTypes = []any{
+	(*SwiftCode)(nil),               // 0: swiftcodes.v1.SwiftCode
+	(*GetSwiftCodeRequest)(nil),     // 1: swiftcodes.v1.GetSwiftCodeRequest
+	(*GetSwiftCodeResponse)(nil),    // 2: swiftcodes.v1.GetSwiftCodeResponse
+	(*ListByCountryRequest)(nil),    // 3: swiftcodes.v1.ListByCountryRequest
+	(*ListByCountryResponse)(nil),   // 4: swiftcodes.v1.ListByCountryResponse
+	(*StreamSwiftCodesRequest)(nil), // 5: swiftcodes.v1.StreamSwiftCodesRequest
+	(*AddSwiftCodeRequest)(nil),     // 6: swiftcodes.v1.AddSwiftCodeRequest
+	(*AddSwiftCodeResponse)(nil),    // 7: swiftcodes.v1.AddSwiftCodeResponse
+	(*DeleteSwiftCodeRequest)(nil),  // 8: swiftcodes.v1.DeleteSwiftCodeRequest
+	(*DeleteSwiftCodeResponse)(nil), // 9: swiftcodes.v1.DeleteSwiftCodeResponse
+}
+var file_swiftcodes_v1_swift_codes_proto_depIdxs = []int32{
+	0, // 0: swiftcodes.v1.GetSwiftCodeResponse.swift_code:type_name -> swiftcodes.v1.SwiftCode
+	0, // 1: swiftcodes.v1.GetSwiftCodeResponse.branches:type_name -> swiftcodes.v1.SwiftCode
+	0, // 2: swiftcodes.v1.ListByCountryResponse.swift_codes:type_name -> swiftcodes.v1.SwiftCode
+	0, // 3: swiftcodes.v1.AddSwiftCodeRequest.swift_code:type_name -> swiftcodes.v1.SwiftCode
+	1, // 4: swiftcodes.v1.SwiftCodes.GetSwiftCode:input_type -> swiftcodes.v1.GetSwiftCodeRequest
+	3, // 5: swiftcodes.v1.SwiftCodes.ListByCountry:input_type -> swiftcodes.v1.ListByCountryRequest
+	5, // 6: swiftcodes.v1.SwiftCodes.StreamSwiftCodes:input_type -> swiftcodes.v1.StreamSwiftCodesRequest
+	6, // 7: swiftcodes.v1.SwiftCodes.AddSwiftCode:input_type -> swiftcodes.v1.AddSwiftCodeRequest
+	8, // 8: swiftcodes.v1.SwiftCodes.DeleteSwiftCode:input_type -> swiftcodes.v1.DeleteSwiftCodeRequest
+	2, // 9: swiftcodes.v1.SwiftCodes.GetSwiftCode:output_type -> swiftcodes.v1.GetSwiftCodeResponse
+	4, // 10: swiftcodes.v1.SwiftCodes.ListByCountry:output_type -> swiftcodes.v1.ListByCountryResponse
+	0, // 11: swiftcodes.v1.SwiftCodes.StreamSwiftCodes:output_type -> swiftcodes.v1.SwiftCode
+	7, // 12: swiftcodes.v1.SwiftCodes.AddSwiftCode:output_type -> swiftcodes.v1.AddSwiftCodeResponse
+	9, // 13: swiftcodes.v1.SwiftCodes.DeleteSwiftCode:output_type -> swiftcodes.v1.DeleteSwiftCodeResponse
+	9, // [9:14] is the sub-list for method output_type
+	4, // [4:9] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_swiftcodes_v1_swift_codes_proto_init() }
+func file_swiftcodes_v1_swift_codes_proto_init() {
+	if File_swiftcodes_v1_swift_codes_proto != nil {
+		return
+	}
+	file_swiftcodes_v1_swift_codes_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_swiftcodes_v1_swift_codes_proto_rawDesc), len(file_swiftcodes_v1_swift_codes_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_swiftcodes_v1_swift_codes_proto_goTypes,
+		DependencyIndexes: file_swiftcodes_v1_swift_codes_proto_depIdxs,
+		MessageInfos:      file_swiftcodes_v1_swift_codes_proto_msgTypes,
+	}.Build()
+	File_swiftcodes_v1_swift_codes_proto = out.File
+	file_swiftcodes_v1_swift_codes_proto_goTypes = nil
+	file_swiftcodes_v1_swift_codes_proto_depIdxs = nil
+}
@@ -0,0 +1,291 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: swiftcodes/v1/swift_codes.proto
+
+package swiftcodesv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SwiftCodes_GetSwiftCode_FullMethodName     = "/swiftcodes.v1.SwiftCodes/GetSwiftCode"
+	SwiftCodes_ListByCountry_FullMethodName    = "/swiftcodes.v1.SwiftCodes/ListByCountry"
+	SwiftCodes_StreamSwiftCodes_FullMethodName = "/swiftcodes.v1.SwiftCodes/StreamSwiftCodes"
+	SwiftCodes_AddSwiftCode_FullMethodName     = "/swiftcodes.v1.SwiftCodes/AddSwiftCode"
+	SwiftCodes_DeleteSwiftCode_FullMethodName  = "/swiftcodes.v1.SwiftCodes/DeleteSwiftCode"
+)
+
+// SwiftCodesClient is the client API for SwiftCodes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SwiftCodes mirrors the /v1/swift-codes REST operations. Errors carry the
+// REST problem code as the reason of a google.rpc.ErrorInfo detail, and
+// validation failures a google.rpc.BadRequest detail listing every field.
+type SwiftCodesClient interface {
+	// GetSwiftCode looks up a SWIFT code; headquarters include their branches.
+	GetSwiftCode(ctx context.Context, in *GetSwiftCodeRequest, opts ...grpc.CallOption) (*GetSwiftCodeResponse, error)
+	ListByCountry(ctx context.Context, in *ListByCountryRequest, opts ...grpc.CallOption) (*ListByCountryResponse, error)
+	// StreamSwiftCodes sends every SWIFT code, or those of one country.
+	StreamSwiftCodes(ctx context.Context, in *StreamSwiftCodesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SwiftCode], error)
+	AddSwiftCode(ctx context.Context, in *AddSwiftCodeRequest, opts ...grpc.CallOption) (*AddSwiftCodeResponse, error)
+	// DeleteSwiftCode soft-deletes a SWIFT code.
+	DeleteSwiftCode(ctx context.Context, in *DeleteSwiftCodeRequest, opts ...grpc.CallOption) (*DeleteSwiftCodeResponse, error)
+}
+
+type swiftCodesClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSwiftCodesClient(cc grpc.ClientConnInterface) SwiftCodesClient {
+	return &swiftCodesClient{cc}
+}
+
+func (c *swiftCodesClient) GetSwiftCode(ctx context.Context, in *GetSwiftCodeRequest, opts ...grpc.CallOption) (*GetSwiftCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSwiftCodeResponse)
+	err := c.cc.Invoke(ctx, SwiftCodes_GetSwiftCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *swiftCodesClient) ListByCountry(ctx context.Context, in *ListByCountryRequest, opts ...grpc.CallOption) (*ListByCountryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListByCountryResponse)
+	err := c.cc.Invoke(ctx, SwiftCodes_ListByCountry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *swiftCodesClient) StreamSwiftCodes(ctx context.Context, in *StreamSwiftCodesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SwiftCode], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &SwiftCodes_ServiceDesc.Streams[0], SwiftCodes_StreamSwiftCodes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSwiftCodesRequest, SwiftCode]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SwiftCodes_StreamSwiftCodesClient = grpc.ServerStreamingClient[SwiftCode]
+
+func (c *swiftCodesClient) AddSwiftCode(ctx context.Context, in *AddSwiftCodeRequest, opts ...grpc.CallOption) (*AddSwiftCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddSwiftCodeResponse)
+	err := c.cc.Invoke(ctx, SwiftCodes_AddSwiftCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *swiftCodesClient) DeleteSwiftCode(ctx context.Context, in *DeleteSwiftCodeRequest, opts ...grpc.CallOption) (*DeleteSwiftCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSwiftCodeResponse)
+	err := c.cc.Invoke(ctx, SwiftCodes_DeleteSwiftCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SwiftCodesServer is the server API for SwiftCodes service.
+// All implementations must embed UnimplementedSwiftCodesServer
+// for forward compatibility.
+//
+// SwiftCodes mirrors the /v1/swift-codes REST operations. Errors carry the
+// REST problem code as the reason of a google.rpc.ErrorInfo detail, and
+// validation failures a google.rpc.BadRequest detail listing every field.
+type SwiftCodesServer interface {
+	// GetSwiftCode looks up a SWIFT code; headquarters include their branches.
+	GetSwiftCode(context.Context, *GetSwiftCodeRequest) (*GetSwiftCodeResponse, error)
+	ListByCountry(context.Context, *ListByCountryRequest) (*ListByCountryResponse, error)
+	// StreamSwiftCodes sends every SWIFT code, or those of one country.
+	StreamSwiftCodes(*StreamSwiftCodesRequest, grpc.ServerStreamingServer[SwiftCode]) error
+	AddSwiftCode(context.Context, *AddSwiftCodeRequest) (*AddSwiftCodeResponse, error)
+	// DeleteSwiftCode soft-deletes a SWIFT code.
+	DeleteSwiftCode(context.Context, *DeleteSwiftCodeRequest) (*DeleteSwiftCodeResponse, error)
+	mustEmbedUnimplementedSwiftCodesServer()
+}
+
+// UnimplementedSwiftCodesServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSwiftCodesServer struct{}
+
+func (UnimplementedSwiftCodesServer) GetSwiftCode(context.Context, *GetSwiftCodeRequest) (*GetSwiftCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSwiftCode not implemented")
+}
+func (UnimplementedSwiftCodesServer) ListByCountry(context.Context, *ListByCountryRequest) (*ListByCountryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListByCountry not implemented")
+}
+func (UnimplementedSwiftCodesServer) StreamSwiftCodes(*StreamSwiftCodesRequest, grpc.ServerStreamingServer[SwiftCode]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSwiftCodes not implemented")
+}
+func (UnimplementedSwiftCodesServer) AddSwiftCode(context.Context, *AddSwiftCodeRequest) (*AddSwiftCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSwiftCode not implemented")
+}
+func (UnimplementedSwiftCodesServer) DeleteSwiftCode(context.Context, *DeleteSwiftCodeRequest) (*DeleteSwiftCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSwiftCode not implemented")
+}
+func (UnimplementedSwiftCodesServer) mustEmbedUnimplementedSwiftCodesServer() {}
+func (UnimplementedSwiftCodesServer) testEmbeddedByValue()                    {}
+
+// UnsafeSwiftCodesServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SwiftCodesServer will
+// result in compilation errors.
+type UnsafeSwiftCodesServer interface {
+	mustEmbedUnimplementedSwiftCodesServer()
+}
+
+func RegisterSwiftCodesServer(s grpc.ServiceRegistrar, srv SwiftCodesServer) {
+	// If the following call pancis, it indicates UnimplementedSwiftCodesServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SwiftCodes_ServiceDesc, srv)
+}
+
+func _SwiftCodes_GetSwiftCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSwiftCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SwiftCodesServer).GetSwiftCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SwiftCodes_GetSwiftCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SwiftCodesServer).GetSwiftCode(ctx, req.(*GetSwiftCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SwiftCodes_ListByCountry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListByCountryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SwiftCodesServer).ListByCountry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SwiftCodes_ListByCountry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SwiftCodesServer).ListByCountry(ctx, req.(*ListByCountryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SwiftCodes_StreamSwiftCodes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSwiftCodesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SwiftCodesServer).StreamSwiftCodes(m, &grpc.GenericServerStream[StreamSwiftCodesRequest, SwiftCode]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type SwiftCodes_StreamSwiftCodesServer = grpc.ServerStreamingServer[SwiftCode]
+
+func _SwiftCodes_AddSwiftCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSwiftCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SwiftCodesServer).AddSwiftCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SwiftCodes_AddSwiftCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SwiftCodesServer).AddSwiftCode(ctx, req.(*AddSwiftCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SwiftCodes_DeleteSwiftCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSwiftCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SwiftCodesServer).DeleteSwiftCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SwiftCodes_DeleteSwiftCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SwiftCodesServer).DeleteSwiftCode(ctx, req.(*DeleteSwiftCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SwiftCodes_ServiceDesc is the grpc.ServiceDesc for SwiftCodes service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SwiftCodes_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "swiftcodes.v1.SwiftCodes",
+	HandlerType: (*SwiftCodesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetSwiftCode",
+			Handler:    _SwiftCodes_GetSwiftCode_Handler,
+		},
+		{
+			MethodName: "ListByCountry",
+			Handler:    _SwiftCodes_ListByCountry_Handler,
+		},
+		{
+			MethodName: "AddSwiftCode",
+			Handler:    _SwiftCodes_AddSwiftCode_Handler,
+		},
+		{
+			MethodName: "DeleteSwiftCode",
+			Handler:    _SwiftCodes_DeleteSwiftCode_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSwiftCodes",
+			Handler:       _SwiftCodes_StreamSwiftCodes_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "swiftcodes/v1/swift_codes.proto",
+}
@@ -0,0 +1,8 @@
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI 3 description of the REST API.
+//
+//go:embed openapi.json
+var Spec []byte
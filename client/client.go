@@ -0,0 +1,207 @@
+// Package client is a Go client for the SWIFT codes API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	apiKey     string
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAPIKey authenticates every request with an API key.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// WithRetries sets how many times a request other than a POST is retried
+// after a 429 or a retryable 5xx response, and the bounds of the exponential
+// backoff between attempts. A Retry-After header sent by the server takes precedence, up to
+// maxBackoff: a request the server asks to retry later fails right away.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// New returns a client for the API served at baseURL, such as
+// "https://swift.example.com".
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: 3,
+		minBackoff: 100 * time.Millisecond,
+		maxBackoff: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RequestOption adjusts a single request.
+type RequestOption func(*http.Request)
+
+// IfRevision makes an update or delete fail with a revision-mismatch error
+// unless the record is still at the given revision.
+func IfRevision(revision int64) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("If-Match", strconv.FormatInt(revision, 10))
+	}
+}
+
+// IncludeDeleted asks for soft-deleted records as well. It requires an
+// administrator's API key.
+func IncludeDeleted() RequestOption {
+	return query("includeDeleted", "true")
+}
+
+// AsOf asks for the state of the records at a point in time.
+func AsOf(t time.Time) RequestOption {
+	return query("asOf", t.UTC().Format(time.RFC3339))
+}
+
+func query(key, value string) RequestOption {
+	return func(req *http.Request) {
+		q := req.URL.Query()
+		q.Set(key, value)
+		req.URL.RawQuery = q.Encode()
+	}
+}
+
+// do sends a request, retrying it when the server is overloaded or failing,
+// and decodes a successful JSON response into out unless out is nil. The
+// caller must close the body of the returned response when out is nil.
+func (c *Client) do(ctx context.Context, method, path string, body any, out any, opts ...RequestOption) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.apiKey != "" {
+			req.Header.Set(apiKeyHeader, c.apiKey)
+		}
+		for _, opt := range opts {
+			opt(req)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode < 300 {
+			if out == nil {
+				return resp, nil
+			}
+			defer resp.Body.Close()
+			return resp, json.NewDecoder(resp.Body).Decode(out)
+		}
+
+		apiErr := newError(resp)
+		if attempt >= c.maxRetries || !retryable(req, resp.StatusCode) {
+			return nil, apiErr
+		}
+
+		wait, ok := c.backoff(attempt, resp.Header.Get("Retry-After"))
+		if !ok {
+			return nil, apiErr
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether a request the server rejected as overloaded or
+// failed may be sent again. POST requests are not, unless If-Match keeps a
+// repeat from being applied twice.
+func retryable(req *http.Request, status int) bool {
+	if req.Method == http.MethodPost && req.Header.Get("If-Match") == "" {
+		return false
+	}
+
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable,
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before the next attempt, reporting false
+// when the server asks for a longer wait than maxBackoff.
+func (c *Client) backoff(attempt int, retryAfter string) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		d := time.Duration(seconds) * time.Second
+		return d, d <= c.maxBackoff
+	}
+	if at, err := http.ParseTime(retryAfter); err == nil {
+		d := max(time.Until(at), 0)
+		return d, d <= c.maxBackoff
+	}
+
+	d := c.minBackoff << attempt
+	if d <= 0 || d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+	// Full jitter keeps clients that failed together from retrying together.
+	return time.Duration(rand.Int64N(int64(d) + 1)), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func drain(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"swift-codes-api/utils"
+)
+
+// Code identifies a kind of API error. Codes are part of the API contract and
+// never change once published, unlike titles and details.
+type Code string
+
+const (
+	InvalidSwiftCode   Code = "invalid-swift-code"
+	InvalidCountryCode Code = "invalid-country-code"
+	MalformedRequest   Code = "malformed-request"
+	ValidationFailed   Code = "validation-failed"
+	InvalidParameter   Code = "invalid-parameter"
+	Forbidden          Code = "forbidden"
+	SwiftCodeNotFound  Code = "swift-code-not-found"
+	CountryNotFound    Code = "country-not-found"
+	HistoryNotFound    Code = "history-not-found"
+	WebhookNotFound    Code = "webhook-not-found"
+	ImportNotFound     Code = "import-not-found"
+	RouteNotFound      Code = "route-not-found"
+	SwiftCodeExists    Code = "swift-code-exists"
+	RevisionMismatch   Code = "revision-mismatch"
+	ImportFinished     Code = "import-finished"
+	FileTooLarge       Code = "file-too-large"
+	RateLimited        Code = "rate-limited"
+	Internal           Code = "internal-error"
+	NotImplemented     Code = "not-implemented"
+	ServiceUnavailable Code = "service-unavailable"
+)
+
+// FieldError tells which field of a request body failed validation and why.
+type FieldError = utils.FieldError
+
+// problem holds the fields of an RFC 7807 problem document the client reads.
+type problem struct {
+	Title  string       `json:"title"`
+	Detail string       `json:"detail"`
+	Code   Code         `json:"code"`
+	Errors []FieldError `json:"errors"`
+}
+
+// Error is an error response of the API. Code holds the API's
+// machine-readable error code; it is empty when the response was not a
+// problem document, for example one sent by a proxy.
+type Error struct {
+	StatusCode int
+	Code       Code
+	Title      string
+	Detail     string
+	RequestID  string
+	Fields     []FieldError
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("swift codes api: %d %s", e.StatusCode, e.Title)
+	if e.Code != "" {
+		msg += " (" + string(e.Code) + ")"
+	}
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// IsCode reports whether err is an API error with the given code.
+func IsCode(err error, code Code) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+func newError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Title:      http.StatusText(resp.StatusCode),
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+
+	var p problem
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if json.Unmarshal(body, &p) == nil && p.Code != "" {
+		apiErr.Code = p.Code
+		apiErr.Title = p.Title
+		apiErr.Detail = p.Detail
+		apiErr.Fields = p.Errors
+	}
+	return apiErr
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"swift-codes-api/dto"
+)
+
+type (
+	SwiftCode = dto.SwiftCodeResponse
+	// Lookup is a looked up SWIFT code. Branches is only set on headquarters.
+	Lookup           = dto.HeadquarterResponse
+	Country          = dto.CountryResponse
+	History          = dto.HistoryResponse
+	SwiftCodeRequest = dto.SwiftCodeRequest
+	LookupResult     = dto.BatchLookupResult
+)
+
+func (c *Client) GetSwiftCode(ctx context.Context, code string, opts ...RequestOption) (*Lookup, error) {
+	var lookup Lookup
+	if _, err := c.do(ctx, http.MethodGet, "/v1/swift-codes/"+url.PathEscape(code), nil, &lookup, opts...); err != nil {
+		return nil, err
+	}
+	return &lookup, nil
+}
+
+func (c *Client) ListByCountry(ctx context.Context, countryISO2 string, opts ...RequestOption) (*Country, error) {
+	var country Country
+	if _, err := c.do(ctx, http.MethodGet, "/v1/swift-codes/country/"+url.PathEscape(countryISO2), nil, &country, opts...); err != nil {
+		return nil, err
+	}
+	return &country, nil
+}
+
+// LookupMany resolves up to 1000 BIC8 or BIC11 codes in one request. The
+// results are in the order of codes.
+func (c *Client) LookupMany(ctx context.Context, codes []string) ([]LookupResult, error) {
+	var response dto.BatchLookupResponse
+	if _, err := c.do(ctx, http.MethodPost, "/v1/swift-codes/lookup", dto.BatchLookupRequest{Codes: codes}, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+func (c *Client) History(ctx context.Context, code string) (*History, error) {
+	var history History
+	if _, err := c.do(ctx, http.MethodGet, "/v1/swift-codes/"+url.PathEscape(code)+"/history", nil, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// Export calls fn for every SWIFT code, or for those of one country when
+// countryISO2 is not empty, streaming them rather than loading the whole
+// directory. It stops at the first error fn returns.
+func (c *Client) Export(ctx context.Context, countryISO2 string, fn func(SwiftCode) error) error {
+	q := url.Values{"format": {"ndjson"}}
+	if countryISO2 != "" {
+		q.Set("country", countryISO2)
+	}
+
+	resp, err := c.do(ctx, http.MethodGet, "/v1/export?"+q.Encode(), nil, nil)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var swiftCode SwiftCode
+		if err := dec.Decode(&swiftCode); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err := fn(swiftCode); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) Add(ctx context.Context, swiftCode SwiftCodeRequest) error {
+	return c.mutate(ctx, http.MethodPost, "/v1/swift-codes", swiftCode)
+}
+
+// Update replaces the bank name, address, country and headquarter flag of a
+// SWIFT code. Pass IfRevision to guard against concurrent changes.
+func (c *Client) Update(ctx context.Context, code string, swiftCode SwiftCodeRequest, opts ...RequestOption) error {
+	return c.mutate(ctx, http.MethodPut, "/v1/swift-codes/"+url.PathEscape(code), swiftCode, opts...)
+}
+
+// Delete soft-deletes a SWIFT code. Pass IfRevision to guard against
+// concurrent changes.
+func (c *Client) Delete(ctx context.Context, code string, opts ...RequestOption) error {
+	return c.mutate(ctx, http.MethodDelete, "/v1/swift-codes/"+url.PathEscape(code), nil, opts...)
+}
+
+func (c *Client) Restore(ctx context.Context, code string) error {
+	return c.mutate(ctx, http.MethodPost, "/v1/swift-codes/"+url.PathEscape(code)+"/restore", nil)
+}
+
+func (c *Client) mutate(ctx context.Context, method, path string, body any, opts ...RequestOption) error {
+	resp, err := c.do(ctx, method, path, body, nil, opts...)
+	if err != nil {
+		return err
+	}
+	drain(resp)
+	return nil
+}
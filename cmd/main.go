@@ -1,12 +1,16 @@
 package main
 
 import (
+	"log"
 	"swift-codes-api/internal/app"
 	"swift-codes-api/internal/config"
 )
 
 func main() {
 	cfg := config.Load()
-	application := app.New(cfg)
+	application, err := app.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
 	app.Start(application)
 }
@@ -0,0 +1,67 @@
+// Command snapshot compiles the directory in the storage backend selected by
+// STORAGE into a snapshot file for read-only replicas. The file is replaced
+// atomically, so replicas serving it pick up the new snapshot on their next
+// check.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"swift-codes-api/internal/app"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/snapshot"
+	"swift-codes-api/models"
+	"time"
+)
+
+func main() {
+	cfg := config.Load()
+	output := flag.String("o", cfg.SnapshotPath, "path of the snapshot file to write")
+	flag.Parse()
+
+	ctx := context.Background()
+	storage, err := app.NewStorage(ctx, cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer storage.Close(ctx)
+
+	var swiftCodes []models.SwiftCode
+	err = storage.SwiftRepo.StreamSwiftCodes(ctx, "", func(swiftCode models.SwiftCode) error {
+		swiftCodes = append(swiftCodes, swiftCode)
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Failed to read the SWIFT codes: %v", err)
+	}
+
+	if err := write(*output, swiftCodes); err != nil {
+		log.Fatalf("Failed to write the snapshot: %v", err)
+	}
+	log.Printf("Wrote %d SWIFT codes to %s", len(swiftCodes), *output)
+}
+
+// write writes the snapshot next to path, then renames it over path.
+func write(path string, swiftCodes []models.SwiftCode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if err := snapshot.Write(file, swiftCodes, time.Now().UTC()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "swift-codes-api/models"
+
+// BatchLookupRequest is the body of a batch lookup: BIC8 or BIC11 codes.
+type BatchLookupRequest struct {
+	Codes []string `json:"codes"`
+}
+
+type LookupStatus string
+
+const (
+	LookupFound    LookupStatus = "found"
+	LookupNotFound LookupStatus = "not-found"
+	LookupInvalid  LookupStatus = "invalid"
+)
+
+// BatchLookupResult is the outcome for one requested code. SwiftCode is the
+// normalised BIC11 the code was looked up as, and Record is only set when it
+// was found.
+type BatchLookupResult struct {
+	Code      string             `json:"code"`
+	SwiftCode string             `json:"swiftCode,omitempty"`
+	Status    LookupStatus       `json:"status"`
+	Record    *SwiftCodeResponse `json:"record,omitempty"`
+}
+
+type BatchLookupResponse struct {
+	Results []BatchLookupResult `json:"results"`
+}
+
+func NewFoundResult(code string, swiftCode models.SwiftCode) BatchLookupResult {
+	record := NewSwiftCodeResponse(swiftCode)
+	return BatchLookupResult{Code: code, SwiftCode: swiftCode.SwiftCode, Status: LookupFound, Record: &record}
+}
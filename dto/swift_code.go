@@ -0,0 +1,145 @@
+// Package dto holds the request and response bodies of the SWIFT code API.
+// They are mapped from the models explicitly, so that a field added to a
+// stored document does not reach clients unless a DTO exposes it.
+package dto
+
+import (
+	"swift-codes-api/models"
+	"time"
+)
+
+// SwiftCodeRequest is the body of create and update requests.
+type SwiftCodeRequest struct {
+	SwiftCode     string `json:"swiftCode"`
+	IsHeadquarter bool   `json:"isHeadquarter"`
+	BankName      string `json:"bankName"`
+	Address       string `json:"address"`
+	CountryISO2   string `json:"countryISO2"`
+	CountryName   string `json:"countryName"`
+}
+
+func (r SwiftCodeRequest) ToModel() models.SwiftCode {
+	return models.SwiftCode{
+		SwiftCode:     r.SwiftCode,
+		IsHeadquarter: r.IsHeadquarter,
+		BankName:      r.BankName,
+		Address:       r.Address,
+		CountryISO2:   r.CountryISO2,
+		CountryName:   r.CountryName,
+	}
+}
+
+type SwiftCodeResponse struct {
+	SwiftCode     string     `json:"swiftCode"`
+	IsHeadquarter bool       `json:"isHeadquarter"`
+	BankName      string     `json:"bankName"`
+	Address       string     `json:"address"`
+	CountryISO2   string     `json:"countryISO2"`
+	CountryName   string     `json:"countryName"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	Revision      int64      `json:"revision,omitempty"`
+}
+
+func NewSwiftCodeResponse(swiftCode models.SwiftCode) SwiftCodeResponse {
+	return SwiftCodeResponse{
+		SwiftCode:     swiftCode.SwiftCode,
+		IsHeadquarter: swiftCode.IsHeadquarter,
+		BankName:      swiftCode.BankName,
+		Address:       swiftCode.Address,
+		CountryISO2:   swiftCode.CountryISO2,
+		CountryName:   swiftCode.CountryName,
+		DeletedAt:     swiftCode.DeletedAt,
+		Revision:      swiftCode.Revision,
+	}
+}
+
+func NewSwiftCodeResponses(swiftCodes []models.SwiftCode) []SwiftCodeResponse {
+	responses := make([]SwiftCodeResponse, len(swiftCodes))
+	for i, swiftCode := range swiftCodes {
+		responses[i] = NewSwiftCodeResponse(swiftCode)
+	}
+	return responses
+}
+
+// BranchResponse is a branch listed under its headquarter. Its country name
+// always equals the headquarter's, so it can be left out.
+type BranchResponse struct {
+	SwiftCode     string     `json:"swiftCode"`
+	IsHeadquarter bool       `json:"isHeadquarter"`
+	BankName      string     `json:"bankName"`
+	Address       string     `json:"address"`
+	CountryISO2   string     `json:"countryISO2"`
+	CountryName   string     `json:"countryName,omitempty"`
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	Revision      int64      `json:"revision,omitempty"`
+}
+
+func NewBranchResponse(branch models.SwiftCode, omitCountryName bool) BranchResponse {
+	response := BranchResponse(NewSwiftCodeResponse(branch))
+	if omitCountryName {
+		response.CountryName = ""
+	}
+	return response
+}
+
+type HeadquarterResponse struct {
+	SwiftCodeResponse
+	Branches []BranchResponse `json:"branches"`
+}
+
+func NewHeadquarterResponse(headquarter models.SwiftCode, branches []models.SwiftCode, omitBranchCountryName bool) HeadquarterResponse {
+	response := HeadquarterResponse{
+		SwiftCodeResponse: NewSwiftCodeResponse(headquarter),
+		Branches:          make([]BranchResponse, len(branches)),
+	}
+	for i, branch := range branches {
+		response.Branches[i] = NewBranchResponse(branch, omitBranchCountryName)
+	}
+	return response
+}
+
+type CountryResponse struct {
+	CountryISO2 string              `json:"countryISO2"`
+	CountryName string              `json:"countryName"`
+	SwiftCodes  []SwiftCodeResponse `json:"swiftCodes"`
+}
+
+func NewCountryResponse(countryISO2, countryName string, swiftCodes []models.SwiftCode) CountryResponse {
+	return CountryResponse{
+		CountryISO2: countryISO2,
+		CountryName: countryName,
+		SwiftCodes:  NewSwiftCodeResponses(swiftCodes),
+	}
+}
+
+type VersionResponse struct {
+	SwiftCode string            `json:"swiftCode"`
+	Version   int               `json:"version"`
+	ValidFrom time.Time         `json:"validFrom"`
+	ValidTo   *time.Time        `json:"validTo"`
+	Deleted   bool              `json:"deleted"`
+	Record    SwiftCodeResponse `json:"record"`
+}
+
+type HistoryResponse struct {
+	SwiftCode string            `json:"swiftCode"`
+	Versions  []VersionResponse `json:"versions"`
+}
+
+func NewHistoryResponse(code string, versions []models.SwiftCodeVersion) HistoryResponse {
+	response := HistoryResponse{
+		SwiftCode: code,
+		Versions:  make([]VersionResponse, len(versions)),
+	}
+	for i, version := range versions {
+		response.Versions[i] = VersionResponse{
+			SwiftCode: version.SwiftCode,
+			Version:   version.Version,
+			ValidFrom: version.ValidFrom,
+			ValidTo:   version.ValidTo,
+			Deleted:   version.Deleted,
+			Record:    NewSwiftCodeResponse(version.Record),
+		}
+	}
+	return response
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"swift-codes-api/models"
+	"time"
+)
+
+const (
+	KindHeadquarter = "headquarter"
+	KindBranch      = "branch"
+)
+
+type CountryV2 struct {
+	ISO2 string `json:"iso2"`
+	Name string `json:"name"`
+}
+
+// SwiftCodeV2 is a SWIFT code as returned by the v2 API. Branches name their
+// headquarter; headquarters list their branches.
+type SwiftCodeV2 struct {
+	SwiftCode   string     `json:"swiftCode"`
+	Kind        string     `json:"kind"`
+	BankName    string     `json:"bankName"`
+	Address     string     `json:"address"`
+	Country     CountryV2  `json:"country"`
+	Headquarter string     `json:"headquarter,omitempty"`
+	Revision    int64      `json:"revision"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
+	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	Branches    []BranchV2 `json:"branches,omitempty"`
+}
+
+// BranchV2 is a branch listed under its headquarter, which it shares the
+// country and the kind with.
+type BranchV2 struct {
+	SwiftCode string     `json:"swiftCode"`
+	BankName  string     `json:"bankName"`
+	Address   string     `json:"address"`
+	Revision  int64      `json:"revision"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+}
+
+func NewSwiftCodeV2(swiftCode models.SwiftCode, branches []models.SwiftCode) SwiftCodeV2 {
+	response := SwiftCodeV2{
+		SwiftCode: swiftCode.SwiftCode,
+		Kind:      KindBranch,
+		BankName:  swiftCode.BankName,
+		Address:   swiftCode.Address,
+		Country:   CountryV2{ISO2: swiftCode.CountryISO2, Name: swiftCode.CountryName},
+		Revision:  swiftCode.Revision,
+		UpdatedAt: timestamp(swiftCode.UpdatedAt),
+		DeletedAt: swiftCode.DeletedAt,
+	}
+
+	if swiftCode.IsHeadquarter {
+		response.Kind = KindHeadquarter
+		for _, branch := range branches {
+			response.Branches = append(response.Branches, BranchV2{
+				SwiftCode: branch.SwiftCode,
+				BankName:  branch.BankName,
+				Address:   branch.Address,
+				Revision:  branch.Revision,
+				UpdatedAt: timestamp(branch.UpdatedAt),
+				DeletedAt: branch.DeletedAt,
+			})
+		}
+	} else if len(swiftCode.SwiftCode) >= 8 {
+		response.Headquarter = swiftCode.SwiftCode[:8] + "XXX"
+	}
+
+	return response
+}
+
+type CountrySwiftCodesV2 struct {
+	Country    CountryV2     `json:"country"`
+	SwiftCodes []SwiftCodeV2 `json:"swiftCodes"`
+}
+
+func NewCountrySwiftCodesV2(countryISO2, countryName string, swiftCodes []models.SwiftCode) CountrySwiftCodesV2 {
+	response := CountrySwiftCodesV2{
+		Country:    CountryV2{ISO2: countryISO2, Name: countryName},
+		SwiftCodes: make([]SwiftCodeV2, len(swiftCodes)),
+	}
+	for i, swiftCode := range swiftCodes {
+		response.SwiftCodes[i] = NewSwiftCodeV2(swiftCode, nil)
+	}
+	return response
+}
+
+func timestamp(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
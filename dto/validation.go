@@ -0,0 +1,64 @@
+package dto
+
+import "swift-codes-api/utils"
+
+// ValidateRequest is the body of a SWIFT code validation.
+type ValidateRequest struct {
+	Code string `json:"code"`
+}
+
+type LengthCheck struct {
+	Valid   bool   `json:"valid"`
+	Message string `json:"message,omitempty"`
+}
+
+type SegmentCheck struct {
+	Segment string `json:"segment"`
+	Value   string `json:"value"`
+	Valid   bool   `json:"valid"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type CountryCheck struct {
+	ISO2   string `json:"iso2"`
+	Exists bool   `json:"exists"`
+}
+
+// DirectoryCheck tells whether a syntactically valid code and the
+// headquarter of its bank are in the directory.
+type DirectoryCheck struct {
+	Found            bool               `json:"found"`
+	Record           *SwiftCodeResponse `json:"record,omitempty"`
+	Headquarter      string             `json:"headquarter"`
+	HeadquarterFound bool               `json:"headquarterFound"`
+}
+
+// ValidationResponse is the verdict on a SWIFT code. Country is omitted when
+// the country segment is not two letters and Directory when the code is not
+// syntactically valid.
+type ValidationResponse struct {
+	Input       string          `json:"input"`
+	SwiftCode   string          `json:"swiftCode"`
+	Valid       bool            `json:"valid"`
+	Length      LengthCheck     `json:"length"`
+	Segments    []SegmentCheck  `json:"segments"`
+	Country     *CountryCheck   `json:"country,omitempty"`
+	Directory   *DirectoryCheck `json:"directory,omitempty"`
+	Suggestions []string        `json:"suggestions"`
+}
+
+func NewValidationResponse(input string, diagnosis utils.SwiftCodeDiagnosis) ValidationResponse {
+	response := ValidationResponse{
+		Input:       input,
+		SwiftCode:   diagnosis.Code,
+		Valid:       diagnosis.Valid,
+		Length:      LengthCheck{Valid: diagnosis.LengthValid, Message: diagnosis.LengthMessage},
+		Segments:    make([]SegmentCheck, len(diagnosis.Segments)),
+		Suggestions: []string{},
+	}
+	for i, s := range diagnosis.Segments {
+		response.Segments[i] = SegmentCheck{Segment: s.Segment, Value: s.Value, Valid: s.Valid, Rule: s.Rule, Message: s.Message}
+	}
+	return response
+}
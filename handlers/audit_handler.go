@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditHandler struct {
+	repo interfaces.AuditRepository
+}
+
+func NewAuditHandler(repo interfaces.AuditRepository) *AuditHandler {
+	return &AuditHandler{
+		repo: repo,
+	}
+}
+
+func (h *AuditHandler) GetAuditEntries(c *gin.Context) {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may read the audit log")
+		return
+	}
+
+	filter := models.AuditFilter{
+		SwiftCode: strings.ToUpper(c.Query("swiftCode")),
+		Actor:     c.Query("actor"),
+		Limit:     defaultAuditLimit,
+	}
+
+	var err error
+	if from := c.Query("from"); from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			problems.Respond(c, problems.InvalidParameter, "from must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			problems.Respond(c, problems.InvalidParameter, "to must be an RFC 3339 timestamp")
+			return
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		filter.Limit, err = strconv.ParseInt(limit, 10, 64)
+		if err != nil || filter.Limit < 1 || filter.Limit > maxAuditLimit {
+			problems.Respond(c, problems.InvalidParameter, "limit must be between 1 and 1000")
+			return
+		}
+	}
+
+	entries, err := h.repo.Find(c.Request.Context(), filter)
+	if err != nil {
+		problems.Respond(c, problems.Internal, "Failed to retrieve audit entries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"swift-codes-api/dto"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/utils"
+)
+
+// maxBatchLookupCodes bounds the codes of one batch lookup.
+const maxBatchLookupCodes = 1000
+
+// LookupSwiftCodes resolves a list of BIC8 or BIC11 codes with a single
+// repository query, reporting for each one, in request order, whether it was
+// found, not found or invalid.
+func (h *SwiftCodesHandler) LookupSwiftCodes(c *gin.Context) {
+	var request dto.BatchLookupRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problems.Respond(c, problems.MalformedRequest, malformedRequestDetail)
+		return
+	}
+
+	switch {
+	case len(request.Codes) == 0:
+		problems.RespondValidation(c, []problems.FieldError{{Field: "codes", Code: problems.FieldRequired, Message: "Must not be empty"}})
+		return
+	case len(request.Codes) > maxBatchLookupCodes:
+		problems.RespondValidation(c, []problems.FieldError{{Field: "codes", Code: problems.FieldFormat, Message: "Must list at most " + strconv.Itoa(maxBatchLookupCodes) + " codes"}})
+		return
+	}
+
+	normalized := make([]string, len(request.Codes))
+	var lookup []string
+	seen := make(map[string]bool)
+	for i, code := range request.Codes {
+		swiftCode, ok := utils.NormalizeSwiftCode(code)
+		if !ok {
+			continue
+		}
+		normalized[i] = swiftCode
+		if !seen[swiftCode] {
+			seen[swiftCode] = true
+			lookup = append(lookup, swiftCode)
+		}
+	}
+
+	found := make(map[string]models.SwiftCode)
+	if len(lookup) > 0 {
+		swiftCodes, err := h.repo.FindByCodes(c.Request.Context(), lookup)
+		if err != nil {
+			respondFailed(c, err, problems.Internal, "Failed to look up SWIFT codes")
+			return
+		}
+		for _, swiftCode := range swiftCodes {
+			found[swiftCode.SwiftCode] = swiftCode
+		}
+	}
+
+	results := make([]dto.BatchLookupResult, len(request.Codes))
+	for i, code := range request.Codes {
+		swiftCode, ok := found[normalized[i]]
+		switch {
+		case normalized[i] == "":
+			results[i] = dto.BatchLookupResult{Code: code, Status: dto.LookupInvalid}
+		case !ok:
+			results[i] = dto.BatchLookupResult{Code: code, SwiftCode: normalized[i], Status: dto.LookupNotFound}
+		default:
+			results[i] = dto.NewFoundResult(code, swiftCode)
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.BatchLookupResponse{Results: results})
+}
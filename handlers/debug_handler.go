@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"expvar"
+	"github.com/gin-gonic/gin"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/problems"
+)
+
+// GetDebugVars serves the runtime metrics published with expvar, which
+// describe the service rather than the directory, to administrators.
+func GetDebugVars(c *gin.Context) {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may read the runtime metrics")
+		return
+	}
+
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
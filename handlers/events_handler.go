@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/utils"
+	"time"
+)
+
+const (
+	eventReplayBatch   = 500
+	eventPollInterval  = 5 * time.Second
+	eventHeartbeatTick = 15 * time.Second
+)
+
+type EventsHandler struct {
+	broker *events.Broker
+}
+
+func NewEventsHandler(broker *events.Broker) *EventsHandler {
+	return &EventsHandler{
+		broker: broker,
+	}
+}
+
+func (h *EventsHandler) StreamEvents(c *gin.Context) {
+	countryISO2 := c.Query("country")
+	if countryISO2 != "" && !utils.ValidateCountryCode(countryISO2) {
+		problems.Respond(c, problems.InvalidCountryCode, invalidCountryCodeDetail)
+		return
+	}
+	filter := events.Filter{CountryISO2: strings.ToUpper(countryISO2)}
+
+	ctx := c.Request.Context()
+
+	lastID, resume, err := lastEventID(c)
+	if err != nil {
+		problems.Respond(c, problems.InvalidParameter, "Last-Event-ID must be a non-negative integer")
+		return
+	}
+
+	sub := h.broker.Subscribe(filter)
+	defer h.broker.Unsubscribe(sub)
+
+	if !resume {
+		if lastID, err = h.broker.LatestID(ctx); err != nil {
+			problems.Respond(c, problems.Internal, "Failed to open event stream")
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	if lastID, err = h.catchUp(ctx, c.Writer, filter, lastID); err != nil {
+		return
+	}
+
+	poll := time.NewTicker(eventPollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(eventHeartbeatTick)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err = writeEvent(c.Writer, event); err != nil {
+				return
+			}
+			lastID = event.ID
+		case <-poll.C:
+			if lastID, err = h.catchUp(ctx, c.Writer, filter, lastID); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// catchUp sends every logged event after lastID, which covers events missed
+// while disconnected and events published by other replicas.
+func (h *EventsHandler) catchUp(ctx context.Context, w gin.ResponseWriter, filter events.Filter, lastID int64) (int64, error) {
+	for {
+		logged, err := h.broker.Replay(ctx, lastID, filter.CountryISO2, eventReplayBatch)
+		if err != nil {
+			return lastID, err
+		}
+
+		for _, event := range logged {
+			if err = writeEvent(w, event); err != nil {
+				return lastID, err
+			}
+			lastID = event.ID
+		}
+		w.Flush()
+
+		if len(logged) < eventReplayBatch {
+			return lastID, nil
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return sse.Encode(w, sse.Event{
+		Id:    strconv.FormatInt(event.ID, 10),
+		Event: event.Type,
+		Data:  string(data),
+	})
+}
+
+func lastEventID(c *gin.Context) (int64, bool, error) {
+	raw := c.GetHeader("Last-Event-ID")
+	if raw == "" {
+		raw = c.Query("lastEventId")
+	}
+	if raw == "" {
+		return 0, false, nil
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, false, fmt.Errorf("invalid event ID %q", raw)
+	}
+	return id, true, nil
+}
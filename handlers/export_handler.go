@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"strings"
+	"swift-codes-api/internal/export"
+	"swift-codes-api/problems"
+	"swift-codes-api/utils"
+)
+
+// ExportSwiftCodes streams the whole directory, or one country of it, in the
+// negotiated format without holding it in memory.
+func (h *SwiftCodesHandler) ExportSwiftCodes(c *gin.Context) {
+	countryISO2 := c.Query("country")
+	if countryISO2 != "" {
+		if !utils.ValidateCountryCode(strings.ToUpper(countryISO2)) {
+			problems.Respond(c, problems.InvalidCountryCode, invalidCountryCodeDetail)
+			return
+		}
+		countryISO2 = strings.ToUpper(countryISO2)
+	}
+
+	format, ok := negotiateFormat(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="swift-codes.`+string(format)+`"`)
+	c.Header("Vary", "Accept")
+	c.Status(http.StatusOK)
+
+	enc := export.NewEncoder(c.Writer, format)
+	err := h.repo.StreamSwiftCodes(c.Request.Context(), countryISO2, enc.Encode)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		respondFailed(c, err, problems.Internal, "Failed to export SWIFT codes")
+		return
+	}
+	// Records have been sent with status 200 already: break the response off
+	// so that clients do not take the partial file for the whole directory.
+	log.Printf("SWIFT code export aborted: %v", err)
+	panic(http.ErrAbortHandler)
+}
+
+// negotiateFormat picks the response format from the format query parameter
+// and the Accept header, responding with 400 when the format is unknown.
+func negotiateFormat(c *gin.Context) (export.Format, bool) {
+	format, err := export.Negotiate(c.Query("format"), c.GetHeader("Accept"))
+	if err != nil {
+		problems.Respond(c, problems.InvalidParameter, "format must be one of json, csv, ndjson or xml")
+		return "", false
+	}
+	return format, true
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+	"net/http"
+	"swift-codes-api/internal/gql"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+)
+
+type GraphQLHandler struct {
+	repo   interfaces.SwiftRepository
+	schema *graphql.Schema
+}
+
+func NewGraphQLHandler(repo interfaces.SwiftRepository) *GraphQLHandler {
+	return &GraphQLHandler{
+		repo:   repo,
+		schema: gql.NewSchema(repo),
+	}
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query executes a GraphQL request. As is usual for GraphQL, errors raised
+// while resolving are reported in the response body with status 200.
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Respond(c, problems.MalformedRequest, "Request body must be a JSON object with a query")
+		return
+	}
+
+	ctx := gql.NewContext(c.Request.Context(), h.repo)
+	c.JSON(http.StatusOK, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime"
+	"net/http"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+)
+
+type ImportsHandler struct {
+	runner  *importer.Runner
+	maxSize int64
+}
+
+func NewImportsHandler(cfg config.Config, runner *importer.Runner) *ImportsHandler {
+	return &ImportsHandler{
+		runner:  runner,
+		maxSize: cfg.ImportMaxSize,
+	}
+}
+
+// CreateImport stores an uploaded directory file and queues a job to import
+// it. The file is sent as the "file" field of a multipart form or as the
+// request body; its format comes from the format query parameter, its media
+// type or its file name.
+func (h *ImportsHandler) CreateImport(c *gin.Context) {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may import SWIFT codes")
+		return
+	}
+
+	if h.maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize)
+	}
+
+	file, name, contentType, ok := uploadedImport(c)
+	if !ok {
+		return
+	}
+
+	format, ok := importer.DetectFormat(contentType, name)
+	if raw := c.Query("format"); raw != "" {
+		format, ok = importer.ParseFormat(raw)
+	}
+	if !ok {
+		problems.Respond(c, problems.InvalidParameter, "format must be one of json, ndjson or csv, and is required when the media type and file name do not tell")
+		return
+	}
+
+	job, err := h.runner.Submit(c.Request.Context(), name, format, file)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		problems.Respond(c, problems.FileTooLarge, fmt.Sprintf("Files of more than %d bytes cannot be imported", tooLarge.Limit))
+		return
+	}
+	if err != nil {
+		respondFailed(c, err, problems.Internal, "Failed to queue the import")
+		return
+	}
+
+	c.Header("Location", "/v1/imports/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *ImportsHandler) GetImport(c *gin.Context) {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may read imports")
+		return
+	}
+
+	job, err := h.runner.Find(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.importFailed(c, err, "Failed to retrieve import")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// CancelImport stops a queued or running import. Rows imported before the
+// cancellation are kept.
+func (h *ImportsHandler) CancelImport(c *gin.Context) {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may cancel imports")
+		return
+	}
+
+	job, err := h.runner.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.importFailed(c, err, "Failed to cancel import")
+		return
+	}
+	if !job.CancelRequested {
+		problems.Respond(c, problems.ImportFinished, "Import "+job.ID+" has already "+job.Status)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *ImportsHandler) importFailed(c *gin.Context, err error, message string) {
+	if errors.Is(err, interfaces.ErrNotFound) {
+		problems.Respond(c, problems.ImportNotFound, "No import "+c.Param("id")+" exists")
+		return
+	}
+	respondFailed(c, err, problems.Internal, message)
+}
+
+// uploadedImport returns the uploaded file with its name and media type,
+// writing an error response and returning false when there is none.
+func uploadedImport(c *gin.Context) (io.Reader, string, string, bool) {
+	contentType := c.GetHeader("Content-Type")
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "multipart/form-data" {
+		return c.Request.Body, "import", contentType, true
+	}
+
+	part, ok := multipartFile(c)
+	if !ok {
+		return nil, "", "", false
+	}
+	name := "import"
+	if part.FileName() != "" {
+		name = sanitizeFileName(part.FileName())
+	}
+	return part, name, part.Header.Get("Content-Type"), true
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"swift-codes-api/internal/screening"
+	"swift-codes-api/problems"
+	"unicode/utf8"
+)
+
+const defaultScreeningColumn = "bic"
+
+// ScreenBeneficiaries returns an uploaded CSV file with columns appended that
+// tell whether the BIC of each row is valid and which bank it belongs to. The
+// file is sent as the "file" field of a multipart form or as a text/csv body,
+// and is streamed back without being held in memory.
+func (h *SwiftCodesHandler) ScreenBeneficiaries(c *gin.Context) {
+	column := c.DefaultQuery("column", defaultScreeningColumn)
+
+	comma := ','
+	if delimiter := c.Query("delimiter"); delimiter != "" {
+		r, size := utf8.DecodeRuneInString(delimiter)
+		if size != len(delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+			problems.Respond(c, problems.InvalidParameter, "delimiter must be a single character other than a quote or line break")
+			return
+		}
+		comma = r
+	}
+
+	file, name, ok := uploadedCSV(c)
+	if !ok {
+		return
+	}
+
+	screener, err := screening.New(file, screening.Options{Column: column, Comma: comma})
+	var columnErr *screening.ColumnError
+	switch {
+	case errors.As(err, &columnErr):
+		problems.Respond(c, problems.InvalidParameter, "Column "+column+" is not in the CSV header; set the column parameter to the header of the BIC column")
+		return
+	case errors.Is(err, screening.ErrEmptyFile):
+		problems.Respond(c, problems.MalformedRequest, "The CSV file is empty")
+		return
+	case err != nil:
+		problems.Respond(c, problems.MalformedRequest, "The CSV file could not be read: "+err.Error())
+		return
+	}
+
+	// The rest of the upload is read while the annotated rows are written.
+	_ = http.NewResponseController(c.Writer).EnableFullDuplex()
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+name+`"`)
+	c.Status(http.StatusOK)
+
+	err = screener.Run(c.Request.Context(), h.repo.FindByCodes, c.Writer)
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		c.Writer.Header().Del("Content-Disposition")
+		respondFailed(c, err, problems.Internal, "Failed to screen the CSV file")
+		return
+	}
+	// Rows have been sent with status 200 already: break the response off so
+	// that clients do not take the partial file for the whole of it.
+	log.Printf("Screening aborted: %v", err)
+	panic(http.ErrAbortHandler)
+}
+
+// uploadedCSV returns the uploaded file and the name to send it back under,
+// writing an error response and returning false when there is none.
+func uploadedCSV(c *gin.Context) (io.Reader, string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return c.Request.Body, "screening.csv", true
+	case "multipart/form-data":
+	default:
+		problems.Respond(c, problems.MalformedRequest, "Send the CSV file as the file field of a multipart form or as a text/csv body")
+		return nil, "", false
+	}
+
+	part, ok := multipartFile(c)
+	if !ok {
+		return nil, "", false
+	}
+	name := "screening.csv"
+	if part.FileName() != "" {
+		name = "screened-" + sanitizeFileName(part.FileName())
+	}
+	return part, name, true
+}
+
+// multipartFile returns the "file" field of a multipart form, writing an error
+// response and returning false when there is none.
+func multipartFile(c *gin.Context) (*multipart.Part, bool) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		problems.Respond(c, problems.MalformedRequest, "Malformed multipart form")
+		return nil, false
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			problems.Respond(c, problems.MalformedRequest, "The multipart form has no file field")
+			return nil, false
+		}
+		if part.FormName() == "file" {
+			return part, true
+		}
+	}
+}
+
+// sanitizeFileName keeps a file name safe to quote in Content-Disposition.
+func sanitizeFileName(name string) string {
+	safe := make([]rune, 0, len(name))
+	for _, r := range name {
+		if r < 0x20 || r == '"' || r == '\\' || r == '/' || r >= 0x7f {
+			r = '_'
+		}
+		safe = append(safe, r)
+	}
+	return string(safe)
+}
@@ -2,162 +2,428 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"swift-codes-api/dto"
 	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/export"
 	"swift-codes-api/models"
+	"swift-codes-api/problems"
 	"swift-codes-api/repositories/interfaces"
 	"swift-codes-api/utils"
 )
 
+const (
+	invalidSwiftCodeDetail   = utils.InvalidSwiftCodeMessage
+	invalidCountryCodeDetail = utils.InvalidCountryCodeMessage
+	malformedRequestDetail   = "Request body must be a valid JSON object"
+	revisionMismatchDetail   = "SWIFT code has been modified since it was retrieved"
+)
+
 type SwiftCodesHandler struct {
-	cfg  config.Config
-	repo interfaces.SwiftRepository
+	cfg      config.Config
+	repo     interfaces.SwiftRepository
+	versions interfaces.VersionRepository
+}
+
+type SwiftHandlerOption func(*SwiftCodesHandler)
+
+func WithVersions(versions interfaces.VersionRepository) SwiftHandlerOption {
+	return func(h *SwiftCodesHandler) {
+		h.versions = versions
+	}
 }
 
-func NewSwiftHandler(cfg config.Config, repo interfaces.SwiftRepository) *SwiftCodesHandler {
-	return &SwiftCodesHandler{
+func NewSwiftHandler(cfg config.Config, repo interfaces.SwiftRepository, opts ...SwiftHandlerOption) *SwiftCodesHandler {
+	h := &SwiftCodesHandler{
 		cfg:  cfg,
 		repo: repo,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *SwiftCodesHandler) GetSwiftCode(c *gin.Context) {
+	ctx, reader, result, ok := h.findSwiftCode(c)
+	if !ok {
+		return
+	}
+
+	branches := branchesOf(ctx, reader, result)
+	setLastModified(c, *result)
+	c.JSON(http.StatusOK, h.lookupResponse(result, branches))
+}
+
+func (h *SwiftCodesHandler) GetSwiftCodeV2(c *gin.Context) {
+	ctx, reader, result, ok := h.findSwiftCode(c)
+	if !ok {
+		return
+	}
+
+	branches := branchesOf(ctx, reader, result)
+	setLastModified(c, *result)
+	c.JSON(http.StatusOK, dto.NewSwiftCodeV2(*result, branches))
+}
+
+func (h *SwiftCodesHandler) findSwiftCode(c *gin.Context) (context.Context, swiftReader, *models.SwiftCode, bool) {
 	code := c.Param("swift-code")
 
 	if !utils.ValidateSwiftCode(code) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid SWIFT code format.",
-		})
-		return
+		problems.Respond(c, problems.InvalidSwiftCode, invalidSwiftCodeDetail)
+		return nil, nil, nil, false
 	}
 
-	result, err := h.repo.FindByCode(context.TODO(), code)
+	ctx, reader, ok := h.reader(c)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	result, err := reader.FindByCode(ctx, code)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": "SWIFT code not found"})
-		return
-	}
-
-	if result.IsHeadquarter {
-		branches, err := h.repo.FindBranchesByPrefix(context.TODO(), result.SwiftPrefix)
-		if err == nil {
-			c.JSON(http.StatusOK, gin.H{
-				"address":       result.Address,
-				"bankName":      result.BankName,
-				"countryISO2":   result.CountryISO2,
-				"countryName":   result.CountryName,
-				"isHeadquarter": result.IsHeadquarter,
-				"swiftCode":     result.SwiftCode,
-				"branches":      branches,
-			})
-			return
-		}
+		respondFailed(c, err, problems.SwiftCodeNotFound, "No SWIFT code "+code+" exists")
+		return nil, nil, nil, false
 	}
+	return ctx, reader, result, true
+}
 
-	c.JSON(http.StatusOK, result)
+// lookupResponse builds the body GetSwiftCode returns for a record and the
+// branches listed by branchesOf.
+func (h *SwiftCodesHandler) lookupResponse(result *models.SwiftCode, branches []models.SwiftCode) any {
+	if branches == nil {
+		return dto.NewSwiftCodeResponse(*result)
+	}
+	return dto.NewHeadquarterResponse(*result, branches, h.cfg.OmitBranchCountryName)
+}
+
+// branchesOf lists the branches of a headquarter. It returns nil for a
+// branch, and for a headquarter whose branches could not be listed.
+func branchesOf(ctx context.Context, reader swiftReader, result *models.SwiftCode) []models.SwiftCode {
+	if !result.IsHeadquarter {
+		return nil
+	}
+	branches, err := reader.FindBranchesByPrefix(ctx, result.SwiftPrefix)
+	if err != nil {
+		return nil
+	}
+	if branches == nil {
+		branches = []models.SwiftCode{}
+	}
+	return branches
 }
 
 func (h *SwiftCodesHandler) GetSwiftCodesByCountry(c *gin.Context) {
 	countryISO2 := c.Param("countryISO2code")
 
-	log.Println(countryISO2)
+	if !utils.ValidateCountryCode(countryISO2) {
+		problems.Respond(c, problems.InvalidCountryCode, invalidCountryCodeDetail)
+		return
+	}
+
+	countryISO2 = strings.ToUpper(countryISO2)
+
+	format, ok := negotiateFormat(c)
+	if !ok {
+		return
+	}
+
+	swiftCodes, countryName, ok := h.findCountry(c, countryISO2)
+	if !ok {
+		return
+	}
+
+	c.Header("Vary", "Accept")
+
+	if format != export.FormatJSON {
+		c.Header("Content-Type", format.ContentType())
+		c.Status(http.StatusOK)
+
+		enc := export.NewEncoder(c.Writer, format)
+		for _, swiftCode := range swiftCodes {
+			if err := enc.Encode(swiftCode); err != nil {
+				log.Printf("Failed to encode SWIFT codes of %s as %s: %v", countryISO2, format, err)
+				return
+			}
+		}
+		if err := enc.Close(); err != nil {
+			log.Printf("Failed to encode SWIFT codes of %s as %s: %v", countryISO2, format, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewCountryResponse(countryISO2, countryName, swiftCodes))
+}
+
+func (h *SwiftCodesHandler) GetSwiftCodesByCountryV2(c *gin.Context) {
+	countryISO2 := c.Param("countryISO2code")
 
 	if !utils.ValidateCountryCode(countryISO2) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid country code format. Must be a 2-letter ISO country code",
-		})
+		problems.Respond(c, problems.InvalidCountryCode, invalidCountryCodeDetail)
 		return
 	}
 
 	countryISO2 = strings.ToUpper(countryISO2)
 
-	swiftCodes, countryName, err := h.repo.FindByCountryISO2(context.TODO(), countryISO2)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": "No SWIFT codes found for this country"})
+	swiftCodes, countryName, ok := h.findCountry(c, countryISO2)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"countryISO2": countryISO2,
-		"countryName": countryName,
-		"swiftCodes":  swiftCodes,
-	})
+	c.JSON(http.StatusOK, dto.NewCountrySwiftCodesV2(countryISO2, countryName, swiftCodes))
+}
+
+func (h *SwiftCodesHandler) findCountry(c *gin.Context, countryISO2 string) ([]models.SwiftCode, string, bool) {
+	ctx, reader, ok := h.reader(c)
+	if !ok {
+		return nil, "", false
+	}
+
+	swiftCodes, countryName, err := reader.FindByCountryISO2(ctx, countryISO2)
+	if err != nil {
+		respondFailed(c, err, problems.CountryNotFound, "No SWIFT codes found for country "+countryISO2)
+		return nil, "", false
+	}
+	return swiftCodes, countryName, true
 }
 
 func (h *SwiftCodesHandler) AddSwiftCode(c *gin.Context) {
-	var swiftCode models.SwiftCode
+	var request dto.SwiftCodeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problems.Respond(c, problems.MalformedRequest, malformedRequestDetail)
+		return
+	}
+	swiftCode := request.ToModel()
+
+	if !validateSwiftCodeRecord(c, &swiftCode) {
+		return
+	}
+
+	err := h.repo.AddSwiftCode(c.Request.Context(), swiftCode)
+	if err != nil {
+		respondFailed(c, err, problems.Internal, "Failed to add SWIFT code")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "SWIFT code added successfully"})
+}
+
+func (h *SwiftCodesHandler) UpdateSwiftCode(c *gin.Context) {
+	code := c.Param("swift-code")
+
+	if !utils.ValidateSwiftCode(code) {
+		problems.Respond(c, problems.InvalidSwiftCode, invalidSwiftCodeDetail)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&swiftCode); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request format"})
+	var request dto.SwiftCodeRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problems.Respond(c, problems.MalformedRequest, malformedRequestDetail)
 		return
 	}
+	swiftCode := request.ToModel()
 
-	if swiftCode.SwiftCode == "" || swiftCode.BankName == "" ||
-		swiftCode.CountryISO2 == "" || swiftCode.CountryName == "" ||
-		swiftCode.Address == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "Missing required fields"})
+	if swiftCode.SwiftCode == "" {
+		swiftCode.SwiftCode = code
+	}
+	if swiftCode.SwiftCode != code {
+		problems.RespondValidation(c, []problems.FieldError{{
+			Field:   "swiftCode",
+			Code:    problems.FieldMismatch,
+			Message: "Must match the SWIFT code in the URL",
+		}})
 		return
 	}
 
-	if !utils.ValidateCountryCode(swiftCode.CountryISO2) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid country code format. Must be a 2-letter ISO country code",
-		})
+	if !validateSwiftCodeRecord(c, &swiftCode) {
 		return
 	}
 
-	swiftCode.CountryISO2 = strings.ToUpper(swiftCode.CountryISO2)
+	ctx := c.Request.Context()
 
-	if !utils.ValidateSwiftCode(swiftCode.SwiftCode) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid SWIFT code format. Must be 11 characters and follow proper format",
-		})
+	current, err := h.repo.FindByCode(ctx, code)
+	if err != nil {
+		respondFailed(c, err, problems.SwiftCodeNotFound, "No SWIFT code "+code+" exists")
 		return
 	}
 
-	if !strings.Contains(swiftCode.SwiftCode, swiftCode.CountryISO2) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Country code in SWIFT code does not match the provided country code",
-		})
+	revision, ok := h.expectedRevision(c, current)
+	if !ok {
 		return
 	}
 
-	err := h.repo.AddSwiftCode(context.TODO(), swiftCode)
+	err = h.repo.UpdateSwiftCode(ctx, swiftCode, revision)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			c.JSON(http.StatusConflict, gin.H{"message": err.Error()})
-			return
+		h.mutationFailed(c, err, "Failed to update SWIFT code")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SWIFT code updated successfully"})
+}
+
+// validateSwiftCodeRecord checks a SWIFT code sent for storage, normalising
+// its country code, and responds with every field that is not acceptable.
+func validateSwiftCodeRecord(c *gin.Context, swiftCode *models.SwiftCode) bool {
+	if errs := utils.ValidateSwiftCodeRecord(swiftCode); len(errs) > 0 {
+		problems.RespondValidation(c, errs)
+		return false
+	}
+	return true
+}
+
+// expectedRevision resolves the If-Match header against the current record.
+// It accepts "*", the record's revision as a bare or quoted number, and the
+// strong ETag of the record's v1 or v2 lookup response. Without the header any
+// revision is accepted; when nothing matches it responds with 412.
+func (h *SwiftCodesHandler) expectedRevision(c *gin.Context, current *models.SwiftCode) (int64, bool) {
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		return interfaces.AnyRevision, true
+	}
+
+	var etags []string
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return interfaces.AnyRevision, true
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to add SWIFT code"})
+
+		if revision, err := strconv.ParseInt(strings.Trim(candidate, `"`), 10, 64); err == nil {
+			if revision == current.Revision {
+				return current.Revision, true
+			}
+			continue
+		}
+
+		if etags == nil {
+			etags = h.lookupETags(c.Request.Context(), current)
+		}
+		if slices.Contains(etags, candidate) {
+			return current.Revision, true
+		}
+	}
+
+	problems.Respond(c, problems.RevisionMismatch, revisionMismatchDetail)
+	return 0, false
+}
+
+// lookupETags returns the ETags of the v1 and v2 lookup responses for a record.
+func (h *SwiftCodesHandler) lookupETags(ctx context.Context, current *models.SwiftCode) []string {
+	branches := branchesOf(ctx, h.repo, current)
+
+	etags := []string{}
+	for _, response := range []any{h.lookupResponse(current, branches), dto.NewSwiftCodeV2(*current, branches)} {
+		if body, err := json.Marshal(response); err == nil {
+			etags = append(etags, utils.ETag(body))
+		}
+	}
+	return etags
+}
+
+func (h *SwiftCodesHandler) mutationFailed(c *gin.Context, err error, message string) {
+	if errors.Is(err, interfaces.ErrNotFound) {
+		problems.Respond(c, problems.SwiftCodeNotFound, "No SWIFT code "+c.Param("swift-code")+" exists")
 		return
 	}
+	respondFailed(c, err, problems.Internal, message)
+}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "SWIFT code added successfully"})
+// respondFailed responds to a failed repository call with code, unless
+// problems.FromError knows the error better.
+func respondFailed(c *gin.Context, err error, code problems.Code, detail string) {
+	code, detail = problems.FromError(err, code, detail)
+	problems.Respond(c, code, detail)
 }
 
 func (h *SwiftCodesHandler) DeleteSwiftCode(c *gin.Context) {
 	code := c.Param("swift-code")
 
 	if !utils.ValidateSwiftCode(code) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid SWIFT code format",
-		})
+		problems.Respond(c, problems.InvalidSwiftCode, invalidSwiftCodeDetail)
 		return
 	}
 
-	_, err := h.repo.FindByCode(context.TODO(), code)
+	current, err := h.repo.FindByCode(c.Request.Context(), code)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"message": "SWIFT code not found"})
+		respondFailed(c, err, problems.SwiftCodeNotFound, "No SWIFT code "+code+" exists")
 		return
 	}
 
-	err = h.repo.DeleteSwiftCode(context.TODO(), code)
+	revision, ok := h.expectedRevision(c, current)
+	if !ok {
+		return
+	}
+
+	err = h.repo.DeleteSwiftCode(c.Request.Context(), code, revision)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to delete SWIFT code"})
+		h.mutationFailed(c, err, "Failed to delete SWIFT code")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "SWIFT code deleted successfully"})
 }
+
+func (h *SwiftCodesHandler) RestoreSwiftCode(c *gin.Context) {
+	code := c.Param("swift-code")
+
+	if !utils.ValidateSwiftCode(code) {
+		problems.Respond(c, problems.InvalidSwiftCode, invalidSwiftCodeDetail)
+		return
+	}
+
+	err := h.repo.RestoreSwiftCode(c.Request.Context(), code)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			problems.Respond(c, problems.SwiftCodeNotFound, "No deleted SWIFT code "+code+" exists")
+			return
+		}
+		respondFailed(c, err, problems.Internal, "Failed to restore SWIFT code")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "SWIFT code restored successfully"})
+}
+
+func (h *SwiftCodesHandler) GetSwiftCodeHistory(c *gin.Context) {
+	code := c.Param("swift-code")
+
+	if !utils.ValidateSwiftCode(code) {
+		problems.Respond(c, problems.InvalidSwiftCode, invalidSwiftCodeDetail)
+		return
+	}
+
+	if h.versions == nil {
+		problems.Respond(c, problems.NotImplemented, "SWIFT code history is not available")
+		return
+	}
+
+	versions, err := h.versions.FindHistory(c.Request.Context(), code)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			problems.Respond(c, problems.HistoryNotFound, "No history found for SWIFT code "+code)
+			return
+		}
+		problems.Respond(c, problems.Internal, "Failed to retrieve SWIFT code history")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewHistoryResponse(code, versions))
+}
+
+// setLastModified sends when a branch was last updated. Headquarters and
+// country listings are only given an ETag: a branch of theirs can be deleted
+// or purged without the latest update of the records left changing.
+func setLastModified(c *gin.Context, record models.SwiftCode) {
+	if record.IsHeadquarter || record.UpdatedAt.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", record.UpdatedAt.UTC().Format(http.TimeFormat))
+}
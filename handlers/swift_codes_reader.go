@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+type swiftReader interface {
+	FindByCode(ctx context.Context, code string) (*models.SwiftCode, error)
+	FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error)
+	FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error)
+}
+
+// asOfReader answers lookups from the version history at a point in time.
+type asOfReader struct {
+	versions interfaces.VersionRepository
+	asOf     time.Time
+}
+
+func (r asOfReader) FindByCode(ctx context.Context, code string) (*models.SwiftCode, error) {
+	return r.versions.FindByCodeAsOf(ctx, code, r.asOf)
+}
+
+func (r asOfReader) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
+	return r.versions.FindBranchesByPrefixAsOf(ctx, prefix, r.asOf)
+}
+
+func (r asOfReader) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
+	return r.versions.FindByCountryISO2AsOf(ctx, countryISO2, r.asOf)
+}
+
+// reader picks the source for a lookup from the asOf and includeDeleted query
+// parameters. It writes an error response and returns false when they are
+// malformed or not permitted for the caller.
+func (h *SwiftCodesHandler) reader(c *gin.Context) (context.Context, swiftReader, bool) {
+	ctx := c.Request.Context()
+
+	if raw := c.Query("asOf"); raw != "" {
+		asOf, ok := parseAsOf(raw)
+		if !ok {
+			problems.Respond(c, problems.InvalidParameter, "asOf must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+			return nil, nil, false
+		}
+		if h.versions == nil {
+			problems.Respond(c, problems.NotImplemented, "Point-in-time queries are not available")
+			return nil, nil, false
+		}
+		return ctx, asOfReader{versions: h.versions, asOf: asOf}, true
+	}
+
+	raw := c.Query("includeDeleted")
+	if raw == "" {
+		return ctx, h.repo, true
+	}
+
+	includeDeleted, err := strconv.ParseBool(raw)
+	if err != nil {
+		problems.Respond(c, problems.InvalidParameter, "includeDeleted must be true or false")
+		return nil, nil, false
+	}
+	if !includeDeleted {
+		return ctx, h.repo, true
+	}
+
+	if !reqctx.IsAdmin(ctx) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may include deleted SWIFT codes")
+		return nil, nil, false
+	}
+
+	return reqctx.WithIncludeDeleted(ctx), h.repo, true
+}
+
+// parseAsOf accepts an RFC 3339 timestamp or a date, which refers to the
+// state at the end of that day in UTC.
+func parseAsOf(raw string) (time.Time, bool) {
+	if asOf, err := time.Parse(time.RFC3339, raw); err == nil {
+		return asOf.UTC(), true
+	}
+
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return day.AddDate(0, 0, 1).Add(-time.Millisecond), true
+}
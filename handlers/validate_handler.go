@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"swift-codes-api/dto"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+)
+
+const (
+	// maxSuggestionDistance is how many typos a suggested code may be away
+	// from the validated one.
+	maxSuggestionDistance = 2
+	maxSuggestions        = 5
+)
+
+// ValidateSwiftCode explains whether a code is acceptable: which segments
+// break which rule, whether its country exists and whether it or its
+// headquarter is in the directory. Codes that are not in the directory come
+// with suggestions of close codes of the same country.
+func (h *SwiftCodesHandler) ValidateSwiftCode(c *gin.Context) {
+	var request dto.ValidateRequest
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		problems.Respond(c, problems.MalformedRequest, malformedRequestDetail)
+		return
+	}
+	if request.Code == "" {
+		problems.RespondValidation(c, []problems.FieldError{{Field: "code", Code: problems.FieldRequired, Message: "Must not be empty"}})
+		return
+	}
+	// Longer inputs cannot be codes, and would make the suggestions costly.
+	if length := len(strings.TrimSpace(request.Code)); length > utils.SwiftCodeLength {
+		problems.RespondValidation(c, []problems.FieldError{{
+			Field:   "code",
+			Code:    problems.FieldFormat,
+			Message: "Must be at most " + strconv.Itoa(utils.SwiftCodeLength) + " characters, got " + strconv.Itoa(length),
+		}})
+		return
+	}
+
+	ctx := c.Request.Context()
+	diagnosis := utils.DiagnoseSwiftCode(request.Code)
+	response := dto.NewValidationResponse(request.Code, diagnosis)
+
+	countryISO2, countryValid := countryOf(diagnosis)
+	if countryValid {
+		response.Country = &dto.CountryCheck{ISO2: countryISO2, Exists: utils.CountryExists(countryISO2)}
+	}
+
+	if diagnosis.Valid {
+		directory, err := h.directoryCheck(ctx, diagnosis.Code)
+		if err != nil {
+			respondFailed(c, err, problems.Internal, "Failed to look up SWIFT codes")
+			return
+		}
+		response.Directory = directory
+	}
+
+	if response.Directory == nil || !response.Directory.Found {
+		if countryValid && response.Country.Exists {
+			suggestions, err := h.suggestions(ctx, diagnosis.Code, countryISO2)
+			if err != nil {
+				respondFailed(c, err, problems.Internal, "Failed to look up SWIFT codes")
+				return
+			}
+			response.Suggestions = suggestions
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func countryOf(diagnosis utils.SwiftCodeDiagnosis) (string, bool) {
+	for _, s := range diagnosis.Segments {
+		if s.Segment == "country" {
+			return s.Value, s.Valid
+		}
+	}
+	return "", false
+}
+
+func (h *SwiftCodesHandler) directoryCheck(ctx context.Context, code string) (*dto.DirectoryCheck, error) {
+	headquarter := code[:8] + "XXX"
+	codes := []string{code}
+	if code != headquarter {
+		codes = append(codes, headquarter)
+	}
+
+	swiftCodes, err := h.repo.FindByCodes(ctx, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &dto.DirectoryCheck{Headquarter: headquarter}
+	for _, swiftCode := range swiftCodes {
+		if swiftCode.SwiftCode == code {
+			record := dto.NewSwiftCodeResponse(swiftCode)
+			check.Found, check.Record = true, &record
+		}
+		if swiftCode.SwiftCode == headquarter {
+			check.HeadquarterFound = true
+		}
+	}
+	return check, nil
+}
+
+func (h *SwiftCodesHandler) suggestions(ctx context.Context, code, countryISO2 string) ([]string, error) {
+	swiftCodes, _, err := h.repo.FindByCountryISO2(ctx, countryISO2)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]string, len(swiftCodes))
+	for i, swiftCode := range swiftCodes {
+		candidates[i] = swiftCode.SwiftCode
+	}
+	return utils.ClosestSwiftCodes(code, candidates, maxSuggestionDistance, maxSuggestions), nil
+}
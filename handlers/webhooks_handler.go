@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/internal/webhooks"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+	"time"
+)
+
+const deliveryLogLimit = 100
+
+var webhookEventTypes = []string{
+	models.EventTypeCreated,
+	models.EventTypeUpdated,
+	models.EventTypeDeleted,
+	models.EventTypeRestored,
+}
+
+type WebhooksHandler struct {
+	repo     interfaces.WebhookRepository
+	resolver webhooks.Resolver
+}
+
+type WebhooksHandlerOption func(*WebhooksHandler)
+
+// WithResolver sets the resolver webhook hosts are checked with, instead of
+// net.DefaultResolver.
+func WithResolver(resolver webhooks.Resolver) WebhooksHandlerOption {
+	return func(h *WebhooksHandler) {
+		h.resolver = resolver
+	}
+}
+
+func NewWebhooksHandler(repo interfaces.WebhookRepository, opts ...WebhooksHandlerOption) *WebhooksHandler {
+	h := &WebhooksHandler{
+		repo:     repo,
+		resolver: net.DefaultResolver,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// requireAdmin responds with a problem and returns false unless the caller
+// is an administrator: webhooks make the server send requests on their
+// behalf.
+func requireAdmin(c *gin.Context) bool {
+	if !reqctx.IsAdmin(c.Request.Context()) {
+		problems.Respond(c, problems.Forbidden, "Only administrators may manage webhooks")
+		return false
+	}
+	return true
+}
+
+type createWebhookRequest struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	CountryISO2 string   `json:"countryISO2"`
+	Secret      string   `json:"secret"`
+}
+
+func (h *WebhooksHandler) CreateWebhook(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	var req createWebhookRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problems.Respond(c, problems.MalformedRequest, malformedRequestDetail)
+		return
+	}
+
+	var errs []problems.FieldError
+
+	target, err := url.Parse(req.URL)
+	if req.URL == "" {
+		errs = append(errs, problems.FieldError{Field: "url", Code: problems.FieldRequired, Message: "Must not be empty"})
+	} else if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		errs = append(errs, problems.FieldError{Field: "url", Code: problems.FieldFormat, Message: "Must be an absolute http or https URL"})
+	} else if err = webhooks.CheckTarget(c.Request.Context(), h.resolver, target); errors.Is(err, webhooks.ErrPrivateTarget) {
+		errs = append(errs, problems.FieldError{Field: "url", Code: problems.FieldFormat, Message: "Must not point to a private, loopback or link-local address"})
+	} else if err != nil {
+		errs = append(errs, problems.FieldError{Field: "url", Code: problems.FieldFormat, Message: "Host could not be resolved"})
+	}
+
+	if len(req.EventTypes) == 0 {
+		req.EventTypes = webhookEventTypes
+	}
+	for i, eventType := range req.EventTypes {
+		if !slices.Contains(webhookEventTypes, eventType) {
+			errs = append(errs, problems.FieldError{
+				Field:   fmt.Sprintf("eventTypes[%d]", i),
+				Code:    problems.FieldFormat,
+				Message: "Unknown event type " + eventType,
+			})
+		}
+	}
+
+	if req.CountryISO2 != "" && !utils.ValidateCountryCode(req.CountryISO2) {
+		errs = append(errs, problems.FieldError{Field: "countryISO2", Code: problems.FieldFormat, Message: invalidCountryCodeDetail})
+	}
+
+	if len(errs) > 0 {
+		problems.RespondValidation(c, errs)
+		return
+	}
+
+	if req.Secret == "" {
+		req.Secret = utils.NewID()
+	}
+
+	subscription := models.WebhookSubscription{
+		ID:          utils.NewID(),
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		CountryISO2: strings.ToUpper(req.CountryISO2),
+		Secret:      req.Secret,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	if err := h.repo.CreateSubscription(c.Request.Context(), subscription); err != nil {
+		problems.Respond(c, problems.Internal, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+func (h *WebhooksHandler) GetWebhooks(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	subscriptions, err := h.repo.FindSubscriptions(c.Request.Context())
+	if err != nil {
+		problems.Respond(c, problems.Internal, "Failed to retrieve webhooks")
+		return
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": subscriptions})
+}
+
+func (h *WebhooksHandler) DeleteWebhook(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	err := h.repo.DeleteSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			problems.Respond(c, problems.WebhookNotFound, "No webhook "+c.Param("id")+" exists")
+			return
+		}
+		problems.Respond(c, problems.Internal, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+func (h *WebhooksHandler) GetWebhookDeliveries(c *gin.Context) {
+	if !requireAdmin(c) {
+		return
+	}
+
+	id := c.Param("id")
+
+	_, err := h.repo.FindSubscription(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrNotFound) {
+			problems.Respond(c, problems.WebhookNotFound, "No webhook "+c.Param("id")+" exists")
+			return
+		}
+		problems.Respond(c, problems.Internal, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	deliveries, err := h.repo.FindDeliveries(c.Request.Context(), id, deliveryLogLimit)
+	if err != nil {
+		problems.Respond(c, problems.Internal, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
@@ -1,39 +1,296 @@
 package app
 
 import (
+	"context"
+	"expvar"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
 	"log"
+	"net"
 	"swift-codes-api/internal/config"
-	"swift-codes-api/internal/db"
+	"swift-codes-api/internal/dropfolder"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/internal/grpcapi"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/purge"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/internal/webhooks"
+	"swift-codes-api/middleware"
+	"swift-codes-api/repositories/audit"
+	"swift-codes-api/repositories/breaker"
+	"swift-codes-api/repositories/cache"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/repositories/publishing"
+	"swift-codes-api/repositories/versioned"
 	"swift-codes-api/routes"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// The metrics published at /debug/vars are those of the latest App, expvar
+// names being registered once per process.
+var (
+	publishStats sync.Once
+	cacheStats   atomic.Pointer[cache.SwiftRepository]
+	breakerStats atomic.Pointer[breaker.SwiftRepository]
 )
 
 type App struct {
-	Config  config.Config
-	Router  *gin.Engine
-	Mongo   *mongo.Client
-	MongoDB *mongo.Database
+	Config     config.Config
+	Router     *gin.Engine
+	GRPC       *grpc.Server
+	Storage    *Storage
+	Logger     *log.Logger
+	SwiftRepo  interfaces.SwiftRepository
+	Dispatcher *webhooks.Dispatcher
+	Importer   *importer.Runner
+	// DropFolder is nil unless DROP_FOLDER is set.
+	DropFolder *dropfolder.Watcher
+	// versions is nil when the storage keeps no versions.
+	versions *versioned.SwiftRepository
 }
 
-func New(cfg config.Config) *App {
-	dbClient := db.Connect(cfg.MongoURI)
-	database := dbClient.Database(cfg.MongoDB)
+// New opens the storage backend and assembles the API on top of it. The
+// features whose repositories the backend lacks are left out: their fields
+// are nil and their routes are not registered.
+func New(cfg config.Config, opts ...Option) (*App, error) {
+	o := options{
+		logger: log.Default(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.storage == nil {
+		o.storage = NewStorage
+		if o.swiftRepo != nil {
+			o.storage = noStorage
+		}
+	}
+
+	r := o.router
+	if r == nil {
+		var err error
+		if r, err = newRouter(cfg, o.logger); err != nil {
+			return nil, err
+		}
+	}
+
+	storage, err := o.storage(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	swiftRepo := storage.SwiftRepo
+	if o.swiftRepo != nil {
+		swiftRepo = o.swiftRepo
+	}
+	var versions *versioned.SwiftRepository
+	if storage.VersionRepo != nil {
+		versions = versioned.NewSwiftRepository(swiftRepo, storage.VersionRepo)
+		swiftRepo = versions
+	}
+	if storage.AuditRepo != nil {
+		swiftRepo = audit.NewSwiftRepository(swiftRepo, storage.AuditRepo)
+	}
 
-	r := gin.Default()
-	routes.SetupRoutes(r, database, cfg)
+	var broker *events.Broker
+	if storage.EventRepo != nil {
+		broker = events.NewBroker(storage.EventRepo)
+		if !watchChanges(storage, broker, o.logger) {
+			swiftRepo = publishing.NewSwiftRepository(swiftRepo, broker)
+		}
+	}
+	publishStats.Do(publishExpvars)
+	cacheStats.Store(nil)
+	breakerStats.Store(nil)
+	if cfg.BreakerFailureRate > 0 && !storage.InMemory {
+		swiftRepo = newBreakerRepository(swiftRepo, cfg)
+	}
+	if cfg.CacheSize > 0 && !storage.InMemory {
+		swiftRepo = newCachedRepository(swiftRepo, cfg)
+	}
+
+	var dispatcher *webhooks.Dispatcher
+	if storage.WebhookRepo != nil && broker != nil {
+		dispatcher = webhooks.NewDispatcher(storage.WebhookRepo, broker, webhooks.NewClient(webhookTimeout))
+	}
+
+	var importRunner *importer.Runner
+	if storage.ImportRepo != nil {
+		importRunner = importer.NewRunner(storage.ImportRepo, swiftRepo, cfg.ImportWorkers)
+	}
+
+	var dropFolder *dropfolder.Watcher
+	if cfg.DropFolder != "" {
+		if storage.DropFolderRepo == nil || importRunner == nil {
+			o.logger.Printf("The storage backend does not support imports, ignoring DROP_FOLDER")
+		} else {
+			dropFolder = dropfolder.NewWatcher(cfg.DropFolder, cfg.DropFolderInterval, storage.DropFolderRepo, importRunner)
+		}
+	}
+
+	// REST and gRPC callers draw from the same budgets.
+	rateLimitStore := storage.RateLimitStore
+	if rateLimitStore == nil {
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+
+	deps := routes.Dependencies{
+		SwiftRepo:      swiftRepo,
+		AuditRepo:      storage.AuditRepo,
+		VersionRepo:    storage.VersionRepo,
+		Broker:         broker,
+		RateLimitStore: rateLimitStore,
+		ImportRunner:   importRunner,
+		Middleware:     o.middleware,
+	}
+	if dispatcher != nil {
+		deps.WebhookRepo = storage.WebhookRepo
+	}
+
+	if o.router != nil {
+		routes.Mount(r.Group(""), deps, cfg)
+	} else {
+		routes.SetupRoutes(r, deps, cfg)
+	}
 
 	return &App{
-		Config:  cfg,
-		Router:  r,
-		Mongo:   dbClient,
-		MongoDB: database,
+		Config:     cfg,
+		Router:     r,
+		GRPC:       grpcapi.NewServer(cfg, swiftRepo, rateLimitStore),
+		Storage:    storage,
+		Logger:     o.logger,
+		SwiftRepo:  swiftRepo,
+		Dispatcher: dispatcher,
+		Importer:   importRunner,
+		DropFolder: dropFolder,
+		versions:   versions,
+	}, nil
+}
+
+// newRouter is gin.Default with the access log written to logger, panics
+// recovered by middleware.Recovery, and only the proxies of cfg trusted to
+// report client IPs.
+func newRouter(cfg config.Config, logger *log.Logger) (*gin.Engine, error) {
+	accessLog := gin.Logger()
+	if logger != log.Default() {
+		accessLog = gin.LoggerWithWriter(logger.Writer())
+	}
+	r := gin.New()
+	r.Use(accessLog, middleware.Recovery(logger))
+
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	return r, nil
+}
+
+// noStorage opens no backend, leaving out every feature but the directory,
+// which is then served by the repository given to WithSwiftRepository.
+func noStorage(context.Context, config.Config) (*Storage, error) {
+	return &Storage{}, nil
+}
+
+// watchChanges feeds the broker from the change feed of the storage when it
+// has one, reporting whether it does.
+func watchChanges(storage *Storage, broker *events.Broker, logger *log.Logger) bool {
+	if storage.WatchChanges == nil {
+		return false
+	}
+
+	err := storage.WatchChanges(context.Background(), broker)
+	if err != nil {
+		logger.Printf("Change streams unavailable, publishing events from the repository: %v", err)
+		return false
 	}
+	return true
+}
+
+func newCachedRepository(repo interfaces.SwiftRepository, cfg config.Config) *cache.SwiftRepository {
+	cached := cache.NewSwiftRepository(repo, cache.Options{
+		Size:        cfg.CacheSize,
+		TTL:         cfg.CacheTTL,
+		NegativeTTL: cfg.CacheNegativeTTL,
+		StaleTTL:    cfg.CacheStaleTTL,
+	})
+
+	cacheStats.Store(cached)
+	return cached
+}
+
+// Close releases the storage backend, if it has anything to release.
+func (a *App) Close(ctx context.Context) error {
+	if a.Storage.Close == nil {
+		return nil
+	}
+	return a.Storage.Close(ctx)
+}
+
+func newBreakerRepository(repo interfaces.SwiftRepository, cfg config.Config) *breaker.SwiftRepository {
+	breaking := breaker.NewSwiftRepository(repo, breaker.Options{
+		FailureRate: cfg.BreakerFailureRate,
+		MinCalls:    cfg.BreakerMinCalls,
+		Window:      cfg.BreakerWindow,
+		OpenTimeout: cfg.BreakerOpenTimeout,
+		Probes:      cfg.BreakerProbes,
+	})
+
+	breakerStats.Store(breaking)
+	return breaking
+}
+
+func publishExpvars() {
+	expvar.Publish("swiftCodesCache", expvar.Func(func() any {
+		if cached := cacheStats.Load(); cached != nil {
+			return cached.Stats()
+		}
+		return nil
+	}))
+	expvar.Publish("circuitBreaker", expvar.Func(func() any {
+		if breaking := breakerStats.Load(); breaking != nil {
+			return breaking.Stats()
+		}
+		return nil
+	}))
 }
 
 func Start(a *App) {
-	err := a.Router.Run(":" + a.Config.Port)
+	purge.Start(context.Background(), a.SwiftRepo, a.Config.SoftDeleteRetention, a.Config.PurgeInterval)
+	if a.versions != nil {
+		go func() {
+			if err := a.versions.Backfill(context.Background()); err != nil {
+				a.Logger.Printf("Failed to backfill the versions of the SWIFT codes: %v", err)
+			}
+		}()
+	}
+	if a.Dispatcher != nil {
+		a.Dispatcher.Start(context.Background())
+	}
+	if a.Importer != nil {
+		a.Importer.Start(context.Background())
+	}
+	if a.DropFolder != nil {
+		if err := a.DropFolder.Start(context.Background()); err != nil {
+			a.Logger.Fatal(err)
+		}
+	}
+
+	lis, err := net.Listen("tcp", ":"+a.Config.GRPCPort)
+	if err != nil {
+		a.Logger.Fatal(err)
+	}
+	go func() {
+		if err := a.GRPC.Serve(lis); err != nil {
+			a.Logger.Fatal(err)
+		}
+	}()
+
+	err = a.Router.Run(":" + a.Config.Port)
 	if err != nil {
-		log.Fatal(err)
+		a.Logger.Fatal(err)
 	}
 }
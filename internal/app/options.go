@@ -0,0 +1,66 @@
+package app
+
+import (
+	"log"
+	"swift-codes-api/repositories/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Option func(*options)
+
+type options struct {
+	storage    StorageFactory
+	swiftRepo  interfaces.SwiftRepository
+	logger     *log.Logger
+	middleware []gin.HandlerFunc
+	router     *gin.Engine
+}
+
+// WithStorage opens the repositories with factory instead of the backend
+// selected by STORAGE.
+func WithStorage(factory StorageFactory) Option {
+	return func(o *options) {
+		o.storage = factory
+	}
+}
+
+// WithSwiftRepository serves the directory from repo instead of the storage's
+// own repository. The audit log, history, events and cache still wrap it.
+// Without WithStorage, no backend is opened and only the directory is served.
+func WithSwiftRepository(repo interfaces.SwiftRepository) Option {
+	return func(o *options) {
+		o.swiftRepo = repo
+	}
+}
+
+// WithLogger sends the access log, the panics recovered while serving
+// requests and the messages of the app itself, such as startup failures, to
+// logger. The handlers and workers it assembles keep logging to the standard
+// logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithMiddleware runs handlers on every request, after request IDs,
+// authentication and rate limiting.
+func WithMiddleware(handlers ...gin.HandlerFunc) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, handlers...)
+	}
+}
+
+// WithRouter registers the routes on router, for instance to serve the API
+// next to those of another service, instead of on a new engine. They are
+// mounted on a group of their own, so the middleware of the API does not run
+// for the other routes of router and unknown routes are left to it. The
+// client IPs rate limits apply to are read as the trusted proxies of router
+// say, rather than TRUSTED_PROXIES, and router keeps its own access log and
+// recovery.
+func WithRouter(router *gin.Engine) Option {
+	return func(o *options) {
+		o.router = router
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/db"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/repositories/interfaces"
+	repos "swift-codes-api/repositories/mongo"
+	"swift-codes-api/repositories/snapshot"
+	"swift-codes-api/repositories/sqlite"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Storage holds the repositories of a backend. Those a backend does not
+// support are nil, which leaves their features out of the app: audit log,
+// history, change events, webhooks, imports and the drop folder.
+type Storage struct {
+	SwiftRepo      interfaces.SwiftRepository
+	AuditRepo      interfaces.AuditRepository
+	VersionRepo    interfaces.VersionRepository
+	EventRepo      interfaces.EventRepository
+	WebhookRepo    interfaces.WebhookRepository
+	ImportRepo     interfaces.ImportRepository
+	DropFolderRepo interfaces.DropFolderRepository
+	// RateLimitStore shares rate limit budgets between replicas, or is nil
+	// to keep them in memory.
+	RateLimitStore ratelimit.Store
+	// WatchChanges feeds the broker from a change feed of the backend. When
+	// it is nil or fails, events are published by the repository instead.
+	WatchChanges func(ctx context.Context, broker *events.Broker) error
+	// Close releases the connection to the backend.
+	Close func(ctx context.Context) error
+	// InMemory backends answer from memory, so they get neither the cache
+	// nor the circuit breaker.
+	InMemory bool
+
+	// MongoDB and SQLite are the databases of the built-in backends.
+	MongoDB *mongo.Database
+	SQLite  *sql.DB
+}
+
+// StorageFactory opens a backend.
+type StorageFactory func(ctx context.Context, cfg config.Config) (*Storage, error)
+
+// NewStorage opens the backend selected by STORAGE.
+func NewStorage(ctx context.Context, cfg config.Config) (*Storage, error) {
+	switch cfg.Storage {
+	case "", "mongo":
+		return newMongoStorage(ctx, cfg)
+	case "sqlite":
+		return newSQLiteStorage(ctx, cfg)
+	case "snapshot":
+		return newSnapshotStorage(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage %q, expected mongo, sqlite or snapshot", cfg.Storage)
+	}
+}
+
+func newMongoStorage(ctx context.Context, cfg config.Config) (*Storage, error) {
+	client, err := db.Connect(ctx, cfg.MongoURI)
+	if err != nil {
+		return nil, err
+	}
+	database := client.Database(cfg.MongoDB)
+
+	storage := &Storage{
+		SwiftRepo:      repos.NewSwiftRepository(database),
+		AuditRepo:      repos.NewAuditRepository(database),
+		VersionRepo:    repos.NewVersionRepository(database),
+		EventRepo:      repos.NewEventRepository(database),
+		WebhookRepo:    repos.NewWebhookRepository(database),
+		ImportRepo:     repos.NewImportRepository(database),
+		DropFolderRepo: repos.NewDropFolderRepository(database),
+		Close:          client.Disconnect,
+		MongoDB:        database,
+	}
+	if cfg.RateLimitStore == "mongo" {
+		storage.RateLimitStore = ratelimit.NewMongoStore(database.Collection("rate-limits"))
+	}
+	if cfg.EventsChangeStream {
+		storage.WatchChanges = func(ctx context.Context, broker *events.Broker) error {
+			return events.WatchChangeStream(ctx, database.Collection("swift-codes"), broker)
+		}
+	}
+	return storage, nil
+}
+
+// newSQLiteStorage keeps the directory, its history, audit log and events in
+// a single file. Webhooks and imports need MongoDB.
+func newSQLiteStorage(_ context.Context, cfg config.Config) (*Storage, error) {
+	database, err := sqlite.Open(cfg.SQLitePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Storage{
+		SwiftRepo:   sqlite.NewSwiftRepository(database),
+		AuditRepo:   sqlite.NewAuditRepository(database),
+		VersionRepo: sqlite.NewVersionRepository(database),
+		EventRepo:   sqlite.NewEventRepository(database),
+		Close: func(context.Context) error {
+			return database.Close()
+		},
+		SQLite: database,
+	}, nil
+}
+
+// newSnapshotStorage serves a read-only replica from a snapshot file, picking
+// up new snapshots as they replace it.
+func newSnapshotStorage(_ context.Context, cfg config.Config) (*Storage, error) {
+	repo, err := snapshot.NewSwiftRepository(cfg.SnapshotPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	repo.Watch(ctx, cfg.SnapshotInterval)
+
+	return &Storage{
+		SwiftRepo: repo,
+		Close: func(context.Context) error {
+			stop()
+			return repo.Close()
+		},
+		InMemory: true,
+	}, nil
+}
@@ -1,20 +1,114 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"swift-codes-api/internal/ratelimit"
+	"time"
 )
 
 type Config struct {
-	Port     string
-	MongoURI string
-	MongoDB  string
+	Port                string
+	GRPCPort            string
+	MongoURI            string
+	MongoDB             string
+	APIKeys             map[string]APIKey
+	SoftDeleteRetention time.Duration
+	PurgeInterval       time.Duration
+	EventsChangeStream  bool
+	CacheSize           int
+	CacheTTL            time.Duration
+	CacheNegativeTTL    time.Duration
+	CacheStaleTTL       time.Duration
+	CacheControlLookup  string
+	CacheControlCountry string
+	CacheControlHistory string
+	SwaggerUI           bool
+	// OmitBranchCountryName leaves the country name, which always equals the
+	// headquarter's, out of the branches listed in v1 lookups.
+	OmitBranchCountryName bool
+	// RateLimitRead and RateLimitWrite are the budgets of each client for
+	// GET requests and for everything else.
+	RateLimitRead  ratelimit.Limit
+	RateLimitWrite ratelimit.Limit
+	// RateLimitStore is "memory" for per-replica budgets or "mongo" to share
+	// them between replicas.
+	RateLimitStore string
+	// ImportWorkers is the number of import jobs run at once by a replica.
+	ImportWorkers int
+	// ImportMaxSize is the largest file accepted for import, in bytes.
+	ImportMaxSize int64
+	// DropFolder is a directory whose files are imported automatically, or
+	// empty to disable it. It is polled every DropFolderInterval, which is
+	// also how long a file must be left unmodified to be picked up.
+	DropFolder         string
+	DropFolderInterval time.Duration
+	// Storage is "mongo", "sqlite" to keep the directory in the single file
+	// at SQLitePath, or "snapshot" to serve it read-only from SnapshotPath.
+	Storage    string
+	SQLitePath string
+	// BreakerFailureRate is the share of failed database calls, over at least
+	// BreakerMinCalls within BreakerWindow, that opens the circuit breaker,
+	// or 0 to disable it. The circuit stays open for BreakerOpenTimeout, then
+	// closes once BreakerProbes calls in a row have succeeded.
+	BreakerFailureRate float64
+	BreakerMinCalls    int
+	BreakerWindow      time.Duration
+	BreakerOpenTimeout time.Duration
+	BreakerProbes      int
+	// SnapshotPath is checked for a new snapshot every SnapshotInterval, or
+	// never when it is 0.
+	SnapshotPath     string
+	SnapshotInterval time.Duration
+	// TrustedProxies are the addresses or CIDR ranges of the proxies whose
+	// X-Forwarded-For header gives the client IP. None are trusted by
+	// default, so clients cannot choose the IP they are rate limited by.
+	TrustedProxies []string
+}
+
+type APIKey struct {
+	Actor string
+	Admin bool
 }
 
 func Load() Config {
 	cfg := Config{
-		Port:     getEnv("PORT", "8080"),
-		MongoURI: getEnv("DB_URI", "mongodb://localhost:27017"),
-		MongoDB:  getEnv("DB_NAME", "swiftdb"),
+		Port:                  getEnv("PORT", "8080"),
+		GRPCPort:              getEnv("GRPC_PORT", "9090"),
+		MongoURI:              getEnv("DB_URI", "mongodb://localhost:27017"),
+		MongoDB:               getEnv("DB_NAME", "swiftdb"),
+		APIKeys:               parseAPIKeys(getEnv("API_KEYS", "")),
+		SoftDeleteRetention:   getDuration("SOFT_DELETE_RETENTION", 30*24*time.Hour),
+		PurgeInterval:         getDuration("PURGE_INTERVAL", time.Hour),
+		EventsChangeStream:    getEnv("EVENTS_CHANGE_STREAM", "false") == "true",
+		CacheSize:             getInt("CACHE_SIZE", 10000),
+		CacheTTL:              getDuration("CACHE_TTL", 5*time.Minute),
+		CacheNegativeTTL:      getDuration("CACHE_NEGATIVE_TTL", 30*time.Second),
+		CacheStaleTTL:         getDuration("CACHE_STALE_TTL", 0),
+		CacheControlLookup:    getEnv("CACHE_CONTROL_LOOKUP", "no-cache"),
+		CacheControlCountry:   getEnv("CACHE_CONTROL_COUNTRY", "no-cache"),
+		CacheControlHistory:   getEnv("CACHE_CONTROL_HISTORY", "no-cache"),
+		SwaggerUI:             getEnv("SWAGGER_UI", "false") == "true",
+		OmitBranchCountryName: getEnv("OMIT_BRANCH_COUNTRY_NAME", "false") == "true",
+		RateLimitRead:         getLimit("RATE_LIMIT_READ", ratelimit.Limit{Requests: 600, Window: time.Minute}),
+		RateLimitWrite:        getLimit("RATE_LIMIT_WRITE", ratelimit.Limit{Requests: 60, Window: time.Minute}),
+		RateLimitStore:        getEnv("RATE_LIMIT_STORE", "memory"),
+		ImportWorkers:         getInt("IMPORT_WORKERS", 2),
+		ImportMaxSize:         int64(getInt("IMPORT_MAX_SIZE", 256<<20)),
+		DropFolder:            getEnv("DROP_FOLDER", ""),
+		DropFolderInterval:    getDuration("DROP_FOLDER_INTERVAL", 30*time.Second),
+		Storage:               getEnv("STORAGE", "mongo"),
+		SQLitePath:            getEnv("SQLITE_PATH", "swift-codes.db"),
+		BreakerFailureRate:    getFloat("BREAKER_FAILURE_RATE", 0.5),
+		BreakerMinCalls:       getInt("BREAKER_MIN_CALLS", 20),
+		BreakerWindow:         getDuration("BREAKER_WINDOW", 30*time.Second),
+		BreakerOpenTimeout:    getDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		BreakerProbes:         getInt("BREAKER_PROBES", 3),
+		SnapshotPath:          getEnv("SNAPSHOT_PATH", "swift-codes.snap"),
+		SnapshotInterval:      getDuration("SNAPSHOT_INTERVAL", 30*time.Second),
+		TrustedProxies:        getList("TRUSTED_PROXIES"),
 	}
 	return cfg
 }
@@ -25,3 +119,87 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getList reads a comma-separated list, empty when the variable is unset.
+func getList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getInt(key string, fallback int) int {
+	val := getEnv(key, "")
+	if val == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		log.Printf("Invalid integer %q for %s, using %d", val, key, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getFloat(key string, fallback float64) float64 {
+	val := getEnv(key, "")
+	if val == "" {
+		return fallback
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		log.Printf("Invalid number %q for %s, using %g", val, key, fallback)
+		return fallback
+	}
+	return f
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	val := getEnv(key, "")
+	if val == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Printf("Invalid duration %q for %s, using %s", val, key, fallback)
+		return fallback
+	}
+	return d
+}
+
+func getLimit(key string, fallback ratelimit.Limit) ratelimit.Limit {
+	val := getEnv(key, "")
+	if val == "" {
+		return fallback
+	}
+
+	limit, err := ratelimit.ParseLimit(val)
+	if err != nil {
+		log.Printf("Invalid rate limit %q for %s, using %s", val, key, fallback)
+		return fallback
+	}
+	return limit
+}
+
+// parseAPIKeys reads a comma-separated list of "key:actor" pairs. A pair may
+// carry a trailing ":admin" to grant the actor administrative access.
+func parseAPIKeys(raw string) map[string]APIKey {
+	keys := make(map[string]APIKey)
+	for _, entry := range strings.Split(raw, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		keys[parts[0]] = APIKey{
+			Actor: parts[1],
+			Admin: len(parts) > 2 && parts[2] == "admin",
+		}
+	}
+	return keys
+}
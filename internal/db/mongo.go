@@ -2,15 +2,15 @@ package db
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-func Connect(uri string) *mongo.Client {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func Connect(ctx context.Context, uri string) (*mongo.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	client, err := mongo.Connect(
@@ -19,8 +19,8 @@ func Connect(uri string) *mongo.Client {
 	)
 
 	if err != nil {
-		log.Fatal("Mongo connection failed: ", err)
+		return nil, fmt.Errorf("mongo connection failed: %w", err)
 	}
 
-	return client
+	return client, nil
 }
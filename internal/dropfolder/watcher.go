@@ -0,0 +1,366 @@
+package dropfolder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+	"time"
+)
+
+const (
+	ProcessingDir = "processing"
+	ProcessedDir  = "processed"
+	FailedDir     = "failed"
+
+	// Actor is recorded in the audit log for changes made by drop folder
+	// imports.
+	Actor = "drop-folder"
+
+	// claimTimeout is how long a file may stay in processing without its
+	// import being queued before the replica that claimed it is taken to
+	// have stopped and the file is returned to the folder.
+	claimTimeout = 10 * time.Minute
+)
+
+// Watcher imports the files dropped into a directory. A file is moved to the
+// processing subfolder while its import job runs, and then to processed or
+// failed. Moving a file is what claims it, so replicas sharing the directory
+// never import the same file twice.
+type Watcher struct {
+	dir      string
+	interval time.Duration
+	runs     interfaces.DropFolderRepository
+	imports  *importer.Runner
+}
+
+func NewWatcher(dir string, interval time.Duration, runs interfaces.DropFolderRepository, imports *importer.Runner) *Watcher {
+	return &Watcher{
+		dir:      dir,
+		interval: interval,
+		runs:     runs,
+		imports:  imports,
+	}
+}
+
+// Start creates the subfolders and polls the folder every interval.
+func (w *Watcher) Start(ctx context.Context) error {
+	for _, sub := range []string{ProcessingDir, ProcessedDir, FailedDir} {
+		if err := os.MkdirAll(filepath.Join(w.dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	go w.loop(ctx)
+	return nil
+}
+
+func (w *Watcher) loop(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Poll(ctx); err != nil {
+			log.Printf("Failed to poll the drop folder: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll finishes the runs whose import is over, returns the files of stopped
+// replicas to the folder and picks up the files that have not been modified
+// for an interval, leaving those still being copied in. Hidden files are
+// ignored.
+func (w *Watcher) Poll(ctx context.Context) error {
+	importing, err := w.runs.FindRunsByStatus(ctx, models.DropFolderRunImporting)
+	if err != nil {
+		return err
+	}
+	claimed, err := w.runs.FindRunsByStatus(ctx, models.DropFolderRunClaimed)
+	if err != nil {
+		return err
+	}
+
+	if err = w.track(ctx, importing); err != nil {
+		return err
+	}
+	if err = w.recoverOrphans(ctx, importing, claimed); err != nil {
+		log.Printf("Failed to recover drop folder files left in processing: %v", err)
+	}
+
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < w.interval {
+			continue
+		}
+
+		if err = w.pickUp(ctx, entry.Name()); err != nil {
+			log.Printf("Failed to pick up %s from the drop folder: %v", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// pickUp claims a file by moving it to processing and records its run as
+// claimed before reading it. The file is touched first, so that its
+// modification time in processing is when it was claimed.
+func (w *Watcher) pickUp(ctx context.Context, name string) error {
+	now := time.Now()
+	run := models.DropFolderRun{
+		ID:        utils.NewID(),
+		FileName:  name,
+		StartedAt: now.UTC(),
+	}
+
+	path := w.processingPath(run)
+	err := os.Chtimes(filepath.Join(w.dir, name), now, now)
+	if err == nil {
+		err = os.Rename(filepath.Join(w.dir, name), path)
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		// Picked up by another replica.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	run.Status = models.DropFolderRunClaimed
+	if err = w.runs.CreateRun(ctx, run); err != nil {
+		// Nothing was recorded, so there is no run to fail.
+		return w.release(ctx, models.DropFolderRun{ID: run.ID, FileName: name}, err)
+	}
+
+	checksum, err := checksumOf(path)
+	if err != nil {
+		return w.finish(ctx, run, models.DropFolderRunFailed, "Failed to read the file: "+err.Error())
+	}
+	run.Checksum = checksum
+
+	format, ok := importer.DetectFormat("", name)
+	if !ok {
+		return w.finish(ctx, run, models.DropFolderRunFailed, "Only .json, .ndjson and .csv files can be imported")
+	}
+
+	previous, err := w.runs.FindImportedRun(ctx, checksum)
+	if err == nil {
+		run.DuplicateOf = previous.ID
+		return w.finish(ctx, run, models.DropFolderRunDuplicate, "")
+	}
+	if !errors.Is(err, interfaces.ErrNotFound) {
+		return w.release(ctx, run, err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return w.release(ctx, run, err)
+	}
+	job, err := w.imports.Submit(reqctx.WithActor(ctx, Actor), name, format, file)
+	file.Close()
+	if err != nil {
+		return w.release(ctx, run, err)
+	}
+
+	run.ImportID = job.ID
+	run.Status = models.DropFolderRunImporting
+	err = w.runs.UpdateRun(ctx, run, models.DropFolderRunClaimed)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		// Taken for the run of a stopped replica and returned to the folder
+		// while the job was being queued.
+		log.Printf("Drop folder file %s was released while queueing job %s, cancelling it", name, job.ID)
+		_, err = w.imports.Cancel(ctx, job.ID)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Importing %s from the drop folder as job %s", name, job.ID)
+	return nil
+}
+
+// release puts a file back into the folder to be picked up again at the next
+// poll, recording its run as failed with the cause if it was claimed. A run
+// that was released or finished in the meantime is left alone.
+func (w *Watcher) release(ctx context.Context, run models.DropFolderRun, cause error) error {
+	if run.Status == models.DropFolderRunClaimed {
+		now := time.Now().UTC()
+		run.Status = models.DropFolderRunFailed
+		run.Error = "Returned to the drop folder: " + cause.Error()
+		run.FinishedAt = &now
+
+		err := w.runs.UpdateRun(ctx, run, models.DropFolderRunClaimed)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return cause
+		}
+		if err != nil {
+			log.Printf("Failed to record the release of %s from the drop folder: %v", run.FileName, err)
+		}
+	}
+
+	if err := w.putBack(filepath.Base(w.processingPath(run)), run.FileName); err != nil {
+		log.Printf("Failed to return %s to the drop folder: %v", run.FileName, err)
+	}
+	return cause
+}
+
+// putBack moves a file from processing back into the folder, touching it so
+// that it waits an interval before being picked up again.
+func (w *Watcher) putBack(entry, name string) error {
+	path := filepath.Join(w.dir, ProcessingDir, entry)
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return err
+	}
+	return os.Rename(path, filepath.Join(w.dir, name))
+}
+
+// track finishes the importing runs whose job is over.
+func (w *Watcher) track(ctx context.Context, runs []models.DropFolderRun) error {
+	for _, run := range runs {
+		job, err := w.imports.Find(ctx, run.ImportID)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			if err = w.finish(ctx, run, models.DropFolderRunFailed, "The import job no longer exists"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !job.Finished() {
+			continue
+		}
+
+		run.Processed = job.Processed
+		run.Created = job.Created
+		run.Updated = job.Updated
+		run.Unchanged = job.Unchanged
+		run.Failed = job.Failed
+		if err = w.finish(ctx, run, job.Status, job.Error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finish records the outcome of a run and moves its file to the processed or
+// failed subfolder. A run that another replica finished first is left alone.
+func (w *Watcher) finish(ctx context.Context, run models.DropFolderRun, status, message string) error {
+	now := time.Now().UTC()
+	from := run.Status
+	run.Status = status
+	run.Error = message
+	run.FinishedAt = &now
+
+	sub := FailedDir
+	if status == models.DropFolderRunSucceeded || status == models.DropFolderRunDuplicate {
+		sub = ProcessedDir
+	}
+	destination := w.destination(sub, run)
+	run.MovedTo = filepath.Join(sub, filepath.Base(destination))
+
+	err := w.runs.UpdateRun(ctx, run, from)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Drop folder file %s %s, moving it to %s", run.FileName, status, run.MovedTo)
+	return os.Rename(w.processingPath(run), destination)
+}
+
+// destination keeps the file name unless the subfolder already holds a file
+// of that name, which is then told apart by the run ID.
+func (w *Watcher) destination(sub string, run models.DropFolderRun) string {
+	path := filepath.Join(w.dir, sub, run.FileName)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return path
+	}
+
+	ext := filepath.Ext(run.FileName)
+	return filepath.Join(w.dir, sub, strings.TrimSuffix(run.FileName, ext)+"."+run.ID+ext)
+}
+
+func (w *Watcher) processingPath(run models.DropFolderRun) string {
+	return filepath.Join(w.dir, ProcessingDir, run.ID+"-"+run.FileName)
+}
+
+// recoverOrphans returns to the folder the files that were claimed more than
+// claimTimeout ago and whose import was never queued, because the replica
+// that claimed them stopped before recording their run or queueing the job.
+// The runs must have been read before the processing subfolder, so that a
+// file claimed in between is recent enough to be left alone.
+func (w *Watcher) recoverOrphans(ctx context.Context, importing, claimed []models.DropFolderRun) error {
+	tracked := map[string]bool{}
+	for _, run := range importing {
+		tracked[filepath.Base(w.processingPath(run))] = true
+	}
+
+	for _, run := range claimed {
+		tracked[filepath.Base(w.processingPath(run))] = true
+		if time.Since(run.StartedAt) >= claimTimeout {
+			log.Printf("Drop folder file %s was claimed at %s and never queued, returning it to the folder", run.FileName, run.StartedAt)
+			_ = w.release(ctx, run, errors.New("the import was not queued within "+claimTimeout.String()))
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(w.dir, ProcessingDir))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		_, name, ok := strings.Cut(entry.Name(), "-")
+		if !ok || tracked[entry.Name()] {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < claimTimeout {
+			continue
+		}
+		if err = w.putBack(entry.Name(), name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func checksumOf(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,123 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"sync"
+	"time"
+)
+
+const subscriptionBuffer = 256
+
+type Filter struct {
+	CountryISO2 string
+	Types       []string
+}
+
+func (f Filter) Matches(event models.Event) bool {
+	if f.CountryISO2 != "" && f.CountryISO2 != event.CountryISO2 {
+		return false
+	}
+	return len(f.Types) == 0 || slices.Contains(f.Types, event.Type)
+}
+
+type Subscription struct {
+	filter Filter
+	events chan models.Event
+}
+
+// Events delivers published events matching the subscription filter. The
+// channel is closed when the subscriber falls too far behind, after which it
+// should resume from the event log.
+func (s *Subscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Broker persists published events to the event log, which assigns their
+// IDs, and fans them out to in-process subscribers.
+type Broker struct {
+	repo interfaces.EventRepository
+
+	// publishMu keeps events fanned out in the order of their IDs. It is
+	// held while the event is logged, unlike mu, so that subscribing does
+	// not wait for the database.
+	publishMu sync.Mutex
+	mu        sync.Mutex
+	subs      map[*Subscription]struct{}
+}
+
+func NewBroker(repo interfaces.EventRepository) *Broker {
+	return &Broker{
+		repo: repo,
+		subs: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish logs an event and fans it out. Events with a SourceID that was
+// logged already are fanned out as logged, so that every replica watching a
+// change feed delivers them under the same ID.
+func (b *Broker) Publish(ctx context.Context, event models.Event) error {
+	b.publishMu.Lock()
+	defer b.publishMu.Unlock()
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	event, err := b.repo.Append(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subs, sub)
+			close(sub.events)
+		}
+	}
+
+	return nil
+}
+
+func (b *Broker) Subscribe(filter Filter) *Subscription {
+	sub := &Subscription{
+		filter: filter,
+		events: make(chan models.Event, subscriptionBuffer),
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+func (b *Broker) LatestID(ctx context.Context) (int64, error) {
+	return b.repo.LatestID(ctx)
+}
+
+// Replay returns logged events after afterID for the given country (or all
+// countries), oldest first, up to limit events.
+func (b *Broker) Replay(ctx context.Context, afterID int64, countryISO2 string, limit int64) ([]models.Event, error) {
+	return b.repo.FindAfter(ctx, afterID, countryISO2, limit)
+}
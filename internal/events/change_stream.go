@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"log"
+	"slices"
+	"swift-codes-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type changeEvent struct {
+	ID struct {
+		Data string `bson:"_data"`
+	} `bson:"_id"`
+	OperationType     string            `bson:"operationType"`
+	FullDocument      *models.SwiftCode `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M   `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+// WatchChangeStream opens a change stream on the SWIFT codes collection and
+// publishes its changes to the broker until ctx is cancelled. It fails when
+// the deployment does not support change streams, e.g. a standalone server.
+func WatchChangeStream(ctx context.Context, col *mongo.Collection, broker *Broker) error {
+	stream, err := col.Watch(ctx, mongo.Pipeline{}, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer stream.Close(context.Background())
+
+		for stream.Next(ctx) {
+			var change changeEvent
+			if err := stream.Decode(&change); err != nil {
+				log.Printf("Failed to decode change event: %v", err)
+				continue
+			}
+
+			event, ok := toEvent(change)
+			if !ok {
+				continue
+			}
+
+			if err := broker.Publish(ctx, event); err != nil {
+				log.Printf("Failed to publish change event for %s: %v", event.SwiftCode, err)
+			}
+		}
+
+		if err := stream.Err(); err != nil && ctx.Err() == nil {
+			log.Printf("SWIFT code change stream stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// toEvent maps a change to an event. Its source is the resume token of the
+// change, which is the same for every replica watching the stream, so that
+// the change is logged once.
+func toEvent(change changeEvent) (models.Event, bool) {
+	if change.FullDocument == nil {
+		return models.Event{}, false
+	}
+
+	var eventType string
+	switch change.OperationType {
+	case "insert", "replace":
+		eventType = models.EventTypeCreated
+	case "update":
+		if _, ok := change.UpdateDescription.UpdatedFields["deletedAt"]; ok {
+			eventType = models.EventTypeDeleted
+		} else if slices.Contains(change.UpdateDescription.RemovedFields, "deletedAt") {
+			eventType = models.EventTypeRestored
+		} else {
+			eventType = models.EventTypeUpdated
+		}
+	default:
+		return models.Event{}, false
+	}
+
+	return models.Event{
+		Type:        eventType,
+		SwiftCode:   change.FullDocument.SwiftCode,
+		CountryISO2: change.FullDocument.CountryISO2,
+		Record:      change.FullDocument,
+		SourceID:    change.ID.Data,
+	}, true
+}
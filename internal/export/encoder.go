@@ -0,0 +1,190 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"swift-codes-api/dto"
+	"swift-codes-api/models"
+)
+
+var csvHeader = []string{"swiftCode", "bankName", "address", "countryISO2", "countryName", "isHeadquarter"}
+
+// formulaPrefixes are the first characters that make spreadsheets evaluate a
+// CSV cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// EscapeCSVCell prefixes a cell that a spreadsheet would evaluate as a
+// formula with a single quote, so that opening an export cannot run one.
+func EscapeCSVCell(value string) string {
+	if value != "" && strings.IndexByte(formulaPrefixes, value[0]) >= 0 {
+		return "'" + value
+	}
+	return value
+}
+
+// UnescapeCSVCell undoes EscapeCSVCell, so that exported CSV files can be
+// imported as they are.
+func UnescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.IndexByte(formulaPrefixes, value[1]) >= 0 {
+		return value[1:]
+	}
+	return value
+}
+
+// Encoder writes SWIFT codes one at a time, so that a listing of any size can
+// be streamed. Close must be called to complete the document.
+type Encoder interface {
+	Encode(swiftCode models.SwiftCode) error
+	Close() error
+}
+
+func NewEncoder(w io.Writer, format Format) Encoder {
+	switch format {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w)}
+	case FormatNDJSON:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}
+	case FormatXML:
+		return &xmlEncoder{w: w, enc: xml.NewEncoder(w)}
+	default:
+		return &jsonEncoder{w: w}
+	}
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (e *csvEncoder) Encode(swiftCode models.SwiftCode) error {
+	if err := e.header(); err != nil {
+		return err
+	}
+	return e.w.Write([]string{
+		EscapeCSVCell(swiftCode.SwiftCode),
+		EscapeCSVCell(swiftCode.BankName),
+		EscapeCSVCell(swiftCode.Address),
+		EscapeCSVCell(swiftCode.CountryISO2),
+		EscapeCSVCell(swiftCode.CountryName),
+		strconv.FormatBool(swiftCode.IsHeadquarter),
+	})
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.header(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) header() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.w.Write(csvHeader)
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(swiftCode models.SwiftCode) error {
+	return e.enc.Encode(dto.NewSwiftCodeResponse(swiftCode))
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonEncoder) Encode(swiftCode models.SwiftCode) error {
+	body, err := json.Marshal(dto.NewSwiftCodeResponse(swiftCode))
+	if err != nil {
+		return err
+	}
+
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	e.count++
+
+	if _, err = io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	_, err = e.w.Write(body)
+	return err
+}
+
+func (e *jsonEncoder) Close() error {
+	closing := "]"
+	if e.count == 0 {
+		closing = "[]"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
+
+type xmlSwiftCode struct {
+	XMLName       xml.Name `xml:"swiftCode"`
+	SwiftCode     string   `xml:"code"`
+	BankName      string   `xml:"bankName"`
+	Address       string   `xml:"address"`
+	CountryISO2   string   `xml:"countryISO2"`
+	CountryName   string   `xml:"countryName"`
+	IsHeadquarter bool     `xml:"isHeadquarter"`
+}
+
+type xmlEncoder struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+var xmlRoot = xml.StartElement{Name: xml.Name{Local: "swiftCodes"}}
+
+func (e *xmlEncoder) Encode(swiftCode models.SwiftCode) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	return e.enc.Encode(xmlSwiftCode{
+		SwiftCode:     swiftCode.SwiftCode,
+		BankName:      swiftCode.BankName,
+		Address:       swiftCode.Address,
+		CountryISO2:   swiftCode.CountryISO2,
+		CountryName:   swiftCode.CountryName,
+		IsHeadquarter: swiftCode.IsHeadquarter,
+	})
+}
+
+func (e *xmlEncoder) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if err := e.enc.EncodeToken(xmlRoot.End()); err != nil {
+		return err
+	}
+	return e.enc.Flush()
+}
+
+func (e *xmlEncoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+	return e.enc.EncodeToken(xmlRoot)
+}
@@ -0,0 +1,79 @@
+package export
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+	FormatXML    Format = "xml"
+)
+
+var (
+	ErrUnknownFormat  = errors.New("unknown format")
+	mediaTypeToFormat = map[string]Format{
+		"application/json":     FormatJSON,
+		"text/csv":             FormatCSV,
+		"application/x-ndjson": FormatNDJSON,
+		"application/ndjson":   FormatNDJSON,
+		"application/xml":      FormatXML,
+		"text/xml":             FormatXML,
+	}
+)
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatXML:
+		return "application/xml; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Negotiate picks the response format. An explicit format query parameter
+// wins over the Accept header. Otherwise the response is JSON unless the
+// media type the Accept header prefers is that of another format, so that
+// wildcards, browsers and unknown media types keep getting JSON.
+func Negotiate(format, accept string) (Format, error) {
+	if format != "" {
+		switch f := Format(strings.ToLower(format)); f {
+		case FormatJSON, FormatCSV, FormatNDJSON, FormatXML:
+			return f, nil
+		}
+		return "", ErrUnknownFormat
+	}
+
+	preferred, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			preferred, bestQ = mediaType, q
+		}
+	}
+
+	if f, ok := mediaTypeToFormat[preferred]; ok {
+		return f, nil
+	}
+	return FormatJSON, nil
+}
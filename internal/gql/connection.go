@@ -0,0 +1,105 @@
+package gql
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+)
+
+// maxPageSize bounds the first argument of every connection.
+const maxPageSize = 100
+
+const cursorPrefix = "offset:"
+
+type connectionResolver struct {
+	nodes       []models.SwiftCode
+	offset      int
+	hasNextPage bool
+}
+
+type edgeResolver struct {
+	cursor string
+	node   models.SwiftCode
+}
+
+type pageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+// pageArgs returns the offset and size of the page requested by first and
+// after. Cursors are opaque to clients and encode an offset.
+func pageArgs(first int32, after *string) (int, int, error) {
+	if first < 0 || first > maxPageSize {
+		return 0, 0, newError(problems.InvalidParameter, "first must be between 0 and "+strconv.Itoa(maxPageSize))
+	}
+
+	offset := 0
+	if after != nil {
+		decoded, err := base64.StdEncoding.DecodeString(*after)
+		n, convErr := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+		if err != nil || convErr != nil || !strings.HasPrefix(string(decoded), cursorPrefix) || n < 0 {
+			return 0, 0, newError(problems.InvalidParameter, "after is not a valid cursor")
+		}
+		offset = n + 1
+	}
+	return offset, int(first), nil
+}
+
+// paginate returns the page of items requested by first and after.
+func paginate(items []models.SwiftCode, first int32, after *string) (*connectionResolver, error) {
+	offset, size, err := pageArgs(first, after)
+	if err != nil {
+		return nil, err
+	}
+
+	offset = min(offset, len(items))
+	end := min(offset+size, len(items))
+	return &connectionResolver{nodes: items[offset:end], offset: offset, hasNextPage: end < len(items)}, nil
+}
+
+func cursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func (r *connectionResolver) Edges(ctx context.Context) ([]*edgeResolver, error) {
+	l := loaderFrom(ctx)
+	if err := l.spend(len(r.nodes)); err != nil {
+		return nil, err
+	}
+
+	edges := make([]*edgeResolver, len(r.nodes))
+	for i, node := range r.nodes {
+		edges[i] = &edgeResolver{cursor: cursor(r.offset + i), node: node}
+	}
+	l.expect(r.nodes)
+	return edges, nil
+}
+
+func (r *connectionResolver) PageInfo() *pageInfoResolver {
+	info := &pageInfoResolver{hasNextPage: r.hasNextPage}
+	if len(r.nodes) > 0 {
+		endCursor := cursor(r.offset + len(r.nodes) - 1)
+		info.endCursor = &endCursor
+	}
+	return info
+}
+
+func (r *edgeResolver) Cursor() string {
+	return r.cursor
+}
+
+func (r *edgeResolver) Node() *swiftCodeResolver {
+	return &swiftCodeResolver{swiftCode: r.node}
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.hasNextPage
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	return r.endCursor
+}
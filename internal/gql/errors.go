@@ -0,0 +1,33 @@
+package gql
+
+import "swift-codes-api/problems"
+
+// Error is a GraphQL error carrying the same problem code as the REST API
+// in its extensions, along with any invalid fields.
+type Error struct {
+	Code    problems.Code
+	Message string
+	Fields  []problems.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{"code": e.Code}
+	if len(e.Fields) > 0 {
+		extensions["errors"] = e.Fields
+	}
+	return extensions
+}
+
+func newError(code problems.Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// failedError reports a failed repository call as internal, unless
+// problems.FromError knows the error better.
+func failedError(err error, message string) *Error {
+	return newError(problems.FromError(err, problems.Internal, message))
+}
@@ -0,0 +1,263 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"sync"
+)
+
+type loaderKey struct{}
+
+// maxNodes bounds the number of records a single request may return across
+// all of its lists, whatever their nesting.
+const maxNodes = 2000
+
+// loader caches the records read while resolving one request and batches the
+// lookups of sibling records, so that listing codes with their banks and
+// branches does not cost one query per code.
+type loader struct {
+	repo interfaces.SwiftRepository
+
+	mu          sync.Mutex
+	codes       map[string]*call[*models.SwiftCode]
+	countries   map[string]*call[country]
+	branchLists map[string]*call[[]models.SwiftCode]
+	// pendingCodes and pendingPrefixes hold the headquarters and the banks
+	// whose records are about to be resolved; the first lookup of one of
+	// them loads them all in one batch.
+	pendingCodes    map[string]bool
+	pendingPrefixes map[string]bool
+	codeBatches     map[string]*batch[*models.SwiftCode]
+	prefixBatches   map[string]*batch[[]models.SwiftCode]
+	nodes           int
+}
+
+type country struct {
+	name       string
+	swiftCodes []models.SwiftCode
+}
+
+// call is a repository read shared by every resolver that needs its result.
+type call[T any] struct {
+	once sync.Once
+	val  T
+	err  error
+}
+
+func (c *call[T]) do(fn func() (T, error)) (T, error) {
+	c.once.Do(func() { c.val, c.err = fn() })
+	return c.val, c.err
+}
+
+// batch is a repository read of several keys at once, shared by the
+// resolvers of each of them.
+type batch[K any] struct {
+	call[map[string]K]
+	keys []string
+}
+
+// NewContext returns a context carrying a loader for one GraphQL request.
+func NewContext(ctx context.Context, repo interfaces.SwiftRepository) context.Context {
+	return context.WithValue(ctx, loaderKey{}, &loader{
+		repo:            repo,
+		codes:           make(map[string]*call[*models.SwiftCode]),
+		countries:       make(map[string]*call[country]),
+		branchLists:     make(map[string]*call[[]models.SwiftCode]),
+		pendingCodes:    make(map[string]bool),
+		pendingPrefixes: make(map[string]bool),
+		codeBatches:     make(map[string]*batch[*models.SwiftCode]),
+		prefixBatches:   make(map[string]*batch[[]models.SwiftCode]),
+	})
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	return ctx.Value(loaderKey{}).(*loader)
+}
+
+func entry[T any](l *loader, m map[string]*call[T], key string) *call[T] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c, ok := m[key]
+	if !ok {
+		c = &call[T]{}
+		m[key] = c
+	}
+	return c
+}
+
+// batchOf returns the batch that loads key, gathering every pending key into
+// a new one if key is pending, or nil when key was never announced.
+func batchOf[K any](l *loader, pending map[string]bool, batches map[string]*batch[K], key string) *batch[K] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := batches[key]; ok {
+		return b
+	}
+	if !pending[key] {
+		return nil
+	}
+
+	b := &batch[K]{keys: make([]string, 0, len(pending))}
+	for k := range pending {
+		b.keys = append(b.keys, k)
+		batches[k] = b
+	}
+	clear(pending)
+	slices.Sort(b.keys)
+	return b
+}
+
+// announce marks keys as pending unless they are loaded, or about to be,
+// already.
+func announce[K, T any](l *loader, pending map[string]bool, batches map[string]*batch[K], loaded map[string]*call[T], keys []string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		_, batched := batches[key]
+		_, single := loaded[key]
+		if !batched && !single && !l.countryLoaded(key[4:6]) {
+			pending[key] = true
+		}
+	}
+}
+
+// spend counts n more records towards maxNodes.
+func (l *loader) spend(n int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.nodes += n
+	if l.nodes > maxNodes {
+		return newError(problems.InvalidParameter, "query returns more than "+strconv.Itoa(maxNodes)+" records, ask for smaller pages")
+	}
+	return nil
+}
+
+// swiftCode returns the record of a code, or nil when it does not exist.
+// Codes of a country that is loaded as a whole are taken from it, and codes
+// announced by expect are loaded together.
+func (l *loader) swiftCode(ctx context.Context, code string) (*models.SwiftCode, error) {
+	if l.wholeCountry(code[4:6]) {
+		c, _, err := l.country(ctx, code[4:6])
+		if err != nil {
+			return nil, err
+		}
+		for i := range c.swiftCodes {
+			if c.swiftCodes[i].SwiftCode == code {
+				return &c.swiftCodes[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	if b := batchOf(l, l.pendingCodes, l.codeBatches, code); b != nil {
+		found, err := b.do(func() (map[string]*models.SwiftCode, error) {
+			swiftCodes, err := l.repo.FindByCodes(ctx, b.keys)
+			if err != nil {
+				return nil, err
+			}
+			found := make(map[string]*models.SwiftCode, len(swiftCodes))
+			for i := range swiftCodes {
+				found[swiftCodes[i].SwiftCode] = &swiftCodes[i]
+			}
+			return found, nil
+		})
+		return found[code], err
+	}
+
+	return entry(l, l.codes, code).do(func() (*models.SwiftCode, error) {
+		result, err := l.repo.FindByCode(ctx, code)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return nil, nil
+		}
+		return result, err
+	})
+}
+
+// country returns the records of a country; found is false when it has none.
+func (l *loader) country(ctx context.Context, iso2 string) (country, bool, error) {
+	c, err := entry(l, l.countries, iso2).do(func() (country, error) {
+		swiftCodes, name, err := l.repo.FindByCountryISO2(ctx, iso2)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return country{}, nil
+		}
+		return country{name: name, swiftCodes: swiftCodes}, err
+	})
+	return c, len(c.swiftCodes) > 0, err
+}
+
+// expect announces the codes of a list about to be resolved: their banks'
+// branches, and the headquarters of the branches among them. The first
+// lookup of any of them loads them all with one query each for the
+// headquarters and the branches.
+func (l *loader) expect(swiftCodes []models.SwiftCode) {
+	var codes, prefixes []string
+	for _, swiftCode := range swiftCodes {
+		prefix := swiftCode.SwiftCode[:8]
+		prefixes = append(prefixes, prefix)
+		if !swiftCode.IsHeadquarter {
+			codes = append(codes, prefix+"XXX")
+		}
+	}
+	announce(l, l.pendingCodes, l.codeBatches, l.codes, codes)
+	announce(l, l.pendingPrefixes, l.prefixBatches, l.branchLists, prefixes)
+}
+
+// wholeCountry reports whether records of a country are read from the
+// country as a whole, which is the case once it has been loaded for a
+// byCountry query.
+func (l *loader) wholeCountry(iso2 string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.countryLoaded(iso2)
+}
+
+func (l *loader) countryLoaded(iso2 string) bool {
+	_, loaded := l.countries[iso2]
+	return loaded
+}
+
+func (l *loader) branches(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
+	iso2 := prefix[4:6]
+	if l.wholeCountry(iso2) {
+		c, _, err := l.country(ctx, iso2)
+		if err != nil {
+			return nil, err
+		}
+		var branches []models.SwiftCode
+		for _, swiftCode := range c.swiftCodes {
+			if !swiftCode.IsHeadquarter && swiftCode.SwiftCode[:8] == prefix {
+				branches = append(branches, swiftCode)
+			}
+		}
+		return branches, nil
+	}
+
+	if b := batchOf(l, l.pendingPrefixes, l.prefixBatches, prefix); b != nil {
+		found, err := b.do(func() (map[string][]models.SwiftCode, error) {
+			branches, err := l.repo.FindBranchesByPrefixes(ctx, b.keys)
+			if err != nil {
+				return nil, err
+			}
+			found := make(map[string][]models.SwiftCode, len(b.keys))
+			for _, branch := range branches {
+				found[branch.SwiftCode[:8]] = append(found[branch.SwiftCode[:8]], branch)
+			}
+			return found, nil
+		})
+		return found[prefix], err
+	}
+
+	return entry(l, l.branchLists, prefix).do(func() ([]models.SwiftCode, error) {
+		return l.repo.FindBranchesByPrefix(ctx, prefix)
+	})
+}
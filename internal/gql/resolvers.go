@@ -0,0 +1,259 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+)
+
+type resolver struct {
+	repo interfaces.SwiftRepository
+}
+
+func (r *resolver) Lookup(ctx context.Context, args struct{ Code string }) (*swiftCodeResolver, error) {
+	if !utils.ValidateSwiftCode(args.Code) {
+		return nil, newError(problems.InvalidSwiftCode, utils.InvalidSwiftCodeMessage)
+	}
+
+	swiftCode, err := loaderFrom(ctx).swiftCode(ctx, args.Code)
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT code")
+	}
+	if swiftCode == nil {
+		return nil, nil
+	}
+	return &swiftCodeResolver{swiftCode: *swiftCode}, nil
+}
+
+func (r *resolver) Search(ctx context.Context, args struct {
+	Query   string
+	Country *string
+	First   int32
+	After   *string
+}) (*connectionResolver, error) {
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return nil, newError(problems.InvalidParameter, "query must not be empty")
+	}
+
+	var countryISO2 string
+	if args.Country != nil {
+		if !utils.ValidateCountryCode(*args.Country) {
+			return nil, newError(problems.InvalidCountryCode, utils.InvalidCountryCodeMessage)
+		}
+		countryISO2 = strings.ToUpper(*args.Country)
+	}
+
+	offset, size, err := pageArgs(args.First, args.After)
+	if err != nil {
+		return nil, err
+	}
+
+	// One match past the page tells whether there is a next one.
+	matches, err := r.repo.SearchSwiftCodes(ctx, query, countryISO2, offset+size+1)
+	if err != nil {
+		return nil, failedError(err, "Failed to search SWIFT codes")
+	}
+
+	offset = min(offset, len(matches))
+	end := min(offset+size, len(matches))
+	return &connectionResolver{nodes: matches[offset:end], offset: offset, hasNextPage: end < len(matches)}, nil
+}
+
+func (r *resolver) ByCountry(ctx context.Context, args struct{ Iso2 string }) (*countryResolver, error) {
+	if !utils.ValidateCountryCode(args.Iso2) {
+		return nil, newError(problems.InvalidCountryCode, utils.InvalidCountryCodeMessage)
+	}
+	iso2 := strings.ToUpper(args.Iso2)
+
+	c, found, err := loaderFrom(ctx).country(ctx, iso2)
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT codes")
+	}
+	if !found {
+		return nil, nil
+	}
+	return &countryResolver{iso2: iso2, name: c.name}, nil
+}
+
+type swiftCodeInput struct {
+	Code          string
+	BankName      string
+	Address       string
+	CountryISO2   string
+	CountryName   string
+	IsHeadquarter bool
+}
+
+func (r *resolver) AddSwiftCode(ctx context.Context, args struct{ Input swiftCodeInput }) (*swiftCodeResolver, error) {
+	swiftCode := models.SwiftCode{
+		SwiftCode:     args.Input.Code,
+		BankName:      args.Input.BankName,
+		Address:       args.Input.Address,
+		CountryISO2:   args.Input.CountryISO2,
+		CountryName:   args.Input.CountryName,
+		IsHeadquarter: args.Input.IsHeadquarter,
+	}
+	if errs := utils.ValidateSwiftCodeRecord(&swiftCode); len(errs) > 0 {
+		return nil, &Error{Code: problems.ValidationFailed, Message: "One or more fields are invalid", Fields: errs}
+	}
+
+	if err := r.repo.AddSwiftCode(ctx, swiftCode); err != nil {
+		return nil, failedError(err, "Failed to add SWIFT code")
+	}
+
+	// Read the record back for the revision the repository assigned to it.
+	added, err := r.repo.FindByCode(ctx, swiftCode.SwiftCode)
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT code")
+	}
+	return &swiftCodeResolver{swiftCode: *added}, nil
+}
+
+func (r *resolver) DeleteSwiftCode(ctx context.Context, args struct {
+	Code             string
+	ExpectedRevision *int32
+}) (bool, error) {
+	if !utils.ValidateSwiftCode(args.Code) {
+		return false, newError(problems.InvalidSwiftCode, utils.InvalidSwiftCodeMessage)
+	}
+
+	revision := interfaces.AnyRevision
+	if args.ExpectedRevision != nil {
+		revision = int64(*args.ExpectedRevision)
+	}
+
+	err := r.repo.DeleteSwiftCode(ctx, args.Code, revision)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, interfaces.ErrNotFound):
+		return false, newError(problems.SwiftCodeNotFound, "No SWIFT code "+args.Code+" exists")
+	default:
+		return false, failedError(err, "Failed to delete SWIFT code")
+	}
+}
+
+type swiftCodeResolver struct {
+	swiftCode models.SwiftCode
+}
+
+func (r *swiftCodeResolver) Code() string {
+	return r.swiftCode.SwiftCode
+}
+
+func (r *swiftCodeResolver) BankName() string {
+	return r.swiftCode.BankName
+}
+
+func (r *swiftCodeResolver) Address() string {
+	return r.swiftCode.Address
+}
+
+func (r *swiftCodeResolver) IsHeadquarter() bool {
+	return r.swiftCode.IsHeadquarter
+}
+
+func (r *swiftCodeResolver) Country() *countryResolver {
+	return &countryResolver{iso2: r.swiftCode.CountryISO2, name: r.swiftCode.CountryName}
+}
+
+func (r *swiftCodeResolver) Revision() int32 {
+	return int32(r.swiftCode.Revision)
+}
+
+func (r *swiftCodeResolver) Bank(ctx context.Context) (*bankResolver, error) {
+	if r.swiftCode.IsHeadquarter {
+		return &bankResolver{headquarter: r.swiftCode}, nil
+	}
+
+	headquarter, err := loaderFrom(ctx).swiftCode(ctx, r.swiftCode.SwiftCode[:8]+"XXX")
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT code")
+	}
+	if headquarter == nil {
+		return nil, nil
+	}
+	return &bankResolver{headquarter: *headquarter}, nil
+}
+
+type bankResolver struct {
+	headquarter models.SwiftCode
+}
+
+func (r *bankResolver) Code() string {
+	return r.headquarter.SwiftCode
+}
+
+func (r *bankResolver) Name() string {
+	return r.headquarter.BankName
+}
+
+func (r *bankResolver) Address() string {
+	return r.headquarter.Address
+}
+
+func (r *bankResolver) Country() *countryResolver {
+	return &countryResolver{iso2: r.headquarter.CountryISO2, name: r.headquarter.CountryName}
+}
+
+func (r *bankResolver) Headquarter() *swiftCodeResolver {
+	return &swiftCodeResolver{swiftCode: r.headquarter}
+}
+
+func (r *bankResolver) Branches(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*connectionResolver, error) {
+	branches, err := loaderFrom(ctx).branches(ctx, r.headquarter.SwiftCode[:8])
+	if err != nil {
+		return nil, failedError(err, "Failed to look up branches")
+	}
+	return paginate(branches, args.First, args.After)
+}
+
+type countryResolver struct {
+	iso2 string
+	name string
+}
+
+func (r *countryResolver) Iso2() string {
+	return r.iso2
+}
+
+func (r *countryResolver) Name() string {
+	return r.name
+}
+
+func (r *countryResolver) SwiftCodes(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*connectionResolver, error) {
+	c, _, err := loaderFrom(ctx).country(ctx, r.iso2)
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT codes")
+	}
+	return paginate(c.swiftCodes, args.First, args.After)
+}
+
+func (r *countryResolver) Banks(ctx context.Context) ([]*bankResolver, error) {
+	c, _, err := loaderFrom(ctx).country(ctx, r.iso2)
+	if err != nil {
+		return nil, failedError(err, "Failed to look up SWIFT codes")
+	}
+
+	banks := []*bankResolver{}
+	for _, swiftCode := range c.swiftCodes {
+		if swiftCode.IsHeadquarter {
+			banks = append(banks, &bankResolver{headquarter: swiftCode})
+		}
+	}
+	if err := loaderFrom(ctx).spend(len(banks)); err != nil {
+		return nil, err
+	}
+	return banks, nil
+}
@@ -0,0 +1,26 @@
+// Package gql serves the SWIFT code directory as a GraphQL schema resolved
+// through the SWIFT code repository.
+package gql
+
+import (
+	_ "embed"
+	"swift-codes-api/repositories/interfaces"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// maxDepth bounds the nesting of queries; the deepest useful one, a
+// country's banks' branches with their bank, is well within it.
+const maxDepth = 10
+
+// NewSchema returns the GraphQL schema backed by repo. Queries must run with
+// a context from NewContext, which also bounds the records a query returns.
+func NewSchema(repo interfaces.SwiftRepository) *graphql.Schema {
+	return graphql.MustParseSchema(schema, &resolver{repo: repo},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxDepth),
+	)
+}
@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Callers authenticate and correlate requests with the same headers as the
+// REST API, sent as metadata.
+const (
+	apiKeyMetadata    = "x-api-key"
+	requestIDMetadata = "x-request-id"
+)
+
+func unaryAuthenticate(cfg config.Config) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(authenticate(ctx, cfg), req)
+	}
+}
+
+func streamAuthenticate(cfg config.Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: authenticate(ss.Context(), cfg)})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate stores the request ID and the caller of a request in its
+// context, like the RequestID and Authenticate middleware do for REST.
+func authenticate(ctx context.Context, cfg config.Config) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := first(md, requestIDMetadata)
+	if requestID == "" {
+		requestID = utils.NewID()
+	}
+	ctx = reqctx.WithRequestID(ctx, requestID)
+
+	if key, ok := cfg.APIKeys[first(md, apiKeyMetadata)]; ok {
+		ctx = reqctx.WithActor(ctx, key.Actor)
+		if key.Admin {
+			ctx = reqctx.WithAdmin(ctx)
+		}
+		return ctx
+	}
+	return reqctx.WithActor(ctx, reqctx.AnonymousActor)
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return strings.TrimSpace(values[0])
+	}
+	return ""
+}
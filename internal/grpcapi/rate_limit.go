@@ -0,0 +1,91 @@
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"math"
+	"net"
+	"strconv"
+	swiftcodesv1 "swift-codes-api/api/proto/swiftcodes/v1"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/problems"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// writeMethods draw from the write budget, like the REST requests other
+// than GET and HEAD. Every other method is a read.
+var writeMethods = map[string]bool{
+	swiftcodesv1.SwiftCodes_AddSwiftCode_FullMethodName:    true,
+	swiftcodesv1.SwiftCodes_DeleteSwiftCode_FullMethodName: true,
+}
+
+func unaryRateLimit(cfg config.Config, store ratelimit.Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		retryAfter, err := takeToken(ctx, cfg, store, info.FullMethod)
+		if err != nil {
+			_ = grpc.SetHeader(ctx, retryAfter)
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamRateLimit takes a single token when a stream opens, however many
+// messages it then carries.
+func streamRateLimit(cfg config.Config, store ratelimit.Store) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		retryAfter, err := takeToken(ss.Context(), cfg, store, info.FullMethod)
+		if err != nil {
+			_ = ss.SetHeader(retryAfter)
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// takeToken applies the budgets of the RateLimit middleware to a call. Calls
+// over budget fail with a rate-limited status, and the returned metadata
+// carries the retry-after seconds. Calls are let through if the store fails.
+func takeToken(ctx context.Context, cfg config.Config, store ratelimit.Store, method string) (metadata.MD, error) {
+	scope, limit := "read", cfg.RateLimitRead
+	if writeMethods[method] {
+		scope, limit = "write", cfg.RateLimitWrite
+	}
+	if !limit.Enabled() {
+		return nil, nil
+	}
+
+	result, err := store.Take(ctx, scope+":"+clientKey(ctx, cfg), limit, time.Now())
+	if err != nil {
+		log.Printf("Rate limit store failed, allowing call: %v", err)
+		return nil, nil
+	}
+	if result.Allowed {
+		return nil, nil
+	}
+
+	retryAfter := metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+	return retryAfter, statusError(problems.RateLimited, "Rate limit of "+limit.String()+" exceeded", nil)
+}
+
+// clientKey identifies the caller by its API key or, without a known one, by
+// the address of its connection.
+func clientKey(ctx context.Context, cfg config.Config) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := first(md, apiKeyMetadata)
+	_, known := cfg.APIKeys[key]
+
+	var ip string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	return ratelimit.ClientKey(key, known, ip)
+}
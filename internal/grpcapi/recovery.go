@@ -0,0 +1,40 @@
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"swift-codes-api/problems"
+
+	"google.golang.org/grpc"
+)
+
+// unaryRecover fails a call whose handler panics with an internal error,
+// like gin's Recovery middleware does for REST, instead of letting the panic
+// take the process down.
+func unaryRecover() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func streamRecover() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+func recovered(method string, r any) error {
+	log.Printf("Panic in %s: %v\n%s", method, r, debug.Stack())
+	return statusError(problems.Internal, "Internal server error", nil)
+}
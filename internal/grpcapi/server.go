@@ -0,0 +1,179 @@
+// Package grpcapi serves the SWIFT code operations over gRPC, next to the
+// REST API and from the same repository.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	swiftcodesv1 "swift-codes-api/api/proto/swiftcodes/v1"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+
+	"google.golang.org/grpc"
+)
+
+type Server struct {
+	swiftcodesv1.UnimplementedSwiftCodesServer
+	repo interfaces.SwiftRepository
+}
+
+// NewServer returns a gRPC server with the SwiftCodes service registered,
+// callers authenticated by the API keys of cfg, rate limited with the
+// buckets of store (in memory when nil) and panics recovered.
+func NewServer(cfg config.Config, repo interfaces.SwiftRepository, store ratelimit.Store) *grpc.Server {
+	if store == nil {
+		store = ratelimit.NewMemoryStore()
+	}
+
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryRecover(), unaryAuthenticate(cfg), unaryRateLimit(cfg, store)),
+		grpc.ChainStreamInterceptor(streamRecover(), streamAuthenticate(cfg), streamRateLimit(cfg, store)),
+	)
+	swiftcodesv1.RegisterSwiftCodesServer(s, &Server{repo: repo})
+	return s
+}
+
+func (s *Server) GetSwiftCode(ctx context.Context, req *swiftcodesv1.GetSwiftCodeRequest) (*swiftcodesv1.GetSwiftCodeResponse, error) {
+	code := req.GetSwiftCode()
+	if !utils.ValidateSwiftCode(code) {
+		return nil, statusError(problems.InvalidSwiftCode, utils.InvalidSwiftCodeMessage, nil)
+	}
+
+	result, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, lookupFailed(err, problems.SwiftCodeNotFound, "No SWIFT code "+code+" exists")
+	}
+
+	response := &swiftcodesv1.GetSwiftCodeResponse{SwiftCode: toProto(*result)}
+	if result.IsHeadquarter {
+		// As in REST, a headquarter whose branches cannot be listed is
+		// returned on its own.
+		branches, _ := s.repo.FindBranchesByPrefix(ctx, result.SwiftPrefix)
+		for _, branch := range branches {
+			response.Branches = append(response.Branches, toProto(branch))
+		}
+	}
+	return response, nil
+}
+
+func (s *Server) ListByCountry(ctx context.Context, req *swiftcodesv1.ListByCountryRequest) (*swiftcodesv1.ListByCountryResponse, error) {
+	countryISO2, err := countryParam(req.GetCountryIso2())
+	if err != nil {
+		return nil, err
+	}
+
+	swiftCodes, countryName, err := s.repo.FindByCountryISO2(ctx, countryISO2)
+	if err != nil {
+		return nil, lookupFailed(err, problems.CountryNotFound, "No SWIFT codes found for country "+countryISO2)
+	}
+
+	response := &swiftcodesv1.ListByCountryResponse{CountryIso2: countryISO2, CountryName: countryName}
+	for _, swiftCode := range swiftCodes {
+		response.SwiftCodes = append(response.SwiftCodes, toProto(swiftCode))
+	}
+	return response, nil
+}
+
+func (s *Server) StreamSwiftCodes(req *swiftcodesv1.StreamSwiftCodesRequest, stream grpc.ServerStreamingServer[swiftcodesv1.SwiftCode]) error {
+	var countryISO2 string
+	if req.GetCountryIso2() != "" {
+		var err error
+		if countryISO2, err = countryParam(req.GetCountryIso2()); err != nil {
+			return err
+		}
+	}
+
+	err := s.repo.StreamSwiftCodes(stream.Context(), countryISO2, func(swiftCode models.SwiftCode) error {
+		return stream.Send(toProto(swiftCode))
+	})
+	if err != nil {
+		if stream.Context().Err() != nil {
+			return stream.Context().Err()
+		}
+		return failed(err, "Failed to stream SWIFT codes")
+	}
+	return nil
+}
+
+func (s *Server) AddSwiftCode(ctx context.Context, req *swiftcodesv1.AddSwiftCodeRequest) (*swiftcodesv1.AddSwiftCodeResponse, error) {
+	swiftCode := fromProto(req.GetSwiftCode())
+	if errs := utils.ValidateSwiftCodeRecord(&swiftCode); len(errs) > 0 {
+		return nil, statusError(problems.ValidationFailed, "One or more fields are invalid", errs)
+	}
+
+	if err := s.repo.AddSwiftCode(ctx, swiftCode); err != nil {
+		return nil, failed(err, "Failed to add SWIFT code")
+	}
+	return &swiftcodesv1.AddSwiftCodeResponse{}, nil
+}
+
+func (s *Server) DeleteSwiftCode(ctx context.Context, req *swiftcodesv1.DeleteSwiftCodeRequest) (*swiftcodesv1.DeleteSwiftCodeResponse, error) {
+	code := req.GetSwiftCode()
+	if !utils.ValidateSwiftCode(code) {
+		return nil, statusError(problems.InvalidSwiftCode, utils.InvalidSwiftCodeMessage, nil)
+	}
+
+	revision := interfaces.AnyRevision
+	if req.ExpectedRevision != nil {
+		revision = req.GetExpectedRevision()
+	}
+
+	err := s.repo.DeleteSwiftCode(ctx, code, revision)
+	switch {
+	case err == nil:
+		return &swiftcodesv1.DeleteSwiftCodeResponse{}, nil
+	case errors.Is(err, interfaces.ErrNotFound):
+		return nil, statusError(problems.SwiftCodeNotFound, "No SWIFT code "+code+" exists", nil)
+	default:
+		return nil, failed(err, "Failed to delete SWIFT code")
+	}
+}
+
+func countryParam(countryISO2 string) (string, error) {
+	if !utils.ValidateCountryCode(countryISO2) {
+		return "", statusError(problems.InvalidCountryCode, utils.InvalidCountryCodeMessage, nil)
+	}
+	return strings.ToUpper(countryISO2), nil
+}
+
+func lookupFailed(err error, notFound problems.Code, detail string) error {
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return statusError(notFound, detail, nil)
+	}
+	return failed(err, "Failed to look up SWIFT codes")
+}
+
+// failed reports a failed repository call as internal, unless
+// problems.FromError knows the error better.
+func failed(err error, detail string) error {
+	code, detail := problems.FromError(err, problems.Internal, detail)
+	return statusError(code, detail, nil)
+}
+
+func toProto(swiftCode models.SwiftCode) *swiftcodesv1.SwiftCode {
+	return &swiftcodesv1.SwiftCode{
+		SwiftCode:     swiftCode.SwiftCode,
+		IsHeadquarter: swiftCode.IsHeadquarter,
+		BankName:      swiftCode.BankName,
+		Address:       swiftCode.Address,
+		CountryIso2:   swiftCode.CountryISO2,
+		CountryName:   swiftCode.CountryName,
+		Revision:      swiftCode.Revision,
+	}
+}
+
+func fromProto(swiftCode *swiftcodesv1.SwiftCode) models.SwiftCode {
+	return models.SwiftCode{
+		SwiftCode:     swiftCode.GetSwiftCode(),
+		IsHeadquarter: swiftCode.GetIsHeadquarter(),
+		BankName:      swiftCode.GetBankName(),
+		Address:       swiftCode.GetAddress(),
+		CountryISO2:   swiftCode.GetCountryIso2(),
+		CountryName:   swiftCode.GetCountryName(),
+	}
+}
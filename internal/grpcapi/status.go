@@ -0,0 +1,55 @@
+package grpcapi
+
+import (
+	"swift-codes-api/problems"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo detail attached to every error.
+const ErrorDomain = "swift-codes-api"
+
+var grpcCodes = map[problems.Code]codes.Code{
+	problems.InvalidSwiftCode:   codes.InvalidArgument,
+	problems.InvalidCountryCode: codes.InvalidArgument,
+	problems.ValidationFailed:   codes.InvalidArgument,
+	problems.SwiftCodeNotFound:  codes.NotFound,
+	problems.CountryNotFound:    codes.NotFound,
+	problems.SwiftCodeExists:    codes.AlreadyExists,
+	problems.RevisionMismatch:   codes.Aborted,
+	problems.Internal:           codes.Internal,
+	problems.NotImplemented:     codes.Unimplemented,
+	problems.ServiceUnavailable: codes.Unavailable,
+	problems.RateLimited:        codes.ResourceExhausted,
+}
+
+// statusError maps a problem to a gRPC status. The problem code travels as
+// the reason of an ErrorInfo detail and field errors as a BadRequest detail.
+func statusError(code problems.Code, detail string, fields []problems.FieldError) error {
+	grpcCode, ok := grpcCodes[code]
+	if !ok {
+		grpcCode = codes.Unknown
+	}
+
+	st, err := status.New(grpcCode, detail).WithDetails(&errdetails.ErrorInfo{Reason: string(code), Domain: ErrorDomain})
+	if err != nil {
+		return status.Error(grpcCode, detail)
+	}
+
+	if len(fields) > 0 {
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+				Reason:      field.Code,
+			})
+		}
+		if withFields, err := st.WithDetails(badRequest); err == nil {
+			st = withFields
+		}
+	}
+	return st.Err()
+}
@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+)
+
+const (
+	// BatchSize is the number of rows looked up together and applied between
+	// two checkpoints.
+	BatchSize = 500
+	// MaxRowErrors caps the row errors kept on a job; Failed counts them all.
+	MaxRowErrors = 100
+)
+
+type row struct {
+	number    int
+	swiftCode models.SwiftCode
+}
+
+// Import loads the records of file into repo, adding the SWIFT codes that do
+// not exist and updating those that differ, and tallies the outcome in job.
+// The first job.Processed rows are skipped, so that a job interrupted after a
+// checkpoint resumes where it left off. checkpoint is called after every
+// batch with job up to date.
+func Import(ctx context.Context, repo interfaces.SwiftRepository, job *models.ImportJob, file io.Reader, checkpoint func() error) error {
+	counter := &countingReader{r: file}
+	records, err := NewReader(counter, Format(job.Format))
+	if err != nil {
+		return err
+	}
+
+	batch := make([]row, 0, BatchSize)
+	flush := func(number int) error {
+		if err := apply(ctx, repo, job, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		job.Processed = number
+		job.BytesRead = counter.n
+		return checkpoint()
+	}
+
+	number := 0
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		swiftCode, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *RowError
+		if err != nil && !errors.As(err, &rowErr) {
+			return err
+		}
+
+		number++
+		if number <= job.Processed {
+			continue
+		}
+
+		if rowErr != nil {
+			fail(job, number, "", rowErr.Error())
+		} else if errs := utils.ValidateSwiftCodeRecord(&swiftCode); len(errs) > 0 {
+			fail(job, number, swiftCode.SwiftCode, fieldErrors(errs))
+		} else {
+			batch = append(batch, row{number: number, swiftCode: swiftCode})
+		}
+
+		if number-job.Processed >= BatchSize {
+			if err = flush(number); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush(max(number, job.Processed))
+}
+
+// apply writes a batch, looking up the current records in one call so that
+// unchanged rows cost no write.
+func apply(ctx context.Context, repo interfaces.SwiftRepository, job *models.ImportJob, batch []row) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	codes := make([]string, len(batch))
+	for i, r := range batch {
+		codes[i] = r.swiftCode.SwiftCode
+	}
+
+	found, err := repo.FindByCodes(ctx, codes)
+	if err != nil {
+		return err
+	}
+	current := make(map[string]models.SwiftCode, len(found))
+	for _, swiftCode := range found {
+		current[swiftCode.SwiftCode] = swiftCode
+	}
+
+	for _, r := range batch {
+		existing, ok := current[r.swiftCode.SwiftCode]
+		switch {
+		case ok && sameRecord(existing, r.swiftCode):
+			job.Unchanged++
+		case ok:
+			if err = repo.UpdateSwiftCode(ctx, r.swiftCode, interfaces.AnyRevision); err != nil {
+				return err
+			}
+			job.Updated++
+		default:
+			if err = add(ctx, repo, job, r.swiftCode); err != nil {
+				return err
+			}
+		}
+		current[r.swiftCode.SwiftCode] = r.swiftCode
+	}
+	return nil
+}
+
+// add creates a SWIFT code, updating it instead when it was created since the
+// batch was looked up.
+func add(ctx context.Context, repo interfaces.SwiftRepository, job *models.ImportJob, swiftCode models.SwiftCode) error {
+	err := repo.AddSwiftCode(ctx, swiftCode)
+	if errors.Is(err, interfaces.ErrAlreadyExists) {
+		if err = repo.UpdateSwiftCode(ctx, swiftCode, interfaces.AnyRevision); err != nil {
+			return err
+		}
+		job.Updated++
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	job.Created++
+	return nil
+}
+
+func sameRecord(a, b models.SwiftCode) bool {
+	return a.BankName == b.BankName &&
+		a.Address == b.Address &&
+		a.CountryISO2 == b.CountryISO2 &&
+		a.CountryName == b.CountryName &&
+		a.IsHeadquarter == b.IsHeadquarter
+}
+
+func fail(job *models.ImportJob, number int, swiftCode, message string) {
+	job.Failed++
+	if len(job.Errors) < MaxRowErrors {
+		job.Errors = append(job.Errors, models.ImportRowError{Row: number, SwiftCode: swiftCode, Message: message})
+	}
+}
+
+func fieldErrors(errs []problems.FieldError) string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strconv"
+	"strings"
+	"swift-codes-api/dto"
+	"swift-codes-api/internal/export"
+	"swift-codes-api/models"
+)
+
+// Format is the layout of a directory file. The formats are those of
+// /v1/export, so that an export can be imported as is.
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+var (
+	ErrEmptyFile = errors.New("the file is empty")
+
+	mediaTypeToFormat = map[string]Format{
+		"application/json":     FormatJSON,
+		"application/x-ndjson": FormatNDJSON,
+		"application/ndjson":   FormatNDJSON,
+		"text/csv":             FormatCSV,
+	}
+)
+
+func ParseFormat(format string) (Format, bool) {
+	switch f := Format(strings.ToLower(format)); f {
+	case FormatJSON, FormatNDJSON, FormatCSV:
+		return f, true
+	}
+	return "", false
+}
+
+// DetectFormat recognises the format of an upload from its media type or,
+// failing that, the extension of its file name.
+func DetectFormat(contentType, fileName string) (Format, bool) {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		if format, ok := mediaTypeToFormat[mediaType]; ok {
+			return format, true
+		}
+	}
+	return ParseFormat(strings.TrimPrefix(path.Ext(fileName), "."))
+}
+
+// RowError is a record that could not be decoded. Reading can continue with
+// the next record.
+type RowError struct {
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return e.Err.Error()
+}
+
+// Reader decodes the records of a directory file one at a time.
+type Reader interface {
+	// Read returns the next record, a *RowError when it cannot be decoded or
+	// io.EOF after the last one. Any other error ends the file.
+	Read() (models.SwiftCode, error)
+}
+
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatJSON:
+		return newJSONReader(r)
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		return &ndjsonReader{scanner: scanner}, nil
+	case FormatCSV:
+		return newCSVReader(r)
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+type jsonReader struct {
+	dec *json.Decoder
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	if token != json.Delim('[') {
+		return nil, errors.New("a JSON file must hold an array of SWIFT codes")
+	}
+	return &jsonReader{dec: dec}, nil
+}
+
+func (r *jsonReader) Read() (models.SwiftCode, error) {
+	if !r.dec.More() {
+		if _, err := r.dec.Token(); err != nil {
+			return models.SwiftCode{}, err
+		}
+		return models.SwiftCode{}, io.EOF
+	}
+
+	var request dto.SwiftCodeRequest
+	err := r.dec.Decode(&request)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return models.SwiftCode{}, &RowError{Err: err}
+	}
+	if err != nil {
+		return models.SwiftCode{}, err
+	}
+	return request.ToModel(), nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *ndjsonReader) Read() (models.SwiftCode, error) {
+	for r.scanner.Scan() {
+		line := strings.TrimSpace(r.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var request dto.SwiftCodeRequest
+		if err := json.Unmarshal([]byte(line), &request); err != nil {
+			return models.SwiftCode{}, &RowError{Err: err}
+		}
+		return request.ToModel(), nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return models.SwiftCode{}, err
+	}
+	return models.SwiftCode{}, io.EOF
+}
+
+var csvColumns = []string{"swiftCode", "bankName", "address", "countryISO2", "countryName", "isHeadquarter"}
+
+// csvReader reads the columns of the CSV export in any order. isHeadquarter
+// may be left out, in which case codes ending in XXX are headquarters.
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for _, column := range csvColumns {
+			if strings.EqualFold(name, column) {
+				columns[column] = i
+			}
+		}
+	}
+
+	for _, column := range csvColumns[:5] {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("the CSV header has no %s column", column)
+		}
+	}
+	return &csvReader{r: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Read() (models.SwiftCode, error) {
+	record, err := r.r.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return models.SwiftCode{}, &RowError{Err: err}
+	}
+	if err != nil {
+		return models.SwiftCode{}, err
+	}
+
+	field := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(export.UnescapeCSVCell(record[i]))
+		}
+		return ""
+	}
+
+	swiftCode := models.SwiftCode{
+		SwiftCode:     field("swiftCode"),
+		BankName:      field("bankName"),
+		Address:       field("address"),
+		CountryISO2:   field("countryISO2"),
+		CountryName:   field("countryName"),
+		IsHeadquarter: strings.HasSuffix(field("swiftCode"), "XXX"),
+	}
+	if raw := field("isHeadquarter"); raw != "" {
+		if swiftCode.IsHeadquarter, err = strconv.ParseBool(raw); err != nil {
+			return models.SwiftCode{}, &RowError{Err: errors.New("isHeadquarter must be true or false")}
+		}
+	}
+	return swiftCode, nil
+}
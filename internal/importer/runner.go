@@ -0,0 +1,230 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/utils"
+	"sync"
+	"time"
+)
+
+const (
+	claimLease   = time.Minute
+	pollInterval = 5 * time.Second
+)
+
+var errCancelled = errors.New("import cancelled")
+
+// Runner runs import jobs on a pool of workers. Jobs and their files are kept
+// in the repository, so any replica can run them, and a job interrupted by a
+// restart is resumed from its last checkpoint once its lease runs out.
+type Runner struct {
+	jobs    interfaces.ImportRepository
+	repo    interfaces.SwiftRepository
+	workers int
+	wake    chan struct{}
+
+	mu      sync.Mutex
+	running map[string]context.CancelCauseFunc
+}
+
+func NewRunner(jobs interfaces.ImportRepository, repo interfaces.SwiftRepository, workers int) *Runner {
+	return &Runner{
+		jobs:    jobs,
+		repo:    repo,
+		workers: max(workers, 1),
+		wake:    make(chan struct{}, 1),
+		running: map[string]context.CancelCauseFunc{},
+	}
+}
+
+func (r *Runner) Start(ctx context.Context) {
+	for i := 0; i < r.workers; i++ {
+		go r.work(ctx)
+	}
+}
+
+func (r *Runner) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			ran, err := r.RunNext(ctx)
+			if err != nil {
+				log.Printf("Failed to run import job: %v", err)
+			}
+			if !ran || err != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// Submit stores an uploaded file and queues a job to import it on behalf of
+// the caller.
+func (r *Runner) Submit(ctx context.Context, fileName string, format Format, file io.Reader) (*models.ImportJob, error) {
+	now := time.Now().UTC()
+	job := models.ImportJob{
+		ID:         utils.NewID(),
+		Status:     models.ImportStatusQueued,
+		Format:     string(format),
+		FileName:   fileName,
+		Errors:     []models.ImportRowError{},
+		Actor:      reqctx.Actor(ctx),
+		LeaseUntil: now,
+		CreatedAt:  now,
+	}
+
+	size, err := r.jobs.SaveFile(ctx, job.ID, fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	job.Size = size
+
+	if err = r.jobs.CreateJob(ctx, job); err != nil {
+		_ = r.jobs.DeleteFile(ctx, job.ID)
+		return nil, err
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return &job, nil
+}
+
+func (r *Runner) Find(ctx context.Context, id string) (*models.ImportJob, error) {
+	return r.jobs.FindJob(ctx, id)
+}
+
+// Cancel stops a queued or running job. A job running on another replica
+// stops at its next checkpoint.
+func (r *Runner) Cancel(ctx context.Context, id string) (*models.ImportJob, error) {
+	job, err := r.jobs.CancelJob(ctx, id, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	// No worker is going to run a job cancelled while queued, nor delete its
+	// file.
+	if job.Status == models.ImportStatusCancelled {
+		if err := r.jobs.DeleteFile(ctx, id); err != nil {
+			log.Printf("Failed to delete the file of import job %s: %v", id, err)
+		}
+	}
+
+	r.mu.Lock()
+	if cancel, ok := r.running[id]; ok {
+		cancel(errCancelled)
+	}
+	r.mu.Unlock()
+
+	return job, nil
+}
+
+// RunNext claims a job and runs it to the end, reporting whether there was
+// one to run.
+func (r *Runner) RunNext(ctx context.Context) (bool, error) {
+	job, err := r.jobs.ClaimJob(ctx, time.Now().UTC(), claimLease)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, r.run(ctx, *job)
+}
+
+func (r *Runner) run(ctx context.Context, job models.ImportJob) error {
+	if job.CancelRequested {
+		return r.finish(ctx, job, models.ImportStatusCancelled, "")
+	}
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	r.track(job.ID, cancel)
+	defer r.untrack(job.ID)
+	jobCtx = reqctx.WithRequestID(reqctx.WithActor(jobCtx, job.Actor), job.ID)
+
+	file, err := r.jobs.OpenFile(jobCtx, job.ID)
+	if err == nil {
+		err = Import(jobCtx, r.repo, &job, file, func() error {
+			return r.checkpoint(jobCtx, &job, cancel)
+		})
+		file.Close()
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// Shutting down: another worker resumes the job once the lease runs out.
+		return nil
+	case errors.Is(err, interfaces.ErrJobReclaimed):
+		log.Printf("Import job %s was taken over by another worker", job.ID)
+		return nil
+	case errors.Is(context.Cause(jobCtx), errCancelled):
+		return r.finish(ctx, job, models.ImportStatusCancelled, "")
+	case err != nil:
+		return r.finish(ctx, job, models.ImportStatusFailed, err.Error())
+	}
+	return r.finish(ctx, job, models.ImportStatusSucceeded, "")
+}
+
+// checkpoint stores the progress of a job and renews its lease, stopping the
+// job when it has been cancelled meanwhile.
+func (r *Runner) checkpoint(ctx context.Context, job *models.ImportJob, cancel context.CancelCauseFunc) error {
+	job.LeaseUntil = time.Now().UTC().Add(claimLease)
+	stored, err := r.jobs.UpdateJob(ctx, *job)
+	if err != nil {
+		return err
+	}
+	if stored.CancelRequested {
+		cancel(errCancelled)
+		return errCancelled
+	}
+	return nil
+}
+
+func (r *Runner) finish(ctx context.Context, job models.ImportJob, status, message string) error {
+	now := time.Now().UTC()
+	job.Status = status
+	job.Error = message
+	job.LeaseUntil = now
+	job.FinishedAt = &now
+
+	_, err := r.jobs.UpdateJob(ctx, job)
+	if errors.Is(err, interfaces.ErrJobReclaimed) {
+		log.Printf("Import job %s was taken over by another worker", job.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := r.jobs.DeleteFile(ctx, job.ID); err != nil {
+		log.Printf("Failed to delete the file of import job %s: %v", job.ID, err)
+	}
+	return nil
+}
+
+func (r *Runner) track(id string, cancel context.CancelCauseFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.running[id] = cancel
+}
+
+func (r *Runner) untrack(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.running, id)
+}
@@ -0,0 +1,45 @@
+package purge
+
+import (
+	"context"
+	"log"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+// Start permanently removes soft-deleted SWIFT codes older than retention,
+// checking every interval until ctx is cancelled.
+func Start(ctx context.Context, repo interfaces.SwiftRepository, retention, interval time.Duration) {
+	if retention <= 0 || interval <= 0 {
+		return
+	}
+
+	ctx = reqctx.WithActor(ctx, reqctx.SystemActor)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			Run(ctx, repo, retention)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func Run(ctx context.Context, repo interfaces.SwiftRepository, retention time.Duration) {
+	purged, err := repo.PurgeDeletedSwiftCodes(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		log.Printf("Failed to purge deleted SWIFT codes: %v", err)
+		return
+	}
+	if len(purged) > 0 {
+		log.Printf("Purged %d deleted SWIFT codes", len(purged))
+	}
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoStore keeps buckets in a MongoDB collection so that replicas share
+// one budget per client. Each Take is a single atomic update.
+type MongoStore struct {
+	col *mongo.Collection
+}
+
+// NewMongoStore returns a store backed by col, which gets a TTL index so that
+// idle buckets are removed once they would have refilled.
+func NewMongoStore(col *mongo.Collection) *MongoStore {
+	_, err := col.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		log.Printf("Failed to create the rate limit TTL index: %v", err)
+	}
+	return &MongoStore{col: col}
+}
+
+func (s *MongoStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	capacity := float64(limit.Requests)
+	elapsed := bson.M{"$divide": bson.A{
+		bson.M{"$subtract": bson.A{now, bson.M{"$ifNull": bson.A{"$updatedAt", now}}}},
+		1000,
+	}}
+	refilled := bson.M{"$min": bson.A{
+		capacity,
+		bson.M{"$add": bson.A{
+			bson.M{"$ifNull": bson.A{"$tokens", capacity}},
+			bson.M{"$multiply": bson.A{bson.M{"$max": bson.A{elapsed, 0}}, limit.rate()}},
+		}},
+	}}
+	hasToken := bson.M{"$gte": bson.A{"$tokens", 1}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"tokens": refilled, "updatedAt": now}}},
+		{{Key: "$set", Value: bson.M{
+			"allowed": hasToken,
+			"tokens":  bson.M{"$cond": bson.A{hasToken, bson.M{"$subtract": bson.A{"$tokens", 1}}, "$tokens"}},
+		}}},
+		{{Key: "$set", Value: bson.M{"expiresAt": now.Add(limit.Window)}}},
+	}
+
+	var doc struct {
+		Tokens  float64 `bson:"tokens"`
+		Allowed bool    `bson:"allowed"`
+	}
+	err := s.col.FindOneAndUpdate(ctx, bson.M{"_id": key}, pipeline,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(limit, doc.Allowed, doc.Tokens), nil
+}
@@ -0,0 +1,73 @@
+package reqctx
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey string
+
+const (
+	requestIDKey      contextKey = "requestID"
+	actorKey          contextKey = "actor"
+	adminKey          contextKey = "admin"
+	includeDeletedKey contextKey = "includeDeleted"
+	staleKey          contextKey = "stale"
+)
+
+const (
+	AnonymousActor = "anonymous"
+	SystemActor    = "system"
+)
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+func Actor(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey).(string); ok && actor != "" {
+		return actor
+	}
+	return AnonymousActor
+}
+
+func WithAdmin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, adminKey, true)
+}
+
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey).(bool)
+	return admin
+}
+
+// WithIncludeDeleted makes repository reads return soft-deleted records too.
+func WithIncludeDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey, true)
+}
+
+func IncludeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey).(bool)
+	return include
+}
+
+// WithStaleHandler has MarkStale call fn.
+func WithStaleHandler(ctx context.Context, fn func(fetchedAt time.Time)) context.Context {
+	return context.WithValue(ctx, staleKey, fn)
+}
+
+// MarkStale reports that a repository answered with data fetched at
+// fetchedAt, because it could not reach the database.
+func MarkStale(ctx context.Context, fetchedAt time.Time) {
+	if fn, ok := ctx.Value(staleKey).(func(time.Time)); ok {
+		fn(fetchedAt)
+	}
+}
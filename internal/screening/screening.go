@@ -0,0 +1,195 @@
+// Package screening annotates CSV files of beneficiaries with the result of
+// checking their BICs against the directory, one batch of rows at a time so
+// that files of any size can be streamed.
+package screening
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"swift-codes-api/internal/export"
+	"swift-codes-api/models"
+	"swift-codes-api/utils"
+)
+
+// Columns appended to every row.
+var Columns = []string{"bicValid", "bicBankName", "bicCountry", "bicHeadquarter", "bicReason"}
+
+// Reasons a BIC is not valid, reported in the bicReason column.
+const (
+	ReasonMissing        = "missing"
+	ReasonInvalid        = "invalid-swift-code"
+	ReasonUnknownCountry = "unknown-country"
+	ReasonNotFound       = "swift-code-not-found"
+)
+
+const DefaultBatchSize = 500
+
+var ErrEmptyFile = errors.New("CSV file is empty")
+
+// ColumnError reports that the BIC column is not in the header row.
+type ColumnError struct {
+	Column string
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("column %q is not in the CSV header", e.Column)
+}
+
+// Lookup finds the records of those codes that exist, as
+// interfaces.SwiftRepository.FindByCodes does.
+type Lookup func(ctx context.Context, codes []string) ([]models.SwiftCode, error)
+
+type Options struct {
+	// Column is the header of the column holding the BICs, matched
+	// case-insensitively.
+	Column    string
+	Comma     rune
+	BatchSize int
+}
+
+// Screener reads a CSV file whose header row has been checked by New.
+type Screener struct {
+	r      *csv.Reader
+	header []string
+	column int
+	opts   Options
+}
+
+// New reads the header row of r and finds the BIC column in it.
+func New(r io.Reader, opts Options) (*Screener, error) {
+	if opts.Comma == 0 {
+		opts.Comma = ','
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Comma
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrEmptyFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Spreadsheet applications often start UTF-8 files with a byte order mark.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), opts.Column) {
+			return &Screener{r: reader, header: header, column: i, opts: opts}, nil
+		}
+	}
+	return nil, &ColumnError{Column: opts.Column}
+}
+
+// Run writes the file to w with the screening columns appended to every row,
+// looking up the BICs of each batch of rows with a single call to lookup.
+func (s *Screener) Run(ctx context.Context, lookup Lookup, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = s.opts.Comma
+
+	if err := writer.Write(append(s.header, Columns...)); err != nil {
+		return err
+	}
+
+	batch := make([][]string, 0, s.opts.BatchSize)
+	for {
+		row, err := s.r.Read()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if row != nil {
+			batch = append(batch, row)
+		}
+
+		if len(batch) == s.opts.BatchSize || (errors.Is(err, io.EOF) && len(batch) > 0) {
+			if err := s.writeBatch(ctx, lookup, writer, batch); err != nil {
+				return err
+			}
+			if f, ok := w.(interface{ Flush() }); ok {
+				f.Flush()
+			}
+			batch = batch[:0]
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+func (s *Screener) writeBatch(ctx context.Context, lookup Lookup, writer *csv.Writer, batch [][]string) error {
+	codes := make([]string, len(batch))
+	var lookups []string
+	seen := make(map[string]bool)
+	for i, row := range batch {
+		if s.column >= len(row) {
+			continue
+		}
+		code, ok := utils.NormalizeSwiftCode(row[s.column])
+		if !ok {
+			continue
+		}
+		codes[i] = code
+		for _, c := range []string{code, code[:8] + "XXX"} {
+			if !seen[c] {
+				seen[c] = true
+				lookups = append(lookups, c)
+			}
+		}
+	}
+
+	found := make(map[string]models.SwiftCode)
+	if len(lookups) > 0 {
+		swiftCodes, err := lookup(ctx, lookups)
+		if err != nil {
+			return err
+		}
+		for _, swiftCode := range swiftCodes {
+			found[swiftCode.SwiftCode] = swiftCode
+		}
+	}
+
+	for i, row := range batch {
+		if err := writer.Write(append(row, annotate(row, s.column, codes[i], found)...)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// annotate returns the screening columns of a row whose BIC normalises to
+// code, which is empty when the BIC is missing or malformed.
+func annotate(row []string, column int, code string, found map[string]models.SwiftCode) []string {
+	var bankName, country, headquarter, reason string
+	if code != "" {
+		if _, ok := found[code[:8]+"XXX"]; ok {
+			headquarter = code[:8] + "XXX"
+		}
+	}
+
+	record, ok := found[code]
+	switch {
+	case column >= len(row) || strings.TrimSpace(row[column]) == "":
+		reason = ReasonMissing
+	case code == "":
+		reason = ReasonInvalid
+	case !utils.CountryExists(code[4:6]):
+		reason = ReasonUnknownCountry
+	case !ok:
+		reason = ReasonNotFound
+	default:
+		bankName, country = export.EscapeCSVCell(record.BankName), export.EscapeCSVCell(record.CountryName)
+	}
+
+	return []string{strconv.FormatBool(reason == ""), bankName, country, headquarter, reason}
+}
@@ -0,0 +1,87 @@
+// Package snapshot reads and writes compact, immutable binary snapshots of
+// the SWIFT code directory.
+//
+// A snapshot is a header followed by five sections, all little-endian:
+//
+//	records    one fixed-size record per code, sorted by code
+//	prefixes   the first record and number of records of each bank prefix
+//	countries  the first posting and number of postings of each country
+//	postings   record numbers grouped by country, each group sorted by code
+//	strings    the text of codes, names and addresses, each stored once
+//
+// Records refer to their text by offset and length in the string table, so
+// everything but the string table is read in place from the mapped file.
+package snapshot
+
+import "errors"
+
+const (
+	magic   = "SWIFTSNP"
+	version = 1
+
+	headerSize  = 64
+	recordSize  = 64
+	prefixSize  = 16
+	countrySize = 16
+	postingSize = 4
+
+	prefixLength = 8
+)
+
+// Header layout.
+const (
+	hMagic     = 0  // [8]byte
+	hVersion   = 8  // uint32
+	hRecords   = 12 // uint32
+	hPrefixes  = 16 // uint32
+	hCountries = 20 // uint32
+	hStrings   = 24 // uint32, bytes
+	hChecksum  = 28 // uint32, CRC-32 (IEEE) of everything after the header
+	hCreatedAt = 32 // int64, Unix nanoseconds
+)
+
+// Record layout. Text fields are a uint32 offset and a uint32 length.
+const (
+	rCode        = 0
+	rBankName    = 8
+	rAddress     = 16
+	rCountryName = 24
+	rCountryISO2 = 32
+	rFlags       = 40 // uint8
+	rRevision    = 48 // int64
+	rUpdatedAt   = 56 // int64, Unix nanoseconds
+)
+
+const flagHeadquarter = 1
+
+// Prefix index layout: the prefix, then the first record and the number of
+// records that start with it.
+const (
+	pPrefix = 0 // [8]byte
+	pFirst  = 8
+	pCount  = 12
+)
+
+// Country index layout: the country, then the first posting and the number
+// of postings of its records.
+const (
+	cCountryISO2 = 0 // [2]byte
+	cFirst       = 4
+	cCount       = 8
+)
+
+var ErrCorrupt = errors.New("snapshot file is corrupt or not a snapshot")
+
+type layout struct {
+	records, prefixes, countries, postings, strings, end int
+}
+
+func newLayout(records, prefixes, countries, strings int) layout {
+	l := layout{records: headerSize}
+	l.prefixes = l.records + records*recordSize
+	l.countries = l.prefixes + prefixes*prefixSize
+	l.postings = l.countries + countries*countrySize
+	l.strings = l.postings + records*postingSize
+	l.end = l.strings + strings
+	return l
+}
@@ -0,0 +1,201 @@
+package snapshot
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"sort"
+	"swift-codes-api/models"
+	"time"
+)
+
+// Snapshot is an open snapshot file, mapped into memory. Its string table is
+// copied once when it is opened, so that the strings of the records it
+// returns stay valid after Close.
+type Snapshot struct {
+	data      []byte
+	text      string
+	layout    layout
+	records   int
+	prefixes  int
+	countries int
+	createdAt time.Time
+	unmap     func() error
+}
+
+// Open maps the snapshot at path and checks its integrity.
+func Open(path string) (*Snapshot, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := parse(data)
+	if err != nil {
+		unmap()
+		return nil, err
+	}
+	s.unmap = unmap
+	return s, nil
+}
+
+func parse(data []byte) (*Snapshot, error) {
+	if len(data) < headerSize || string(data[hMagic:hMagic+len(magic)]) != magic ||
+		binary.LittleEndian.Uint32(data[hVersion:]) != version {
+		return nil, ErrCorrupt
+	}
+
+	s := &Snapshot{
+		data:      data,
+		records:   int(binary.LittleEndian.Uint32(data[hRecords:])),
+		prefixes:  int(binary.LittleEndian.Uint32(data[hPrefixes:])),
+		countries: int(binary.LittleEndian.Uint32(data[hCountries:])),
+		createdAt: time.Unix(0, int64(binary.LittleEndian.Uint64(data[hCreatedAt:]))).UTC(),
+	}
+	s.layout = newLayout(s.records, s.prefixes, s.countries, int(binary.LittleEndian.Uint32(data[hStrings:])))
+	if s.layout.end != len(data) || crc32.ChecksumIEEE(data[headerSize:]) != binary.LittleEndian.Uint32(data[hChecksum:]) {
+		return nil, ErrCorrupt
+	}
+	s.text = string(data[s.layout.strings:])
+
+	// Bounds are checked once here rather than on every lookup.
+	for i := range s.records {
+		record := s.record(i)
+		for _, field := range []int{rCode, rBankName, rAddress, rCountryName, rCountryISO2} {
+			offset, length := binary.LittleEndian.Uint32(record[field:]), binary.LittleEndian.Uint32(record[field+4:])
+			if uint64(offset)+uint64(length) > uint64(len(s.text)) {
+				return nil, ErrCorrupt
+			}
+		}
+		if len(s.code(i)) < prefixLength || i > 0 && s.code(i-1) >= s.code(i) {
+			return nil, ErrCorrupt
+		}
+	}
+	for i := range s.prefixes {
+		first, count := s.entry(s.layout.prefixes, prefixSize, i, pFirst, pCount)
+		if first+count > s.records {
+			return nil, ErrCorrupt
+		}
+	}
+	for i := range s.countries {
+		first, count := s.entry(s.layout.countries, countrySize, i, cFirst, cCount)
+		if first+count > s.records {
+			return nil, ErrCorrupt
+		}
+	}
+	for i := range s.records {
+		if s.posting(i) >= s.records {
+			return nil, ErrCorrupt
+		}
+	}
+	return s, nil
+}
+
+// Close unmaps the file. Records returned before remain valid.
+func (s *Snapshot) Close() error {
+	return s.unmap()
+}
+
+func (s *Snapshot) Len() int {
+	return s.records
+}
+
+func (s *Snapshot) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *Snapshot) record(i int) []byte {
+	offset := s.layout.records + i*recordSize
+	return s.data[offset : offset+recordSize]
+}
+
+func (s *Snapshot) str(field []byte) string {
+	offset := binary.LittleEndian.Uint32(field)
+	return s.text[offset : offset+binary.LittleEndian.Uint32(field[4:])]
+}
+
+func (s *Snapshot) code(i int) string {
+	return s.str(s.record(i)[rCode:])
+}
+
+func (s *Snapshot) entry(section, size, i, first, count int) (int, int) {
+	entry := s.data[section+i*size : section+(i+1)*size]
+	return int(binary.LittleEndian.Uint32(entry[first:])), int(binary.LittleEndian.Uint32(entry[count:]))
+}
+
+func (s *Snapshot) posting(i int) int {
+	return int(binary.LittleEndian.Uint32(s.data[s.layout.postings+i*postingSize:]))
+}
+
+// Record decodes the i-th record in code order.
+func (s *Snapshot) Record(i int) models.SwiftCode {
+	record := s.record(i)
+	code := s.str(record[rCode:])
+	swiftCode := models.SwiftCode{
+		SwiftCode:     code,
+		SwiftPrefix:   code[:prefixLength],
+		IsHeadquarter: record[rFlags]&flagHeadquarter != 0,
+		BankName:      s.str(record[rBankName:]),
+		Address:       s.str(record[rAddress:]),
+		CountryISO2:   s.str(record[rCountryISO2:]),
+		CountryName:   s.str(record[rCountryName:]),
+		Revision:      int64(binary.LittleEndian.Uint64(record[rRevision:])),
+	}
+	if updatedAt := int64(binary.LittleEndian.Uint64(record[rUpdatedAt:])); updatedAt != 0 {
+		swiftCode.UpdatedAt = time.Unix(0, updatedAt).UTC()
+	}
+	return swiftCode
+}
+
+// Find returns the number of the record of code.
+func (s *Snapshot) Find(code string) (int, bool) {
+	i := sort.Search(s.records, func(i int) bool {
+		return s.code(i) >= code
+	})
+	return i, i < s.records && s.code(i) == code
+}
+
+// Prefix returns the range of the records whose code starts with prefix.
+func (s *Snapshot) Prefix(prefix string) (first, end int) {
+	if len(prefix) != prefixLength {
+		return 0, 0
+	}
+
+	i := sort.Search(s.prefixes, func(i int) bool {
+		offset := s.layout.prefixes + i*prefixSize
+		return string(s.data[offset:offset+prefixLength]) >= prefix
+	})
+	if i == s.prefixes {
+		return 0, 0
+	}
+
+	offset := s.layout.prefixes + i*prefixSize
+	if string(s.data[offset:offset+prefixLength]) != prefix {
+		return 0, 0
+	}
+	first, count := s.entry(s.layout.prefixes, prefixSize, i, pFirst, pCount)
+	return first, first + count
+}
+
+// Country returns the range of the postings of the records of a country,
+// which Posting turns into record numbers.
+func (s *Snapshot) Country(countryISO2 string) (first, end int) {
+	i := sort.Search(s.countries, func(i int) bool {
+		offset := s.layout.countries + i*countrySize
+		return string(s.data[offset:offset+2]) >= countryISO2
+	})
+	if i == s.countries {
+		return 0, 0
+	}
+
+	offset := s.layout.countries + i*countrySize
+	if string(s.data[offset:offset+2]) != countryISO2 {
+		return 0, 0
+	}
+	first, count := s.entry(s.layout.countries, countrySize, i, cFirst, cCount)
+	return first, first + count
+}
+
+// Posting returns the record number of the i-th posting.
+func (s *Snapshot) Posting(i int) int {
+	return s.posting(i)
+}
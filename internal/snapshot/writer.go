@@ -0,0 +1,119 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"swift-codes-api/models"
+	"time"
+)
+
+// Write compiles swiftCodes into a snapshot taken at createdAt.
+func Write(w io.Writer, swiftCodes []models.SwiftCode, createdAt time.Time) error {
+	sorted := slices.Clone(swiftCodes)
+	slices.SortFunc(sorted, func(a, b models.SwiftCode) int {
+		return strings.Compare(a.SwiftCode, b.SwiftCode)
+	})
+
+	var table bytes.Buffer
+	offsets := map[string]uint32{}
+	text := func(record []byte, s string) {
+		offset, ok := offsets[s]
+		if !ok {
+			offset = uint32(table.Len())
+			offsets[s] = offset
+			table.WriteString(s)
+		}
+		binary.LittleEndian.PutUint32(record, offset)
+		binary.LittleEndian.PutUint32(record[4:], uint32(len(s)))
+	}
+
+	records := make([]byte, len(sorted)*recordSize)
+	var prefixes []byte
+	countries := map[string][]uint32{}
+	for i, swiftCode := range sorted {
+		if i > 0 && swiftCode.SwiftCode == sorted[i-1].SwiftCode {
+			return fmt.Errorf("SWIFT code %s appears twice", swiftCode.SwiftCode)
+		}
+		if len(swiftCode.SwiftCode) < prefixLength || len(swiftCode.CountryISO2) != 2 {
+			return fmt.Errorf("SWIFT code %s is not valid", swiftCode.SwiftCode)
+		}
+
+		record := records[i*recordSize : (i+1)*recordSize]
+		text(record[rCode:], swiftCode.SwiftCode)
+		text(record[rBankName:], swiftCode.BankName)
+		text(record[rAddress:], swiftCode.Address)
+		text(record[rCountryName:], swiftCode.CountryName)
+		text(record[rCountryISO2:], swiftCode.CountryISO2)
+		if swiftCode.IsHeadquarter {
+			record[rFlags] = flagHeadquarter
+		}
+		binary.LittleEndian.PutUint64(record[rRevision:], uint64(swiftCode.Revision))
+		if !swiftCode.UpdatedAt.IsZero() {
+			binary.LittleEndian.PutUint64(record[rUpdatedAt:], uint64(swiftCode.UpdatedAt.UnixNano()))
+		}
+
+		prefix := swiftCode.SwiftCode[:prefixLength]
+		if n := len(prefixes); n > 0 && string(prefixes[n-prefixSize:n-prefixSize+prefixLength]) == prefix {
+			count := prefixes[n-prefixSize+pCount:]
+			binary.LittleEndian.PutUint32(count, binary.LittleEndian.Uint32(count)+1)
+		} else {
+			entry := make([]byte, prefixSize)
+			copy(entry[pPrefix:], prefix)
+			binary.LittleEndian.PutUint32(entry[pFirst:], uint32(i))
+			binary.LittleEndian.PutUint32(entry[pCount:], 1)
+			prefixes = append(prefixes, entry...)
+		}
+
+		countries[swiftCode.CountryISO2] = append(countries[swiftCode.CountryISO2], uint32(i))
+	}
+	if table.Len() > math.MaxUint32 {
+		return fmt.Errorf("the string table of %d bytes is too large", table.Len())
+	}
+
+	isos := make([]string, 0, len(countries))
+	for iso2 := range countries {
+		isos = append(isos, iso2)
+	}
+	slices.Sort(isos)
+
+	countryIndex := make([]byte, len(isos)*countrySize)
+	postings := make([]byte, 0, len(sorted)*postingSize)
+	for i, iso2 := range isos {
+		entry := countryIndex[i*countrySize : (i+1)*countrySize]
+		copy(entry[cCountryISO2:], iso2)
+		binary.LittleEndian.PutUint32(entry[cFirst:], uint32(len(postings)/postingSize))
+		binary.LittleEndian.PutUint32(entry[cCount:], uint32(len(countries[iso2])))
+		for _, record := range countries[iso2] {
+			postings = binary.LittleEndian.AppendUint32(postings, record)
+		}
+	}
+
+	checksum := crc32.NewIEEE()
+	sections := [][]byte{records, prefixes, countryIndex, postings, table.Bytes()}
+	for _, section := range sections {
+		checksum.Write(section)
+	}
+
+	header := make([]byte, headerSize)
+	copy(header[hMagic:], magic)
+	binary.LittleEndian.PutUint32(header[hVersion:], version)
+	binary.LittleEndian.PutUint32(header[hRecords:], uint32(len(sorted)))
+	binary.LittleEndian.PutUint32(header[hPrefixes:], uint32(len(prefixes)/prefixSize))
+	binary.LittleEndian.PutUint32(header[hCountries:], uint32(len(isos)))
+	binary.LittleEndian.PutUint32(header[hStrings:], uint32(table.Len()))
+	binary.LittleEndian.PutUint32(header[hChecksum:], checksum.Sum32())
+	binary.LittleEndian.PutUint64(header[hCreatedAt:], uint64(createdAt.UnixNano()))
+
+	for _, section := range append([][]byte{header}, sections...) {
+		if _, err := w.Write(section); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+const (
+	MaxAttempts = 8
+
+	baseBackoff  = 30 * time.Second
+	maxBackoff   = time.Hour
+	claimLease   = time.Minute
+	pollInterval = 5 * time.Second
+	replayBatch  = 500
+)
+
+// Dispatcher turns change events into queued webhook deliveries and delivers
+// them, retrying failures with exponential backoff until they are dead.
+type Dispatcher struct {
+	repo   interfaces.WebhookRepository
+	broker *events.Broker
+	client *http.Client
+}
+
+func NewDispatcher(repo interfaces.WebhookRepository, broker *events.Broker, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		repo:   repo,
+		broker: broker,
+		client: client,
+	}
+}
+
+// Start enqueues deliveries for every event after the cursor saved in the
+// webhook repository, so that events published while no dispatcher was
+// running are not lost, and delivers them. The cursor only moves past an
+// event once its deliveries are enqueued.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go d.consume(ctx)
+	go d.deliverLoop(ctx)
+}
+
+func (d *Dispatcher) consume(ctx context.Context) {
+	lastID, ok := d.loadCursor(ctx)
+	if !ok {
+		return
+	}
+
+	for ctx.Err() == nil {
+		sub := d.broker.Subscribe(events.Filter{})
+		var err error
+		if lastID, err = d.catchUp(ctx, lastID); err == nil {
+			lastID, err = d.follow(ctx, sub, lastID)
+		}
+		d.broker.Unsubscribe(sub)
+
+		if err != nil {
+			log.Printf("Failed to enqueue webhook deliveries, retrying from event %d: %v", lastID, err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(pollInterval):
+			}
+		}
+	}
+}
+
+// follow enqueues the events published to sub until ctx is done, the
+// subscription falls behind or an event fails, returning the last event
+// enqueued.
+func (d *Dispatcher) follow(ctx context.Context, sub *events.Subscription, lastID int64) (int64, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return lastID, nil
+		case event, ok := <-sub.Events():
+			if !ok {
+				return lastID, nil
+			}
+			if event.ID <= lastID {
+				continue
+			}
+			if err := d.advance(ctx, event); err != nil {
+				return lastID, err
+			}
+			lastID = event.ID
+		}
+	}
+}
+
+// loadCursor returns the saved cursor, retrying until it can be read. The
+// first dispatcher to run starts from the latest event rather than replaying
+// the whole event log to the current subscriptions.
+func (d *Dispatcher) loadCursor(ctx context.Context) (int64, bool) {
+	for {
+		lastID, err := d.repo.FindCursor(ctx)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			if lastID, err = d.broker.LatestID(ctx); err == nil {
+				err = d.repo.SaveCursor(ctx, lastID)
+			}
+		}
+		if err == nil {
+			return lastID, true
+		}
+		log.Printf("Failed to load the webhook dispatcher cursor: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return 0, false
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// advance enqueues the deliveries of event and moves the cursor past it.
+func (d *Dispatcher) advance(ctx context.Context, event models.Event) error {
+	if err := d.Enqueue(ctx, event); err != nil {
+		return err
+	}
+	return d.repo.SaveCursor(ctx, event.ID)
+}
+
+// catchUp enqueues the logged events after lastID, stopping at the first
+// one that fails so that it is retried on the next catch-up.
+func (d *Dispatcher) catchUp(ctx context.Context, lastID int64) (int64, error) {
+	for {
+		logged, err := d.broker.Replay(ctx, lastID, "", replayBatch)
+		if err != nil {
+			return lastID, err
+		}
+
+		for _, event := range logged {
+			if err = d.advance(ctx, event); err != nil {
+				return lastID, err
+			}
+			lastID = event.ID
+		}
+
+		if len(logged) < replayBatch {
+			return lastID, nil
+		}
+	}
+}
+
+func (d *Dispatcher) Enqueue(ctx context.Context, event models.Event) error {
+	subscriptions, err := d.repo.FindSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, subscription := range subscriptions {
+		filter := events.Filter{CountryISO2: subscription.CountryISO2, Types: subscription.EventTypes}
+		if !filter.Matches(event) {
+			continue
+		}
+
+		err = d.repo.EnqueueDelivery(ctx, models.WebhookDelivery{
+			ID:             fmt.Sprintf("%s-%d", subscription.ID, event.ID),
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			Status:         models.DeliveryStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) deliverLoop(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Failed to deliver webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue attempts every delivery that is due, one at a time.
+func (d *Dispatcher) DeliverDue(ctx context.Context) error {
+	for ctx.Err() == nil {
+		delivery, err := d.repo.ClaimDelivery(ctx, time.Now().UTC(), claimLease)
+		if errors.Is(err, interfaces.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = d.deliver(ctx, *delivery); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery) error {
+	subscription, err := d.repo.FindSubscription(ctx, delivery.SubscriptionID)
+	if errors.Is(err, interfaces.ErrNotFound) {
+		delivery.Status = models.DeliveryStatusDead
+		delivery.LastError = "subscription deleted"
+		return d.repo.UpdateDelivery(ctx, delivery)
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+	statusCode, err := d.send(ctx, *subscription, delivery)
+	delivery.LastStatusCode = statusCode
+
+	now := time.Now().UTC()
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= MaxAttempts:
+		delivery.Status = models.DeliveryStatusDead
+		delivery.LastError = err.Error()
+	default:
+		delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+		delivery.LastError = err.Error()
+	}
+
+	return d.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event.Type)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number
+// of failed attempts.
+func Backoff(attempts int) time.Duration {
+	delay := baseBackoff << (attempts - 1)
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/reqctx"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+func Authenticate(cfg config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		if key, ok := cfg.APIKeys[c.GetHeader(APIKeyHeader)]; ok {
+			ctx = reqctx.WithActor(ctx, key.Actor)
+			if key.Admin {
+				ctx = reqctx.WithAdmin(ctx)
+			}
+		} else {
+			ctx = reqctx.WithActor(ctx, reqctx.AnonymousActor)
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
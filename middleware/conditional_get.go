@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"swift-codes-api/utils"
+	"time"
+)
+
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Flush does nothing, as flushing would send the headers before the ETag is
+// known.
+func (w *bufferedWriter) Flush() {}
+
+// ConditionalGET gives successful GET responses a strong ETag derived from
+// their content and the given Cache-Control policy, and answers requests
+// whose If-None-Match or If-Modified-Since validators still hold with 304.
+// Handlers may set Last-Modified to enable If-Modified-Since.
+func ConditionalGET(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buffered
+		defer func() {
+			// A panicking handler leaves the response to Recovery, which
+			// must write it to the client rather than to the buffer.
+			if r := recover(); r != nil {
+				c.Writer = original
+				panic(r)
+			}
+		}()
+		c.Next()
+		c.Writer = original
+
+		if original.Status() != http.StatusOK {
+			_, _ = original.Write(buffered.body.Bytes())
+			return
+		}
+
+		etag := utils.ETag(buffered.body.Bytes())
+		header := original.Header()
+		header.Set("ETag", etag)
+		if cacheControl != "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		if notModified(c.Request, etag, header.Get("Last-Modified")) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+
+		_, _ = original.Write(buffered.body.Bytes())
+	}
+}
+
+func notModified(req *http.Request, etag, lastModified string) bool {
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := req.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified == "" {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since.Truncate(time.Second))
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"math"
+	"strconv"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/problems"
+	"time"
+)
+
+// Rate limit scopes. Each gives every client a budget of its own.
+const (
+	ReadScope  = "read"
+	WriteScope = "write"
+)
+
+// RateLimit gives every client a token bucket for the routes of a scope,
+// sized by RATE_LIMIT_READ or RATE_LIMIT_WRITE. Clients are identified by
+// their API key, or by IP when they send none or an unknown one. Responses
+// carry the RateLimit-* headers, and requests over budget get 429 with
+// Retry-After. Requests are let through if the store fails.
+func RateLimit(cfg config.Config, store ratelimit.Store, scope string) gin.HandlerFunc {
+	limit := cfg.RateLimitWrite
+	if scope == ReadScope {
+		limit = cfg.RateLimitRead
+	}
+
+	return func(c *gin.Context) {
+		if !limit.Enabled() {
+			c.Next()
+			return
+		}
+
+		result, err := store.Take(c.Request.Context(), scope+":"+clientKey(c, cfg), limit, time.Now())
+		if err != nil {
+			log.Printf("Rate limit store failed, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", ceilSeconds(result.Reset))
+		header.Set("RateLimit-Policy", strconv.Itoa(limit.Requests)+";w="+ceilSeconds(limit.Window))
+
+		if !result.Allowed {
+			header.Set("Retry-After", ceilSeconds(result.RetryAfter))
+			problems.Respond(c, problems.RateLimited, "Rate limit of "+limit.String()+" exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey identifies the caller. Its IP is only read from the
+// X-Forwarded-For header of requests sent by the trusted proxies of the
+// engine, none unless configured with TRUSTED_PROXIES.
+func clientKey(c *gin.Context, cfg config.Config) string {
+	key := c.GetHeader(APIKeyHeader)
+	_, known := cfg.APIKeys[key]
+	return ratelimit.ClientKey(key, known, c.ClientIP())
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"swift-codes-api/problems"
+)
+
+// Recovery answers requests whose handler panics with an internal error,
+// like gin's Recovery middleware, logging the panic to logger.
+//
+// It lets http.ErrAbortHandler through to net/http, which then breaks off
+// the response: streamed responses that fail halfway use it so that clients
+// see a truncated transfer instead of a response that looks complete.
+func Recovery(logger *log.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			if r == http.ErrAbortHandler {
+				panic(r)
+			}
+
+			logger.Printf("Panic in %s %s: %v\n%s", c.Request.Method, c.Request.URL.Path, r, debug.Stack())
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			problems.Respond(c, problems.Internal, "Internal server error")
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"swift-codes-api/internal/reqctx"
+	"sync"
+	"time"
+)
+
+// StaleWarning flags responses built from data that repositories served out
+// of a cache because the database was unavailable, with a Warning header and
+// the Age of the oldest such data.
+//
+// Repositories may report stale data from several goroutines at once, as the
+// GraphQL resolvers do. Data reported once the response has started, as
+// streamed exports and screenings do after their first rows, can no longer
+// be flagged: those responses only carry the headers when the first rows
+// were already stale.
+func StaleWarning() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var (
+			mu     sync.Mutex
+			oldest time.Time
+		)
+		ctx := reqctx.WithStaleHandler(c.Request.Context(), func(fetchedAt time.Time) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !oldest.IsZero() && !fetchedAt.Before(oldest) || c.Writer.Written() {
+				return
+			}
+			oldest = fetchedAt
+			c.Header("Warning", `110 - "Response is Stale"`)
+			c.Header("Age", strconv.Itoa(int(time.Since(fetchedAt).Seconds())))
+		})
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	AuditOperationCreate  = "create"
+	AuditOperationUpdate  = "update"
+	AuditOperationDelete  = "delete"
+	AuditOperationRestore = "restore"
+	AuditOperationPurge   = "purge"
+)
+
+type AuditEntry struct {
+	SwiftCode string     `bson:"swiftCode" json:"swiftCode"`
+	Operation string     `bson:"operation" json:"operation"`
+	Actor     string     `bson:"actor" json:"actor"`
+	RequestID string     `bson:"requestId" json:"requestId"`
+	Before    *SwiftCode `bson:"before" json:"before"`
+	After     *SwiftCode `bson:"after" json:"after"`
+	Timestamp time.Time  `bson:"timestamp" json:"timestamp"`
+}
+
+type AuditFilter struct {
+	SwiftCode string
+	Actor     string
+	From      time.Time
+	To        time.Time
+	Limit     int64
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+const (
+	DropFolderRunClaimed   = "claimed"
+	DropFolderRunImporting = "importing"
+	DropFolderRunSucceeded = "succeeded"
+	DropFolderRunFailed    = "failed"
+	DropFolderRunCancelled = "cancelled"
+	DropFolderRunDuplicate = "duplicate"
+)
+
+// DropFolderRun is a file picked up from the drop folder. A file whose
+// checksum matches an earlier imported file is not imported again; DuplicateOf
+// then names the run that imported it.
+type DropFolderRun struct {
+	ID          string     `bson:"_id" json:"id"`
+	FileName    string     `bson:"fileName" json:"fileName"`
+	Checksum    string     `bson:"checksum" json:"checksum"`
+	Status      string     `bson:"status" json:"status"`
+	ImportID    string     `bson:"importId,omitempty" json:"importId,omitempty"`
+	DuplicateOf string     `bson:"duplicateOf,omitempty" json:"duplicateOf,omitempty"`
+	Processed   int        `bson:"processed" json:"processed"`
+	Created     int        `bson:"created" json:"created"`
+	Updated     int        `bson:"updated" json:"updated"`
+	Unchanged   int        `bson:"unchanged" json:"unchanged"`
+	Failed      int        `bson:"failed" json:"failed"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"`
+	MovedTo     string     `bson:"movedTo,omitempty" json:"movedTo,omitempty"`
+	StartedAt   time.Time  `bson:"startedAt" json:"startedAt"`
+	FinishedAt  *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	EventTypeCreated  = "swift-code.created"
+	EventTypeUpdated  = "swift-code.updated"
+	EventTypeDeleted  = "swift-code.deleted"
+	EventTypeRestored = "swift-code.restored"
+)
+
+type Event struct {
+	ID          int64      `bson:"_id" json:"id"`
+	Type        string     `bson:"type" json:"type"`
+	SwiftCode   string     `bson:"swiftCode" json:"swiftCode"`
+	CountryISO2 string     `bson:"countryISO2" json:"countryISO2"`
+	Record      *SwiftCode `bson:"record" json:"record"`
+	Timestamp   time.Time  `bson:"timestamp" json:"timestamp"`
+	// SourceID identifies the change an event was derived from, so that
+	// every replica watching a change feed logs it once.
+	SourceID string `bson:"sourceId,omitempty" json:"-"`
+}
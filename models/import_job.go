@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+const (
+	ImportStatusQueued    = "queued"
+	ImportStatusRunning   = "running"
+	ImportStatusSucceeded = "succeeded"
+	ImportStatusFailed    = "failed"
+	ImportStatusCancelled = "cancelled"
+)
+
+// ImportJob is a directory file being loaded in the background. The counts
+// cover the rows processed so far; Row of an error is the 1-based position of
+// the record in the file, not counting a CSV header.
+type ImportJob struct {
+	ID              string           `bson:"_id" json:"id"`
+	Status          string           `bson:"status" json:"status"`
+	Format          string           `bson:"format" json:"format"`
+	FileName        string           `bson:"fileName" json:"fileName"`
+	Size            int64            `bson:"size" json:"size"`
+	BytesRead       int64            `bson:"bytesRead" json:"bytesRead"`
+	Processed       int              `bson:"processed" json:"processed"`
+	Created         int              `bson:"created" json:"created"`
+	Updated         int              `bson:"updated" json:"updated"`
+	Unchanged       int              `bson:"unchanged" json:"unchanged"`
+	Failed          int              `bson:"failed" json:"failed"`
+	Errors          []ImportRowError `bson:"errors" json:"errors"`
+	Error           string           `bson:"error,omitempty" json:"error,omitempty"`
+	CancelRequested bool             `bson:"cancelRequested" json:"cancelRequested"`
+	Actor           string           `bson:"actor" json:"actor"`
+	LeaseUntil      time.Time        `bson:"leaseUntil" json:"-"`
+	// Claims counts the times a worker claimed the job. It fences off the
+	// updates of a worker whose lease ran out and was taken over.
+	Claims     int64      `bson:"claims" json:"-"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	StartedAt  *time.Time `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	FinishedAt *time.Time `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+}
+
+type ImportRowError struct {
+	Row       int    `bson:"row" json:"row"`
+	SwiftCode string `bson:"swiftCode,omitempty" json:"swiftCode,omitempty"`
+	Message   string `bson:"message" json:"message"`
+}
+
+// Finished reports whether the job has reached a final status.
+func (j ImportJob) Finished() bool {
+	switch j.Status {
+	case ImportStatusSucceeded, ImportStatusFailed, ImportStatusCancelled:
+		return true
+	}
+	return false
+}
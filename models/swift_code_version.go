@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// SwiftCodeVersion is the state of a SWIFT code during [ValidFrom, ValidTo).
+// The current version has no ValidTo.
+type SwiftCodeVersion struct {
+	SwiftCode string     `bson:"swiftCode" json:"swiftCode"`
+	Version   int        `bson:"version" json:"version"`
+	ValidFrom time.Time  `bson:"validFrom" json:"validFrom"`
+	ValidTo   *time.Time `bson:"validTo" json:"validTo"`
+	Deleted   bool       `bson:"deleted" json:"deleted"`
+	Record    SwiftCode  `bson:"record" json:"record"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+type WebhookSubscription struct {
+	ID          string    `bson:"_id" json:"id"`
+	URL         string    `bson:"url" json:"url"`
+	EventTypes  []string  `bson:"eventTypes" json:"eventTypes"`
+	CountryISO2 string    `bson:"countryISO2,omitempty" json:"countryISO2,omitempty"`
+	Secret      string    `bson:"secret" json:"secret,omitempty"`
+	CreatedAt   time.Time `bson:"createdAt" json:"createdAt"`
+}
+
+type WebhookDelivery struct {
+	ID             string     `bson:"_id" json:"id"`
+	SubscriptionID string     `bson:"subscriptionId" json:"subscriptionId"`
+	Event          Event      `bson:"event" json:"event"`
+	Status         string     `bson:"status" json:"status"`
+	Attempts       int        `bson:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time  `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LastStatusCode int        `bson:"lastStatusCode,omitempty" json:"lastStatusCode,omitempty"`
+	LastError      string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt      time.Time  `bson:"createdAt" json:"createdAt"`
+	DeliveredAt    *time.Time `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+}
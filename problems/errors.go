@@ -0,0 +1,25 @@
+package problems
+
+import (
+	"errors"
+	"swift-codes-api/repositories/interfaces"
+)
+
+// FromError maps the errors that every repository reports the same way to
+// their problem, so that REST, gRPC and GraphQL describe them alike. Other
+// errors, including interfaces.ErrNotFound whose problem depends on what was
+// looked up, are described by fallback and detail.
+func FromError(err error, fallback Code, detail string) (Code, string) {
+	switch {
+	case errors.Is(err, interfaces.ErrUnavailable):
+		return ServiceUnavailable, "The database is unavailable, try again later"
+	case errors.Is(err, interfaces.ErrReadOnly):
+		return NotImplemented, "SWIFT codes cannot be changed on this read-only deployment"
+	case errors.Is(err, interfaces.ErrAlreadyExists):
+		return SwiftCodeExists, err.Error()
+	case errors.Is(err, interfaces.ErrRevisionMismatch):
+		return RevisionMismatch, "SWIFT code has been modified since it was retrieved"
+	default:
+		return fallback, detail
+	}
+}
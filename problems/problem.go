@@ -0,0 +1,120 @@
+package problems
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/utils"
+)
+
+const ContentType = "application/problem+json"
+
+// Code identifies a kind of problem. Codes are part of the API contract and
+// never change once published, unlike titles and details.
+type Code string
+
+const (
+	InvalidSwiftCode   Code = "invalid-swift-code"
+	InvalidCountryCode Code = "invalid-country-code"
+	MalformedRequest   Code = "malformed-request"
+	ValidationFailed   Code = "validation-failed"
+	InvalidParameter   Code = "invalid-parameter"
+	Forbidden          Code = "forbidden"
+	SwiftCodeNotFound  Code = "swift-code-not-found"
+	CountryNotFound    Code = "country-not-found"
+	HistoryNotFound    Code = "history-not-found"
+	WebhookNotFound    Code = "webhook-not-found"
+	ImportNotFound     Code = "import-not-found"
+	RouteNotFound      Code = "route-not-found"
+	SwiftCodeExists    Code = "swift-code-exists"
+	RevisionMismatch   Code = "revision-mismatch"
+	ImportFinished     Code = "import-finished"
+	FileTooLarge       Code = "file-too-large"
+	RateLimited        Code = "rate-limited"
+	Internal           Code = "internal-error"
+	NotImplemented     Code = "not-implemented"
+	ServiceUnavailable Code = "service-unavailable"
+)
+
+type kind struct {
+	status int
+	title  string
+}
+
+var kinds = map[Code]kind{
+	InvalidSwiftCode:   {http.StatusBadRequest, "Invalid SWIFT code"},
+	InvalidCountryCode: {http.StatusBadRequest, "Invalid country code"},
+	MalformedRequest:   {http.StatusBadRequest, "Malformed request body"},
+	ValidationFailed:   {http.StatusBadRequest, "Validation failed"},
+	InvalidParameter:   {http.StatusBadRequest, "Invalid parameter"},
+	Forbidden:          {http.StatusForbidden, "Forbidden"},
+	SwiftCodeNotFound:  {http.StatusNotFound, "SWIFT code not found"},
+	CountryNotFound:    {http.StatusNotFound, "Country not found"},
+	HistoryNotFound:    {http.StatusNotFound, "History not found"},
+	WebhookNotFound:    {http.StatusNotFound, "Webhook not found"},
+	ImportNotFound:     {http.StatusNotFound, "Import not found"},
+	RouteNotFound:      {http.StatusNotFound, "Not found"},
+	SwiftCodeExists:    {http.StatusConflict, "SWIFT code already exists"},
+	RevisionMismatch:   {http.StatusPreconditionFailed, "SWIFT code has been modified"},
+	ImportFinished:     {http.StatusConflict, "Import already finished"},
+	FileTooLarge:       {http.StatusRequestEntityTooLarge, "File too large"},
+	RateLimited:        {http.StatusTooManyRequests, "Too many requests"},
+	Internal:           {http.StatusInternalServerError, "Internal server error"},
+	NotImplemented:     {http.StatusNotImplemented, "Not implemented"},
+	ServiceUnavailable: {http.StatusServiceUnavailable, "Service unavailable"},
+}
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      Code         `json:"code"`
+	RequestID string       `json:"requestId,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError tells which field of a request body failed validation and why.
+type FieldError = utils.FieldError
+
+const (
+	FieldRequired = utils.FieldRequired
+	FieldFormat   = utils.FieldFormat
+	FieldMismatch = utils.FieldMismatch
+)
+
+func New(code Code, detail string) Problem {
+	k, ok := kinds[code]
+	if !ok {
+		k = kinds[Internal]
+	}
+	return Problem{
+		Type:   "urn:swift-codes-api:problem:" + string(code),
+		Title:  k.title,
+		Status: k.status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Respond aborts the request with a problem response for code.
+func Respond(c *gin.Context, code Code, detail string) {
+	Write(c, New(code, detail))
+}
+
+// RespondValidation aborts the request with the given field errors.
+func RespondValidation(c *gin.Context, errs []FieldError) {
+	p := New(ValidationFailed, "One or more fields are invalid")
+	p.Errors = errs
+	Write(c, p)
+}
+
+func Write(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	p.RequestID = reqctx.RequestID(c.Request.Context())
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
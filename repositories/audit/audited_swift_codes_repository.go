@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+const (
+	defaultRetries    = 5
+	defaultRetryDelay = time.Second
+	recordTimeout     = 10 * time.Second
+)
+
+// SwiftRepository records an audit entry for every mutation that passes
+// through it. Reads are forwarded to the wrapped repository untouched.
+//
+// A mutation whose audit entry cannot be recorded still succeeds, as the
+// change was applied; the entry is recorded again in the background, with
+// the delay doubling between attempts, and logged when all attempts fail.
+type SwiftRepository struct {
+	interfaces.SwiftRepository
+	audit      interfaces.AuditRepository
+	retries    int
+	retryDelay time.Duration
+}
+
+type Option func(*SwiftRepository)
+
+// WithRetries sets how many more times an audit entry that could not be
+// recorded is tried, and the delay before the first of them.
+func WithRetries(retries int, delay time.Duration) Option {
+	return func(r *SwiftRepository) {
+		r.retries = retries
+		r.retryDelay = delay
+	}
+}
+
+func NewSwiftRepository(repo interfaces.SwiftRepository, audit interfaces.AuditRepository, opts ...Option) *SwiftRepository {
+	r := &SwiftRepository{
+		SwiftRepository: repo,
+		audit:           audit,
+		retries:         defaultRetries,
+		retryDelay:      defaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	if err := r.SwiftRepository.AddSwiftCode(ctx, swiftCode); err != nil {
+		return err
+	}
+
+	after, err := r.SwiftRepository.FindByCode(ctx, swiftCode.SwiftCode)
+	if err != nil {
+		after = &swiftCode
+	}
+
+	r.record(ctx, models.AuditOperationCreate, swiftCode.SwiftCode, nil, after)
+	return nil
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	before, _ := r.SwiftRepository.FindByCode(ctx, swiftCode.SwiftCode)
+
+	if err := r.SwiftRepository.UpdateSwiftCode(ctx, swiftCode, expectedRevision); err != nil {
+		return err
+	}
+
+	after, _ := r.SwiftRepository.FindByCode(ctx, swiftCode.SwiftCode)
+	r.record(ctx, models.AuditOperationUpdate, swiftCode.SwiftCode, before, after)
+	return nil
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	before, _ := r.SwiftRepository.FindByCode(ctx, code)
+
+	if err := r.SwiftRepository.DeleteSwiftCode(ctx, code, expectedRevision); err != nil {
+		return err
+	}
+
+	r.record(ctx, models.AuditOperationDelete, code, before, nil)
+	return nil
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	before, _ := r.SwiftRepository.FindByCode(reqctx.WithIncludeDeleted(ctx), code)
+
+	if err := r.SwiftRepository.RestoreSwiftCode(ctx, code); err != nil {
+		return err
+	}
+
+	after, _ := r.SwiftRepository.FindByCode(ctx, code)
+	r.record(ctx, models.AuditOperationRestore, code, before, after)
+	return nil
+}
+
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	purged, err := r.SwiftRepository.PurgeDeletedSwiftCodes(ctx, deletedBefore)
+	for i := range purged {
+		r.record(ctx, models.AuditOperationPurge, purged[i].SwiftCode, &purged[i], nil)
+	}
+	return purged, err
+}
+
+func (r *SwiftRepository) record(ctx context.Context, operation, code string, before, after *models.SwiftCode) {
+	entry := models.AuditEntry{
+		SwiftCode: code,
+		Operation: operation,
+		Actor:     reqctx.Actor(ctx),
+		RequestID: reqctx.RequestID(ctx),
+		Before:    before,
+		After:     after,
+		Timestamp: time.Now().UTC(),
+	}
+
+	err := r.audit.Record(ctx, entry)
+	if err == nil {
+		return
+	}
+
+	log.Printf("Failed to record audit entry for %s %s, retrying: %v", operation, code, err)
+	go r.retry(context.WithoutCancel(ctx), entry, err)
+}
+
+func (r *SwiftRepository) retry(ctx context.Context, entry models.AuditEntry, err error) {
+	delay := r.retryDelay
+	for attempt := 0; attempt < r.retries; attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+
+		recordCtx, cancel := context.WithTimeout(ctx, recordTimeout)
+		err = r.audit.Record(recordCtx, entry)
+		cancel()
+		if err == nil {
+			return
+		}
+	}
+
+	log.Printf("Gave up recording audit entry for %s %s by %s: %v", entry.Operation, entry.SwiftCode, entry.Actor, err)
+}
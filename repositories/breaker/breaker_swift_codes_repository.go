@@ -0,0 +1,297 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"sync"
+	"time"
+)
+
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// ErrOpen is returned without calling the database while the circuit is open.
+var ErrOpen = fmt.Errorf("circuit breaker open: %w", interfaces.ErrUnavailable)
+
+type Options struct {
+	// FailureRate is the share of failed calls, between 0 and 1, that opens
+	// the circuit once at least MinCalls were made within a Window.
+	FailureRate float64
+	MinCalls    int
+	Window      time.Duration
+	// OpenTimeout is how long the circuit stays open before Probes calls
+	// are let through. The circuit closes when they all succeed and opens
+	// again at the first failure.
+	OpenTimeout time.Duration
+	Probes      int
+}
+
+type Stats struct {
+	State    string `json:"state"`
+	Calls    int    `json:"calls"`
+	Failures int    `json:"failures"`
+	Rejected int64  `json:"rejected"`
+	Trips    int64  `json:"trips"`
+}
+
+// SwiftRepository is a circuit breaker in front of another repository. It
+// fails fast with ErrOpen while the database keeps failing, instead of tying
+// up every request until the driver times out. The errors of failed calls
+// wrap interfaces.ErrUnavailable.
+type SwiftRepository struct {
+	repo interfaces.SwiftRepository
+	opts Options
+
+	mu          sync.Mutex
+	state       string
+	windowStart time.Time
+	calls       int
+	failures    int
+	openedAt    time.Time
+	probing     int
+	probed      int
+	rejected    int64
+	trips       int64
+}
+
+func NewSwiftRepository(repo interfaces.SwiftRepository, opts Options) *SwiftRepository {
+	if opts.Probes < 1 {
+		opts.Probes = 1
+	}
+	return &SwiftRepository{
+		repo:        repo,
+		opts:        opts,
+		state:       StateClosed,
+		windowStart: time.Now(),
+	}
+}
+
+func (r *SwiftRepository) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Stats{
+		State:    r.currentState(time.Now()),
+		Calls:    r.calls,
+		Failures: r.failures,
+		Rejected: r.rejected,
+		Trips:    r.trips,
+	}
+}
+
+// currentState is the state of the circuit at now, an open circuit turning
+// half-open once OpenTimeout has passed.
+func (r *SwiftRepository) currentState(now time.Time) string {
+	if r.state == StateOpen && now.Sub(r.openedAt) >= r.opts.OpenTimeout {
+		r.state = StateHalfOpen
+		r.probing = 0
+		r.probed = 0
+	}
+	return r.state
+}
+
+// allow reports whether a call may go through to the database.
+func (r *SwiftRepository) allow() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	switch r.currentState(now) {
+	case StateClosed:
+		if now.Sub(r.windowStart) >= r.opts.Window {
+			r.windowStart = now
+			r.calls = 0
+			r.failures = 0
+		}
+		return true
+	case StateHalfOpen:
+		if r.probing+r.probed < r.opts.Probes {
+			r.probing++
+			return true
+		}
+	}
+
+	r.rejected++
+	return false
+}
+
+// record counts the outcome of a call let through by allow.
+func (r *SwiftRepository) record(failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	switch r.state {
+	case StateClosed:
+		r.calls++
+		if failed {
+			r.failures++
+		}
+		if r.calls >= r.opts.MinCalls && float64(r.failures) >= r.opts.FailureRate*float64(r.calls) && r.failures > 0 {
+			r.open(now)
+		}
+	case StateHalfOpen:
+		r.probing--
+		if failed {
+			r.open(now)
+			return
+		}
+		r.probed++
+		if r.probed >= r.opts.Probes {
+			r.state = StateClosed
+			r.windowStart = now
+			r.calls = 0
+			r.failures = 0
+		}
+	}
+}
+
+func (r *SwiftRepository) open(now time.Time) {
+	r.state = StateOpen
+	r.openedAt = now
+	r.trips++
+}
+
+// call runs fn through the breaker.
+func (r *SwiftRepository) call(ctx context.Context, fn func() error) error {
+	if !r.allow() {
+		return ErrOpen
+	}
+
+	err := fn()
+	if isFailure(ctx, err) {
+		r.record(true)
+		return fmt.Errorf("%w: %w", interfaces.ErrUnavailable, err)
+	}
+	r.record(false)
+	return err
+}
+
+// isFailure tells the errors of a failing database from the outcomes of a
+// healthy one: missing records, conflicts and calls given up by the caller.
+func isFailure(ctx context.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, interfaces.ErrNotFound), errors.Is(err, interfaces.ErrRevisionMismatch),
+		errors.Is(err, interfaces.ErrAlreadyExists):
+		return false
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return false
+	}
+	return true
+}
+
+func (r *SwiftRepository) FindByCode(ctx context.Context, code string) (*models.SwiftCode, error) {
+	var swiftCode *models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		swiftCode, err = r.repo.FindByCode(ctx, code)
+		return err
+	})
+	return swiftCode, err
+}
+
+func (r *SwiftRepository) FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error) {
+	var swiftCodes []models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		swiftCodes, err = r.repo.FindByCodes(ctx, codes)
+		return err
+	})
+	return swiftCodes, err
+}
+
+func (r *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
+	var branches []models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		branches, err = r.repo.FindBranchesByPrefix(ctx, prefix)
+		return err
+	})
+	return branches, err
+}
+
+func (r *SwiftRepository) FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	var branches []models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		branches, err = r.repo.FindBranchesByPrefixes(ctx, prefixes)
+		return err
+	})
+	return branches, err
+}
+
+func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
+	var swiftCodes []models.SwiftCode
+	var countryName string
+	err := r.call(ctx, func() (err error) {
+		swiftCodes, countryName, err = r.repo.FindByCountryISO2(ctx, countryISO2)
+		return err
+	})
+	return swiftCodes, countryName, err
+}
+
+// StreamSwiftCodes only counts the errors of the database, not those of fn,
+// which are returned as they are.
+func (r *SwiftRepository) StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error {
+	var fnErr error
+	err := r.call(ctx, func() error {
+		err := r.repo.StreamSwiftCodes(ctx, countryISO2, func(swiftCode models.SwiftCode) error {
+			fnErr = fn(swiftCode)
+			return fnErr
+		})
+		if fnErr != nil {
+			return nil
+		}
+		return err
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
+
+func (r *SwiftRepository) SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error) {
+	var swiftCodes []models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		swiftCodes, err = r.repo.SearchSwiftCodes(ctx, query, countryISO2, limit)
+		return err
+	})
+	return swiftCodes, err
+}
+
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	return r.call(ctx, func() error {
+		return r.repo.AddSwiftCode(ctx, swiftCode)
+	})
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	return r.call(ctx, func() error {
+		return r.repo.UpdateSwiftCode(ctx, swiftCode, expectedRevision)
+	})
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	return r.call(ctx, func() error {
+		return r.repo.DeleteSwiftCode(ctx, code, expectedRevision)
+	})
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	return r.call(ctx, func() error {
+		return r.repo.RestoreSwiftCode(ctx, code)
+	})
+}
+
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	var purged []models.SwiftCode
+	err := r.call(ctx, func() (err error) {
+		purged, err = r.repo.PurgeDeletedSwiftCodes(ctx, deletedBefore)
+		return err
+	})
+	return purged, err
+}
@@ -0,0 +1,360 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"sync/atomic"
+	"time"
+)
+
+type Options struct {
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	// StaleTTL is how long past their expiry entries are served when the
+	// repository behind is unavailable, or 0 to never serve stale entries.
+	StaleTTL time.Duration
+}
+
+type Stats struct {
+	Hits          int64 `json:"hits"`
+	NegativeHits  int64 `json:"negativeHits"`
+	Misses        int64 `json:"misses"`
+	Evictions     int64 `json:"evictions"`
+	Invalidations int64 `json:"invalidations"`
+	StaleHits     int64 `json:"staleHits"`
+	Entries       int   `json:"entries"`
+}
+
+type countryEntry struct {
+	swiftCodes  []models.SwiftCode
+	countryName string
+}
+
+// SwiftRepository is a read-through cache in front of another repository.
+// Lookups of codes, branches by prefix and countries are cached, including
+// not-found results, and the affected entries are dropped on every mutation.
+// While the repository is unavailable, entries are served for StaleTTL past
+// their expiry and reported with reqctx.MarkStale.
+type SwiftRepository struct {
+	interfaces.SwiftRepository
+	opts    Options
+	entries *lru
+
+	hits          atomic.Int64
+	negativeHits  atomic.Int64
+	misses        atomic.Int64
+	evictions     atomic.Int64
+	invalidations atomic.Int64
+	staleHits     atomic.Int64
+}
+
+func NewSwiftRepository(repo interfaces.SwiftRepository, opts Options) *SwiftRepository {
+	return &SwiftRepository{
+		SwiftRepository: repo,
+		opts:            opts,
+		entries:         newLRU(opts.Size, opts.StaleTTL),
+	}
+}
+
+func (r *SwiftRepository) Stats() Stats {
+	return Stats{
+		Hits:          r.hits.Load(),
+		NegativeHits:  r.negativeHits.Load(),
+		Misses:        r.misses.Load(),
+		Evictions:     r.evictions.Load(),
+		Invalidations: r.invalidations.Load(),
+		StaleHits:     r.staleHits.Load(),
+		Entries:       r.entries.len(),
+	}
+}
+
+func (r *SwiftRepository) FindByCode(ctx context.Context, code string) (*models.SwiftCode, error) {
+	value, err := r.load(ctx, codeKey(code), func() (any, error) {
+		return r.SwiftRepository.FindByCode(ctx, code)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	swiftCode := *value.(*models.SwiftCode)
+	return &swiftCode, nil
+}
+
+// FindByCodes answers the codes it has cached and fetches the others in a
+// single call, caching what it finds and, as not found, what it does not.
+func (r *SwiftRepository) FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error) {
+	if reqctx.IncludeDeleted(ctx) {
+		return r.SwiftRepository.FindByCodes(ctx, codes)
+	}
+
+	now := time.Now()
+	var found []models.SwiftCode
+	var missing []string
+	for _, code := range codes {
+		entry, ok := r.entries.get(codeKey(code), now)
+		switch {
+		case !ok:
+			missing = append(missing, code)
+		case entry.err != nil:
+			r.negativeHits.Add(1)
+		default:
+			r.hits.Add(1)
+			found = append(found, *entry.value.(*models.SwiftCode))
+		}
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	r.misses.Add(int64(len(missing)))
+	generation := r.entries.currentGeneration()
+	fetched, err := r.SwiftRepository.FindByCodes(ctx, missing)
+	if errors.Is(err, interfaces.ErrUnavailable) {
+		if stale, ok := r.staleCodes(ctx, missing, now); ok {
+			return append(found, stale...), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fetchedCodes := make(map[string]bool, len(fetched))
+	for _, swiftCode := range fetched {
+		fetchedCodes[swiftCode.SwiftCode] = true
+		stored := swiftCode
+		r.store(&lruEntry{key: codeKey(swiftCode.SwiftCode), value: &stored, stored: now, expires: now.Add(r.opts.TTL)}, generation)
+	}
+	if r.opts.NegativeTTL > 0 {
+		for _, code := range missing {
+			if !fetchedCodes[code] {
+				r.store(&lruEntry{key: codeKey(code), err: interfaces.ErrNotFound, stored: now, expires: now.Add(r.opts.NegativeTTL)}, generation)
+			}
+		}
+	}
+	return append(found, fetched...), nil
+}
+
+// staleCodes answers codes from stale entries, provided there is one for
+// every code.
+func (r *SwiftRepository) staleCodes(ctx context.Context, codes []string, now time.Time) ([]models.SwiftCode, bool) {
+	var found []models.SwiftCode
+	var oldest time.Time
+	for _, code := range codes {
+		entry, ok := r.entries.stale(codeKey(code), now)
+		if !ok {
+			return nil, false
+		}
+		if oldest.IsZero() || entry.stored.Before(oldest) {
+			oldest = entry.stored
+		}
+		if entry.err == nil {
+			found = append(found, *entry.value.(*models.SwiftCode))
+		}
+	}
+
+	r.staleHits.Add(int64(len(codes)))
+	reqctx.MarkStale(ctx, oldest)
+	return found, true
+}
+
+func (r *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
+	value, err := r.load(ctx, prefixKey(prefix), func() (any, error) {
+		return r.SwiftRepository.FindBranchesByPrefix(ctx, prefix)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Clone(value.([]models.SwiftCode)), nil
+}
+
+// FindBranchesByPrefixes answers the prefixes it has cached and fetches the
+// branches of the others in a single call, caching them per prefix.
+func (r *SwiftRepository) FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	if reqctx.IncludeDeleted(ctx) {
+		return r.SwiftRepository.FindBranchesByPrefixes(ctx, prefixes)
+	}
+
+	now := time.Now()
+	var found []models.SwiftCode
+	var missing []string
+	for _, prefix := range prefixes {
+		entry, ok := r.entries.get(prefixKey(prefix), now)
+		switch {
+		case !ok:
+			missing = append(missing, prefix)
+		case entry.err != nil:
+			r.negativeHits.Add(1)
+		default:
+			r.hits.Add(1)
+			found = append(found, entry.value.([]models.SwiftCode)...)
+		}
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	r.misses.Add(int64(len(missing)))
+	generation := r.entries.currentGeneration()
+	fetched, err := r.SwiftRepository.FindBranchesByPrefixes(ctx, missing)
+	if errors.Is(err, interfaces.ErrUnavailable) {
+		if stale, ok := r.staleBranches(ctx, missing, now); ok {
+			return append(found, stale...), nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byPrefix := make(map[string][]models.SwiftCode, len(missing))
+	for _, branch := range fetched {
+		byPrefix[branch.SwiftPrefix] = append(byPrefix[branch.SwiftPrefix], branch)
+	}
+	for _, prefix := range missing {
+		branches := byPrefix[prefix]
+		if branches == nil {
+			branches = []models.SwiftCode{}
+		}
+		r.store(&lruEntry{key: prefixKey(prefix), value: branches, stored: now, expires: now.Add(r.opts.TTL)}, generation)
+	}
+	return append(found, fetched...), nil
+}
+
+// staleBranches answers prefixes from stale entries, provided there is one
+// for every prefix.
+func (r *SwiftRepository) staleBranches(ctx context.Context, prefixes []string, now time.Time) ([]models.SwiftCode, bool) {
+	var found []models.SwiftCode
+	var oldest time.Time
+	for _, prefix := range prefixes {
+		entry, ok := r.entries.stale(prefixKey(prefix), now)
+		if !ok {
+			return nil, false
+		}
+		if oldest.IsZero() || entry.stored.Before(oldest) {
+			oldest = entry.stored
+		}
+		if entry.err == nil {
+			found = append(found, entry.value.([]models.SwiftCode)...)
+		}
+	}
+
+	r.staleHits.Add(int64(len(prefixes)))
+	reqctx.MarkStale(ctx, oldest)
+	return found, true
+}
+
+func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
+	value, err := r.load(ctx, countryKey(countryISO2), func() (any, error) {
+		swiftCodes, countryName, err := r.SwiftRepository.FindByCountryISO2(ctx, countryISO2)
+		if err != nil {
+			return nil, err
+		}
+		return countryEntry{swiftCodes: swiftCodes, countryName: countryName}, nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	country := value.(countryEntry)
+	return slices.Clone(country.swiftCodes), country.countryName, nil
+}
+
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	defer r.Invalidate(swiftCode.SwiftCode)
+	return r.SwiftRepository.AddSwiftCode(ctx, swiftCode)
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	defer r.Invalidate(swiftCode.SwiftCode)
+	return r.SwiftRepository.UpdateSwiftCode(ctx, swiftCode, expectedRevision)
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	defer r.Invalidate(code)
+	return r.SwiftRepository.DeleteSwiftCode(ctx, code, expectedRevision)
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	defer r.Invalidate(code)
+	return r.SwiftRepository.RestoreSwiftCode(ctx, code)
+}
+
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	purged, err := r.SwiftRepository.PurgeDeletedSwiftCodes(ctx, deletedBefore)
+	for _, swiftCode := range purged {
+		r.Invalidate(swiftCode.SwiftCode)
+	}
+	return purged, err
+}
+
+// Invalidate drops every cached entry a change to the given code can affect:
+// the code itself, the branches of its bank and its country.
+func (r *SwiftRepository) Invalidate(code string) {
+	keys := []string{codeKey(code)}
+	if len(code) >= 8 {
+		keys = append(keys, prefixKey(code[:8]), countryKey(code[4:6]))
+	}
+
+	r.entries.remove(keys...)
+	r.invalidations.Add(1)
+}
+
+func (r *SwiftRepository) load(ctx context.Context, key string, fetch func() (any, error)) (any, error) {
+	if reqctx.IncludeDeleted(ctx) {
+		return fetch()
+	}
+
+	now := time.Now()
+	if entry, ok := r.entries.get(key, now); ok {
+		if entry.err != nil {
+			r.negativeHits.Add(1)
+			return nil, entry.err
+		}
+		r.hits.Add(1)
+		return entry.value, nil
+	}
+
+	r.misses.Add(1)
+	generation := r.entries.currentGeneration()
+	value, err := fetch()
+
+	switch {
+	case err == nil:
+		r.store(&lruEntry{key: key, value: value, stored: now, expires: now.Add(r.opts.TTL)}, generation)
+	case errors.Is(err, interfaces.ErrNotFound) && r.opts.NegativeTTL > 0:
+		r.store(&lruEntry{key: key, err: err, stored: now, expires: now.Add(r.opts.NegativeTTL)}, generation)
+	case errors.Is(err, interfaces.ErrUnavailable):
+		if entry, ok := r.entries.stale(key, now); ok {
+			r.staleHits.Add(1)
+			reqctx.MarkStale(ctx, entry.stored)
+			return entry.value, entry.err
+		}
+	}
+
+	return value, err
+}
+
+// store caches an entry fetched at the given generation, unless a mutation
+// has invalidated entries since.
+func (r *SwiftRepository) store(entry *lruEntry, generation uint64) {
+	if r.entries.set(entry, generation) {
+		r.evictions.Add(1)
+	}
+}
+
+func codeKey(code string) string {
+	return "code:" + code
+}
+
+func prefixKey(prefix string) string {
+	return "prefix:" + prefix
+}
+
+func countryKey(countryISO2 string) string {
+	return "country:" + countryISO2
+}
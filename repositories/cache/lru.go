@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type lruEntry struct {
+	key     string
+	value   any
+	err     error
+	stored  time.Time
+	expires time.Time
+}
+
+// lru is a size-bounded least-recently-used map whose entries expire. Expired
+// entries are kept for a grace period, during which only stale returns them.
+// Every removal starts a new generation, so that values fetched before a
+// removal are not stored after it.
+type lru struct {
+	mu         sync.Mutex
+	capacity   int
+	grace      time.Duration
+	order      *list.List
+	items      map[string]*list.Element
+	generation uint64
+}
+
+func newLRU(capacity int, grace time.Duration) *lru {
+	return &lru{
+		capacity: capacity,
+		grace:    grace,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *lru) get(key string, now time.Time) (*lruEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if now.After(entry.expires.Add(c.grace)) {
+		c.order.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	if now.After(entry.expires) {
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return entry, true
+}
+
+// stale returns an entry whether it has expired or not, as long as it is
+// within its grace period.
+func (c *lru) stale(key string, now time.Time) (*lruEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if now.After(entry.expires.Add(c.grace)) {
+		return nil, false
+	}
+	return entry, true
+}
+
+// currentGeneration returns the generation to pass to set for a value about
+// to be fetched.
+func (c *lru) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// set stores an entry and reports whether another entry had to be evicted.
+// The entry is dropped when entries were removed since the given generation,
+// as its value may predate the change that removed them.
+func (c *lru) set(entry *lruEntry, generation uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return false
+	}
+	if el, ok := c.items[entry.key]; ok {
+		el.Value = entry
+		c.order.MoveToFront(el)
+		return false
+	}
+
+	c.items[entry.key] = c.order.PushFront(entry)
+	if c.order.Len() <= c.capacity {
+		return false
+	}
+
+	oldest := c.order.Back()
+	c.order.Remove(oldest)
+	delete(c.items, oldest.Value.(*lruEntry).key)
+	return true
+}
+
+func (c *lru) remove(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, key := range keys {
+		if el, ok := c.items[key]; ok {
+			c.order.Remove(el)
+			delete(c.items, key)
+		}
+	}
+}
+
+func (c *lru) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
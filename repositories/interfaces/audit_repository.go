@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"swift-codes-api/models"
+)
+
+type AuditRepository interface {
+	Record(ctx context.Context, entry models.AuditEntry) error
+	Find(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"swift-codes-api/models"
+)
+
+type DropFolderRepository interface {
+	CreateRun(ctx context.Context, run models.DropFolderRun) error
+	// FindImportedRun returns the latest run that imported, or is importing,
+	// a file with the given checksum.
+	FindImportedRun(ctx context.Context, checksum string) (*models.DropFolderRun, error)
+	FindRunsByStatus(ctx context.Context, status string) ([]models.DropFolderRun, error)
+	// UpdateRun replaces a run whose status is still from. It returns
+	// ErrNotFound when the run has moved on since, for instance because
+	// another replica finished it first.
+	UpdateRun(ctx context.Context, run models.DropFolderRun, from string) error
+}
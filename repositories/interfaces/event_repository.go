@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"swift-codes-api/models"
+)
+
+type EventRepository interface {
+	// Append assigns the next ID to an event and adds it to the log, such
+	// that events become visible in the order of their IDs, even when several
+	// replicas append at once. It returns the event as logged, which for an
+	// event whose SourceID is logged already is the earlier one.
+	Append(ctx context.Context, event models.Event) (models.Event, error)
+	LatestID(ctx context.Context) (int64, error)
+	FindAfter(ctx context.Context, afterID int64, countryISO2 string, limit int64) ([]models.Event, error)
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event models.Event) error
+}
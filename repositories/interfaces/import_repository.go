@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"io"
+	"swift-codes-api/models"
+	"time"
+)
+
+// ErrJobReclaimed is returned by UpdateJob when another worker has claimed
+// the job since the update's copy of it was claimed.
+var ErrJobReclaimed = errors.New("import job claimed by another worker")
+
+type ImportRepository interface {
+	CreateJob(ctx context.Context, job models.ImportJob) error
+	FindJob(ctx context.Context, id string) (*models.ImportJob, error)
+	// ClaimJob takes the oldest queued job, or a running one whose worker
+	// stopped renewing its lease, marks it running, leases it until
+	// now+lease and counts the claim in its Claims.
+	ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error)
+	// UpdateJob stores the progress and status of a job, leaving a pending
+	// cancellation request in place, and returns the stored job. It fails
+	// with ErrJobReclaimed if the job was claimed again since job was.
+	UpdateJob(ctx context.Context, job models.ImportJob) (*models.ImportJob, error)
+	// CancelJob cancels a queued job right away and flags a running one for
+	// its worker to stop, returning the job as stored afterwards.
+	CancelJob(ctx context.Context, id string, now time.Time) (*models.ImportJob, error)
+	SaveFile(ctx context.Context, id, name string, file io.Reader) (int64, error)
+	OpenFile(ctx context.Context, id string) (io.ReadCloser, error)
+	DeleteFile(ctx context.Context, id string) error
+}
@@ -2,13 +2,55 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"swift-codes-api/models"
+	"time"
 )
 
+// AnyRevision disables the revision check of UpdateSwiftCode and
+// DeleteSwiftCode.
+const AnyRevision int64 = -1
+
+// ErrNotFound is returned by every repository when the record asked for, or
+// changed, does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrAlreadyExists is wrapped by the error AddSwiftCode returns for a code
+// that is already in the directory.
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrRevisionMismatch is returned by UpdateSwiftCode and DeleteSwiftCode when
+// the stored record is no longer at the expected revision.
+var ErrRevisionMismatch = errors.New("revision mismatch")
+
+// ErrUnavailable wraps the errors of a repository whose database is down or
+// not responding, as opposed to the outcome of a request it did serve.
+var ErrUnavailable = errors.New("database unavailable")
+
+// ErrReadOnly is returned by the changes to a repository that only serves
+// lookups.
+var ErrReadOnly = errors.New("repository is read-only")
+
 type SwiftRepository interface {
 	FindByCode(ctx context.Context, code string) (*models.SwiftCode, error)
+	// FindByCodes returns the records of those codes that exist, in no
+	// particular order.
+	FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error)
 	FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error)
+	// FindBranchesByPrefixes returns the branches of every given bank prefix,
+	// in no particular order.
+	FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error)
 	FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error)
+	// StreamSwiftCodes calls fn for every SWIFT code, or for those of one
+	// country when countryISO2 is not empty, stopping at the first error.
+	StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error
+	// SearchSwiftCodes returns, ordered by code, at most limit SWIFT codes
+	// that start with query or whose bank name contains it, ignoring case,
+	// restricted to one country when countryISO2 is not empty.
+	SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error)
 	AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error
-	DeleteSwiftCode(ctx context.Context, code string) error
+	UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error
+	DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error
+	RestoreSwiftCode(ctx context.Context, code string) error
+	PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error)
 }
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"swift-codes-api/models"
+	"time"
+)
+
+type VersionRepository interface {
+	SaveVersion(ctx context.Context, record models.SwiftCode, deleted bool, at time.Time) error
+	// SaveInitialVersion saves record as the first version of its code, valid
+	// from validFrom until the record was deleted, unless the code already
+	// has versions. It backfills records stored before versions were kept.
+	SaveInitialVersion(ctx context.Context, record models.SwiftCode, validFrom time.Time) error
+	FindHistory(ctx context.Context, code string) ([]models.SwiftCodeVersion, error)
+	FindByCodeAsOf(ctx context.Context, code string, asOf time.Time) (*models.SwiftCode, error)
+	FindBranchesByPrefixAsOf(ctx context.Context, prefix string, asOf time.Time) ([]models.SwiftCode, error)
+	FindByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time) ([]models.SwiftCode, string, error)
+}
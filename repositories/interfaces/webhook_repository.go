@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"swift-codes-api/models"
+	"time"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) error
+	FindSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	FindSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	EnqueueDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, subscriptionID string, limit int64) ([]models.WebhookDelivery, error)
+	// FindCursor returns the ID of the last event deliveries were enqueued
+	// for, or ErrNotFound before the first one.
+	FindCursor(ctx context.Context) (int64, error)
+	// SaveCursor moves the cursor forward to eventID. It never moves back.
+	SaveCursor(ctx context.Context, eventID int64) error
+}
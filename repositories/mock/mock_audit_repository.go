@@ -0,0 +1,24 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/models"
+)
+
+type AuditRepository struct {
+	mock.Mock
+}
+
+func (m *AuditRepository) Record(ctx context.Context, entry models.AuditEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *AuditRepository) Find(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.AuditEntry), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
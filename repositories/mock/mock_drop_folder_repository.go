@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/models"
+)
+
+type DropFolderRepository struct {
+	mock.Mock
+}
+
+func (m *DropFolderRepository) CreateRun(ctx context.Context, run models.DropFolderRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *DropFolderRepository) FindImportedRun(ctx context.Context, checksum string) (*models.DropFolderRun, error) {
+	args := m.Called(ctx, checksum)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.DropFolderRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *DropFolderRepository) FindRunsByStatus(ctx context.Context, status string) ([]models.DropFolderRun, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.DropFolderRun), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *DropFolderRepository) UpdateRun(ctx context.Context, run models.DropFolderRun, from string) error {
+	args := m.Called(ctx, run, from)
+	return args.Error(0)
+}
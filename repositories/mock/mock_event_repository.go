@@ -0,0 +1,38 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/models"
+)
+
+type EventRepository struct {
+	mock.Mock
+}
+
+func (m *EventRepository) Append(ctx context.Context, event models.Event) (models.Event, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(models.Event), args.Error(1)
+}
+
+func (m *EventRepository) LatestID(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *EventRepository) FindAfter(ctx context.Context, afterID int64, countryISO2 string, limit int64) ([]models.Event, error) {
+	args := m.Called(ctx, afterID, countryISO2, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.Event), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type EventPublisher struct {
+	mock.Mock
+}
+
+func (m *EventPublisher) Publish(ctx context.Context, event models.Event) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
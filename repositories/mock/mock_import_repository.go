@@ -0,0 +1,68 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"swift-codes-api/models"
+	"time"
+)
+
+type ImportRepository struct {
+	mock.Mock
+}
+
+func (m *ImportRepository) CreateJob(ctx context.Context, job models.ImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *ImportRepository) FindJob(ctx context.Context, id string) (*models.ImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ImportRepository) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error) {
+	args := m.Called(ctx, now, lease)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ImportRepository) UpdateJob(ctx context.Context, job models.ImportJob) (*models.ImportJob, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ImportRepository) CancelJob(ctx context.Context, id string, now time.Time) (*models.ImportJob, error) {
+	args := m.Called(ctx, id, now)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.ImportJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ImportRepository) SaveFile(ctx context.Context, id, name string, file io.Reader) (int64, error) {
+	args := m.Called(ctx, id, name, file)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ImportRepository) OpenFile(ctx context.Context, id string) (io.ReadCloser, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(io.ReadCloser), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *ImportRepository) DeleteFile(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/stretchr/testify/mock"
 	"swift-codes-api/models"
+	"time"
 )
 
 type SwiftRepository struct {
@@ -18,6 +19,14 @@ func (m *SwiftRepository) FindByCode(ctx context.Context, code string) (*models.
 	return nil, args.Error(1)
 }
 
+func (m *SwiftRepository) FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error) {
+	args := m.Called(ctx, codes)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
 	args := m.Called(ctx, prefix)
 	if args.Get(0) != nil {
@@ -26,6 +35,14 @@ func (m *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix strin
 	return nil, args.Error(1)
 }
 
+func (m *SwiftRepository) FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	args := m.Called(ctx, prefixes)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
 	args := m.Called(ctx, countryISO2)
 	if args.Get(0) != nil && args.Get(1) != nil {
@@ -34,12 +51,50 @@ func (m *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 str
 	return nil, "", args.Error(2)
 }
 
+func (m *SwiftRepository) StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error {
+	args := m.Called(ctx, countryISO2)
+	if args.Get(0) != nil {
+		for _, swiftCode := range args.Get(0).([]models.SwiftCode) {
+			if err := fn(swiftCode); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *SwiftRepository) SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error) {
+	args := m.Called(ctx, query, countryISO2, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
 	args := m.Called(ctx, swiftCode)
 	return args.Error(0)
 }
 
-func (m *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string) error {
+func (m *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	args := m.Called(ctx, swiftCode, expectedRevision)
+	return args.Error(0)
+}
+
+func (m *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	args := m.Called(ctx, code, expectedRevision)
+	return args.Error(0)
+}
+
+func (m *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
 	args := m.Called(ctx, code)
 	return args.Error(0)
 }
+
+func (m *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	args := m.Called(ctx, deletedBefore)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
@@ -0,0 +1,54 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/models"
+	"time"
+)
+
+type VersionRepository struct {
+	mock.Mock
+}
+
+func (m *VersionRepository) SaveVersion(ctx context.Context, record models.SwiftCode, deleted bool, at time.Time) error {
+	args := m.Called(ctx, record, deleted, at)
+	return args.Error(0)
+}
+
+func (m *VersionRepository) SaveInitialVersion(ctx context.Context, record models.SwiftCode, validFrom time.Time) error {
+	args := m.Called(ctx, record, validFrom)
+	return args.Error(0)
+}
+
+func (m *VersionRepository) FindHistory(ctx context.Context, code string) ([]models.SwiftCodeVersion, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCodeVersion), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *VersionRepository) FindByCodeAsOf(ctx context.Context, code string, asOf time.Time) (*models.SwiftCode, error) {
+	args := m.Called(ctx, code, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *VersionRepository) FindBranchesByPrefixAsOf(ctx context.Context, prefix string, asOf time.Time) ([]models.SwiftCode, error) {
+	args := m.Called(ctx, prefix, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *VersionRepository) FindByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time) ([]models.SwiftCode, string, error) {
+	args := m.Called(ctx, countryISO2, asOf)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.SwiftCode), args.String(1), args.Error(2)
+	}
+	return nil, "", args.Error(2)
+}
@@ -0,0 +1,74 @@
+package mock
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/models"
+	"time"
+)
+
+type WebhookRepository struct {
+	mock.Mock
+}
+
+func (m *WebhookRepository) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *WebhookRepository) FindSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WebhookRepository) FindSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.WebhookSubscription), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepository) EnqueueDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *WebhookRepository) ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, lease)
+	if args.Get(0) != nil {
+		return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WebhookRepository) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *WebhookRepository) FindDeliveries(ctx context.Context, subscriptionID string, limit int64) ([]models.WebhookDelivery, error) {
+	args := m.Called(ctx, subscriptionID, limit)
+	if args.Get(0) != nil {
+		return args.Get(0).([]models.WebhookDelivery), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *WebhookRepository) FindCursor(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *WebhookRepository) SaveCursor(ctx context.Context, eventID int64) error {
+	args := m.Called(ctx, eventID)
+	return args.Error(0)
+}
@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"swift-codes-api/repositories/interfaces"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// notFound translates the driver's mongo.ErrNoDocuments into
+// interfaces.ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return interfaces.ErrNotFound
+	}
+	return err
+}
+
+// unavailable wraps the driver's network and timeout errors, server selection
+// timeouts among them, in interfaces.ErrUnavailable and leaves the others as
+// they are. The SWIFT code repository leaves this to the circuit breaker in
+// front of it.
+func unavailable(err error) error {
+	if err != nil && (mongo.IsNetworkError(err) || mongo.IsTimeout(err)) {
+		return fmt.Errorf("%w: %w", interfaces.ErrUnavailable, err)
+	}
+	return err
+}
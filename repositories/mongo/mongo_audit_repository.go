@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"context"
+	"swift-codes-api/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditRepository struct {
+	col *mongo.Collection
+}
+
+func NewAuditRepository(db *mongo.Database) *AuditRepository {
+	return &AuditRepository{
+		col: db.Collection("audit"),
+	}
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry models.AuditEntry) error {
+	_, err := r.col.InsertOne(ctx, entry)
+	return err
+}
+
+func (r *AuditRepository) Find(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := bson.M{}
+	if filter.SwiftCode != "" {
+		query["swiftCode"] = filter.SwiftCode
+	}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lte"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(filter.Limit)
+	}
+
+	cursor, err := r.col.Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []models.AuditEntry{}
+	err = cursor.All(ctx, &entries)
+	return entries, err
+}
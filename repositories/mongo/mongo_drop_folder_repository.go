@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DropFolderRepository struct {
+	col *mongo.Collection
+}
+
+func NewDropFolderRepository(db *mongo.Database) *DropFolderRepository {
+	return &DropFolderRepository{
+		col: db.Collection("drop-folder-runs"),
+	}
+}
+
+func (r *DropFolderRepository) CreateRun(ctx context.Context, run models.DropFolderRun) error {
+	_, err := r.col.InsertOne(ctx, run)
+	return err
+}
+
+func (r *DropFolderRepository) FindImportedRun(ctx context.Context, checksum string) (*models.DropFolderRun, error) {
+	var run models.DropFolderRun
+	err := r.col.FindOne(ctx,
+		bson.M{
+			"checksum": checksum,
+			"status":   bson.M{"$in": bson.A{models.DropFolderRunImporting, models.DropFolderRunSucceeded}},
+		},
+		options.FindOne().SetSort(bson.D{{Key: "startedAt", Value: -1}}),
+	).Decode(&run)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &run, nil
+}
+
+func (r *DropFolderRepository) FindRunsByStatus(ctx context.Context, status string) ([]models.DropFolderRun, error) {
+	cursor, err := r.col.Find(ctx, bson.M{"status": status},
+		options.Find().SetSort(bson.D{{Key: "startedAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []models.DropFolderRun{}
+	err = cursor.All(ctx, &runs)
+	return runs, err
+}
+
+func (r *DropFolderRepository) UpdateRun(ctx context.Context, run models.DropFolderRun, from string) error {
+	result, err := r.col.ReplaceOne(ctx, bson.M{"_id": run.ID, "status": from}, run)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return interfaces.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"swift-codes-api/models"
+	"sync"
+	"sync/atomic"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const eventCounterID = "events"
+
+// illegalOperation is the error code of servers refusing transactions
+// because they are not part of a replica set.
+const illegalOperation = 20
+
+type EventRepository struct {
+	col      *mongo.Collection
+	counters *mongo.Collection
+
+	// standalone is set once the server turned out not to support
+	// transactions; appends are then only ordered within this process.
+	standalone atomic.Bool
+	mu         sync.Mutex
+}
+
+func NewEventRepository(db *mongo.Database) *EventRepository {
+	r := &EventRepository{
+		col:      db.Collection("events"),
+		counters: db.Collection("counters"),
+	}
+
+	ctx := context.Background()
+	_, err := r.col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sourceId", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"sourceId": bson.M{"$exists": true}}),
+	})
+	if err != nil {
+		log.Printf("Failed to create the events source index: %v", err)
+	}
+
+	// The counter must stay ahead of every logged ID, whatever assigned it.
+	if latest, err := r.LatestID(ctx); err != nil {
+		log.Printf("Failed to read the latest event ID: %v", err)
+	} else if _, err = r.counters.UpdateOne(ctx,
+		bson.M{"_id": eventCounterID},
+		bson.M{"$max": bson.M{"seq": latest}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		log.Printf("Failed to advance the event counter: %v", err)
+	}
+
+	return r
+}
+
+// Append takes the next ID from the counter and inserts the event in one
+// transaction. Transactions taking an ID conflict on the counter until the
+// one before them commits, so no event becomes visible before those with
+// lower IDs. On a standalone server, which has no transactions, this only
+// holds for the appends of one process.
+func (r *EventRepository) Append(ctx context.Context, event models.Event) (models.Event, error) {
+	if event.SourceID != "" {
+		logged, err := r.findBySource(ctx, event.SourceID)
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return logged, err
+		}
+	}
+
+	err := r.append(ctx, &event)
+	if mongo.IsDuplicateKeyError(err) && event.SourceID != "" {
+		return r.findBySource(ctx, event.SourceID)
+	}
+	return event, err
+}
+
+func (r *EventRepository) append(ctx context.Context, event *models.Event) error {
+	if r.standalone.Load() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.insertNext(ctx, event)
+	}
+
+	session, err := r.col.Database().Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, r.insertNext(sc, event)
+	})
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(illegalOperation) {
+		log.Printf("MongoDB does not support transactions, events are only ordered within this process")
+		r.standalone.Store(true)
+		return r.append(ctx, event)
+	}
+	return err
+}
+
+func (r *EventRepository) insertNext(ctx context.Context, event *models.Event) error {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOneAndUpdate(ctx,
+		bson.M{"_id": eventCounterID},
+		bson.M{"$inc": bson.M{"seq": int64(1)}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&counter)
+	if err != nil {
+		return err
+	}
+
+	event.ID = counter.Seq
+	_, err = r.col.InsertOne(ctx, event)
+	return err
+}
+
+func (r *EventRepository) findBySource(ctx context.Context, sourceID string) (models.Event, error) {
+	var logged models.Event
+	err := r.col.FindOne(ctx, bson.M{"sourceId": sourceID}).Decode(&logged)
+	return logged, err
+}
+
+func (r *EventRepository) LatestID(ctx context.Context) (int64, error) {
+	var latest models.Event
+	err := r.col.FindOne(ctx, bson.M{},
+		options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}}),
+	).Decode(&latest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	return latest.ID, err
+}
+
+func (r *EventRepository) FindAfter(ctx context.Context, afterID int64, countryISO2 string, limit int64) ([]models.Event, error) {
+	filter := bson.M{"_id": bson.M{"$gt": afterID}}
+	if countryISO2 != "" {
+		filter["countryISO2"] = countryISO2
+	}
+
+	cursor, err := r.col.Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []models.Event
+	err = cursor.All(ctx, &events)
+	return events, err
+}
@@ -0,0 +1,149 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ImportRepository keeps import jobs in a collection and their uploaded files
+// in GridFS, so that queued and interrupted jobs survive a restart.
+type ImportRepository struct {
+	jobs  *mongo.Collection
+	files *gridfs.Bucket
+}
+
+func NewImportRepository(db *mongo.Database) *ImportRepository {
+	files, err := gridfs.NewBucket(db, options.GridFSBucket().SetName("import-files"))
+	if err != nil {
+		panic(err)
+	}
+
+	return &ImportRepository{
+		jobs:  db.Collection("import-jobs"),
+		files: files,
+	}
+}
+
+func (r *ImportRepository) CreateJob(ctx context.Context, job models.ImportJob) error {
+	_, err := r.jobs.InsertOne(ctx, job)
+	return unavailable(err)
+}
+
+func (r *ImportRepository) FindJob(ctx context.Context, id string) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.jobs.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if err != nil {
+		return nil, unavailable(notFound(err))
+	}
+	return &job, nil
+}
+
+func (r *ImportRepository) ClaimJob(ctx context.Context, now time.Time, lease time.Duration) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.jobs.FindOneAndUpdate(ctx,
+		bson.M{
+			"status":     bson.M{"$in": bson.A{models.ImportStatusQueued, models.ImportStatusRunning}},
+			"leaseUntil": bson.M{"$lte": now},
+		},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"status":     models.ImportStatusRunning,
+			"leaseUntil": now.Add(lease),
+			"startedAt":  bson.M{"$ifNull": bson.A{"$startedAt", now}},
+			"claims":     bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$claims", 0}}, 1}},
+		}}}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &job, nil
+}
+
+func (r *ImportRepository) UpdateJob(ctx context.Context, job models.ImportJob) (*models.ImportJob, error) {
+	set := bson.M{
+		"status":     job.Status,
+		"bytesRead":  job.BytesRead,
+		"processed":  job.Processed,
+		"created":    job.Created,
+		"updated":    job.Updated,
+		"unchanged":  job.Unchanged,
+		"failed":     job.Failed,
+		"errors":     job.Errors,
+		"error":      job.Error,
+		"leaseUntil": job.LeaseUntil,
+	}
+	if job.FinishedAt != nil {
+		set["finishedAt"] = job.FinishedAt
+	}
+
+	var stored models.ImportJob
+	err := r.jobs.FindOneAndUpdate(ctx, bson.M{"_id": job.ID, "claims": job.Claims}, bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&stored)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err = r.FindJob(ctx, job.ID); err == nil {
+			return nil, interfaces.ErrJobReclaimed
+		}
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *ImportRepository) CancelJob(ctx context.Context, id string, now time.Time) (*models.ImportJob, error) {
+	queued := bson.M{"$eq": bson.A{"$status", models.ImportStatusQueued}}
+	unfinished := bson.M{"$in": bson.A{"$status", bson.A{models.ImportStatusQueued, models.ImportStatusRunning}}}
+
+	var job models.ImportJob
+	err := r.jobs.FindOneAndUpdate(ctx, bson.M{"_id": id},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"status":          bson.M{"$cond": bson.A{queued, models.ImportStatusCancelled, "$status"}},
+			"finishedAt":      bson.M{"$cond": bson.A{queued, now, "$finishedAt"}},
+			"cancelRequested": bson.M{"$cond": bson.A{unfinished, true, "$cancelRequested"}},
+		}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&job)
+	if err != nil {
+		return nil, unavailable(notFound(err))
+	}
+	return &job, nil
+}
+
+func (r *ImportRepository) SaveFile(ctx context.Context, id, name string, file io.Reader) (int64, error) {
+	upload, err := r.files.OpenUploadStreamWithID(id, name)
+	if err != nil {
+		return 0, unavailable(err)
+	}
+
+	size, err := io.Copy(upload, file)
+	if err != nil {
+		_ = upload.Abort()
+		return 0, unavailable(err)
+	}
+	return size, unavailable(upload.Close())
+}
+
+func (r *ImportRepository) OpenFile(ctx context.Context, id string) (io.ReadCloser, error) {
+	return r.files.OpenDownloadStream(id)
+}
+
+func (r *ImportRepository) DeleteFile(ctx context.Context, id string) error {
+	err := r.files.DeleteContext(ctx, id)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil
+	}
+	return err
+}
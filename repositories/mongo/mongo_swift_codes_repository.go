@@ -3,10 +3,16 @@ package mongo
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strings"
+	"swift-codes-api/internal/reqctx"
 	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"fmt"
 )
@@ -21,20 +27,65 @@ func NewSwiftRepository(db *mongo.Database) *SwiftRepository {
 	}
 }
 
+// live restricts a query to records that have not been soft-deleted, unless
+// the caller asked for deleted records as well.
+func live(ctx context.Context, filter bson.M) bson.M {
+	if !reqctx.IncludeDeleted(ctx) {
+		filter["deletedAt"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// atRevision restricts a mutation to a record still at the expected revision.
+// Records stored before revisions were introduced are at revision 0.
+func atRevision(filter bson.M, expectedRevision int64) bson.M {
+	switch expectedRevision {
+	case interfaces.AnyRevision:
+	case 0:
+		filter["revision"] = bson.M{"$in": bson.A{0, nil}}
+	default:
+		filter["revision"] = expectedRevision
+	}
+	return filter
+}
+
 func (r *SwiftRepository) FindByCode(ctx context.Context, code string) (*models.SwiftCode, error) {
 	var result models.SwiftCode
-	err := r.col.FindOne(ctx, bson.M{"swiftCode": code}).Decode(&result)
+	err := r.col.FindOne(ctx, live(ctx, bson.M{"swiftCode": code})).Decode(&result)
 	if err != nil {
-		return nil, err
+		return nil, notFound(err)
 	}
 	return &result, nil
 }
 
+func (r *SwiftRepository) FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error) {
+	cursor, err := r.col.Find(ctx, live(ctx, bson.M{"swiftCode": bson.M{"$in": codes}}))
+	if err != nil {
+		return nil, err
+	}
+	var swiftCodes []models.SwiftCode
+	err = cursor.All(ctx, &swiftCodes)
+	return swiftCodes, err
+}
+
 func (r *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
-	cursor, err := r.col.Find(ctx, bson.M{
+	cursor, err := r.col.Find(ctx, live(ctx, bson.M{
 		"swiftPrefix":   prefix,
 		"isHeadquarter": false,
-	})
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var branches []models.SwiftCode
+	err = cursor.All(ctx, &branches)
+	return branches, err
+}
+
+func (r *SwiftRepository) FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	cursor, err := r.col.Find(ctx, live(ctx, bson.M{
+		"swiftPrefix":   bson.M{"$in": prefixes},
+		"isHeadquarter": false,
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +95,7 @@ func (r *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix strin
 }
 
 func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
-	cursor, err := r.col.Find(ctx, bson.M{"countryISO2": countryISO2})
+	cursor, err := r.col.Find(ctx, live(ctx, bson.M{"countryISO2": countryISO2}))
 
 	if err != nil {
 		return nil, "", err
@@ -54,7 +105,7 @@ func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 str
 	err = cursor.All(ctx, &swiftCodes)
 
 	if len(swiftCodes) == 0 {
-		return nil, "", mongo.ErrNoDocuments
+		return nil, "", interfaces.ErrNotFound
 	}
 
 	countryName := swiftCodes[0].CountryName
@@ -62,30 +113,185 @@ func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 str
 	return swiftCodes, countryName, err
 }
 
+func (r *SwiftRepository) StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error {
+	filter := bson.M{}
+	if countryISO2 != "" {
+		filter["countryISO2"] = countryISO2
+	}
+
+	cursor, err := r.col.Find(ctx, live(ctx, filter))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var swiftCode models.SwiftCode
+		if err := cursor.Decode(&swiftCode); err != nil {
+			return err
+		}
+		if err := fn(swiftCode); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// SearchSwiftCodes walks the swiftCode index and stops at limit matches, so
+// the database never sends more records than the page needs.
+func (r *SwiftRepository) SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"swiftCode": bson.M{"$regex": "^" + regexp.QuoteMeta(strings.ToUpper(query))}},
+		bson.M{"bankName": bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}},
+	}}
+	if countryISO2 != "" {
+		filter["countryISO2"] = countryISO2
+	}
+
+	cursor, err := r.col.Find(ctx, live(ctx, filter),
+		options.Find().SetSort(bson.D{{Key: "swiftCode", Value: 1}}).SetLimit(int64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	var swiftCodes []models.SwiftCode
+	err = cursor.All(ctx, &swiftCodes)
+	return swiftCodes, err
+}
+
 func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
 	swiftCode.SwiftPrefix = swiftCode.SwiftCode[:8]
+	swiftCode.DeletedAt = nil
+	swiftCode.UpdatedAt = time.Now().UTC()
+	swiftCode.Revision = 1
 
 	var existing models.SwiftCode
 	err := r.col.FindOne(ctx, bson.M{"swiftCode": swiftCode.SwiftCode}).Decode(&existing)
 	if err == nil {
-		return fmt.Errorf("SWIFT code %s already exists", swiftCode.SwiftCode)
+		if existing.DeletedAt == nil {
+			return fmt.Errorf("SWIFT code %s %w", swiftCode.SwiftCode, interfaces.ErrAlreadyExists)
+		}
+
+		swiftCode.Revision = existing.Revision + 1
+		result, err := r.col.ReplaceOne(ctx,
+			atRevision(bson.M{"swiftCode": swiftCode.SwiftCode, "deletedAt": bson.M{"$exists": true}}, existing.Revision),
+			swiftCode,
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return fmt.Errorf("SWIFT code %s %w", swiftCode.SwiftCode, interfaces.ErrAlreadyExists)
+		}
+		return nil
 	} else if !errors.Is(err, mongo.ErrNoDocuments) {
 		return err
 	}
 
 	_, err = r.col.InsertOne(ctx, swiftCode)
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("SWIFT code %s %w", swiftCode.SwiftCode, interfaces.ErrAlreadyExists)
+	}
 	return err
 }
 
-func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string) error {
-	result, err := r.col.DeleteOne(ctx, bson.M{"swiftCode": code})
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	filter := atRevision(bson.M{"swiftCode": swiftCode.SwiftCode, "deletedAt": bson.M{"$exists": false}}, expectedRevision)
+	result, err := r.col.UpdateOne(ctx, filter, bson.M{
+		"$set": bson.M{
+			"isHeadquarter": swiftCode.IsHeadquarter,
+			"bankName":      swiftCode.BankName,
+			"address":       swiftCode.Address,
+			"countryISO2":   swiftCode.CountryISO2,
+			"countryName":   swiftCode.CountryName,
+			"updatedAt":     time.Now().UTC(),
+		},
+		"$inc": bson.M{"revision": 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return r.missOrConflict(ctx, swiftCode.SwiftCode)
+	}
+
+	return nil
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	now := time.Now().UTC()
+	result, err := r.col.UpdateOne(ctx,
+		atRevision(bson.M{"swiftCode": code, "deletedAt": bson.M{"$exists": false}}, expectedRevision),
+		bson.M{
+			"$set": bson.M{"deletedAt": now, "updatedAt": now},
+			"$inc": bson.M{"revision": 1},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return r.missOrConflict(ctx, code)
+	}
+
+	return nil
+}
+
+// missOrConflict explains why a conditional mutation matched nothing: either
+// the live record is gone or it has moved past the expected revision.
+func (r *SwiftRepository) missOrConflict(ctx context.Context, code string) error {
+	err := r.col.FindOne(ctx, bson.M{"swiftCode": code, "deletedAt": bson.M{"$exists": false}}).Err()
+	if err == nil {
+		return interfaces.ErrRevisionMismatch
+	}
+	return notFound(err)
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	result, err := r.col.UpdateOne(ctx,
+		bson.M{"swiftCode": code, "deletedAt": bson.M{"$exists": true}},
+		bson.M{
+			"$set":   bson.M{"updatedAt": time.Now().UTC()},
+			"$unset": bson.M{"deletedAt": ""},
+			"$inc":   bson.M{"revision": 1},
+		},
+	)
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
-		return mongo.ErrNoDocuments
+	if result.MatchedCount == 0 {
+		return interfaces.ErrNotFound
 	}
 
 	return nil
 }
+
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	filter := bson.M{"deletedAt": bson.M{"$lt": deletedBefore}}
+
+	cursor, err := r.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []models.SwiftCode
+	if err = cursor.All(ctx, &purged); err != nil {
+		return nil, err
+	}
+	if len(purged) == 0 {
+		return nil, nil
+	}
+
+	codes := make([]string, len(purged))
+	for i, swiftCode := range purged {
+		codes[i] = swiftCode.SwiftCode
+	}
+
+	_, err = r.col.DeleteMany(ctx, bson.M{
+		"swiftCode": bson.M{"$in": codes},
+		"deletedAt": bson.M{"$lt": deletedBefore},
+	})
+	return purged, err
+}
@@ -0,0 +1,186 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"log"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// saveVersionAttempts bounds the retries of SaveVersion when other writers
+// take the version number it picked.
+const saveVersionAttempts = 5
+
+type VersionRepository struct {
+	col *mongo.Collection
+}
+
+// NewVersionRepository returns a repository backed by the
+// swift-code-versions collection, whose unique index on the code and version
+// keeps concurrent writers from saving the same version twice.
+func NewVersionRepository(db *mongo.Database) *VersionRepository {
+	col := db.Collection("swift-code-versions")
+	_, err := col.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "swiftCode", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		log.Printf("Failed to create the SWIFT code versions index: %v", err)
+	}
+
+	return &VersionRepository{
+		col: col,
+	}
+}
+
+// SaveVersion closes the current version of the code at at and saves record
+// as the next one, trying again with a later version number when another
+// writer saved the one it picked first.
+func (r *VersionRepository) SaveVersion(ctx context.Context, record models.SwiftCode, deleted bool, at time.Time) error {
+	var err error
+	for range saveVersionAttempts {
+		if err = r.saveNextVersion(ctx, record, deleted, at); !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+func (r *VersionRepository) saveNextVersion(ctx context.Context, record models.SwiftCode, deleted bool, at time.Time) error {
+	var latest models.SwiftCodeVersion
+	err := r.col.FindOne(ctx,
+		bson.M{"swiftCode": record.SwiftCode},
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}),
+	).Decode(&latest)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	if err == nil && latest.ValidTo == nil {
+		_, err = r.col.UpdateOne(ctx,
+			bson.M{"swiftCode": record.SwiftCode, "version": latest.Version, "validTo": nil},
+			bson.M{"$set": bson.M{"validTo": at}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = r.col.InsertOne(ctx, models.SwiftCodeVersion{
+		SwiftCode: record.SwiftCode,
+		Version:   latest.Version + 1,
+		ValidFrom: at,
+		Deleted:   deleted,
+		Record:    record,
+	})
+	return err
+}
+
+func (r *VersionRepository) SaveInitialVersion(ctx context.Context, record models.SwiftCode, validFrom time.Time) error {
+	_, err := r.col.UpdateOne(ctx,
+		bson.M{"swiftCode": record.SwiftCode, "version": 1},
+		bson.M{"$setOnInsert": models.SwiftCodeVersion{
+			SwiftCode: record.SwiftCode,
+			Version:   1,
+			ValidFrom: validFrom,
+			ValidTo:   record.DeletedAt,
+			Record:    record,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another writer saved the first version meanwhile.
+		return nil
+	}
+	return err
+}
+
+func (r *VersionRepository) FindHistory(ctx context.Context, code string) ([]models.SwiftCodeVersion, error) {
+	cursor, err := r.col.Find(ctx,
+		bson.M{"swiftCode": code},
+		options.Find().SetSort(bson.D{{Key: "version", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.SwiftCodeVersion
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, interfaces.ErrNotFound
+	}
+
+	return versions, nil
+}
+
+// validAt matches the versions that were current at asOf and not deleted.
+func validAt(filter bson.M, asOf time.Time) bson.M {
+	filter["deleted"] = false
+	filter["validFrom"] = bson.M{"$lte": asOf}
+	filter["$or"] = bson.A{
+		bson.M{"validTo": nil},
+		bson.M{"validTo": bson.M{"$gt": asOf}},
+	}
+	return filter
+}
+
+func (r *VersionRepository) findRecordsAsOf(ctx context.Context, filter bson.M, asOf time.Time) ([]models.SwiftCode, error) {
+	cursor, err := r.col.Find(ctx, validAt(filter, asOf))
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []models.SwiftCodeVersion
+	if err = cursor.All(ctx, &versions); err != nil {
+		return nil, err
+	}
+
+	records := make([]models.SwiftCode, len(versions))
+	for i, version := range versions {
+		records[i] = version.Record
+		records[i].DeletedAt = nil
+	}
+	return records, nil
+}
+
+func (r *VersionRepository) FindByCodeAsOf(ctx context.Context, code string, asOf time.Time) (*models.SwiftCode, error) {
+	records, err := r.findRecordsAsOf(ctx, bson.M{"swiftCode": code}, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, interfaces.ErrNotFound
+	}
+
+	return &records[0], nil
+}
+
+func (r *VersionRepository) FindBranchesByPrefixAsOf(ctx context.Context, prefix string, asOf time.Time) ([]models.SwiftCode, error) {
+	return r.findRecordsAsOf(ctx, bson.M{
+		"record.swiftPrefix":   prefix,
+		"record.isHeadquarter": false,
+	}, asOf)
+}
+
+func (r *VersionRepository) FindByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time) ([]models.SwiftCode, string, error) {
+	records, err := r.findRecordsAsOf(ctx, bson.M{"record.countryISO2": countryISO2}, asOf)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(records) == 0 {
+		return nil, "", interfaces.ErrNotFound
+	}
+
+	return records, records[0].CountryName, nil
+}
@@ -0,0 +1,141 @@
+package mongo
+
+import (
+	"context"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+	cursors       *mongo.Collection
+}
+
+const dispatcherCursorID = "dispatcher"
+
+type cursorDocument struct {
+	LastEventID int64 `bson:"lastEventId"`
+}
+
+func NewWebhookRepository(db *mongo.Database) *WebhookRepository {
+	return &WebhookRepository{
+		subscriptions: db.Collection("webhook-subscriptions"),
+		deliveries:    db.Collection("webhook-deliveries"),
+		cursors:       db.Collection("webhook-cursors"),
+	}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) error {
+	_, err := r.subscriptions.InsertOne(ctx, subscription)
+	return err
+}
+
+func (r *WebhookRepository) FindSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.subscriptions.FindOne(ctx, bson.M{"_id": id}).Decode(&subscription)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookRepository) FindSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	cursor, err := r.subscriptions.Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions := []models.WebhookSubscription{}
+	err = cursor.All(ctx, &subscriptions)
+	return subscriptions, err
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	result, err := r.subscriptions.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return interfaces.ErrNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDelivery queues a delivery. Delivery IDs are derived from the
+// subscription and event, so enqueueing the same delivery twice is a no-op.
+func (r *WebhookRepository) EnqueueDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	_, err := r.deliveries.InsertOne(ctx, delivery)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// ClaimDelivery takes the oldest due pending delivery and pushes its next
+// attempt back by lease, so that no other worker picks it up meanwhile.
+func (r *WebhookRepository) ClaimDelivery(ctx context.Context, now time.Time, lease time.Duration) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.deliveries.FindOneAndUpdate(ctx,
+		bson.M{
+			"status":        models.DeliveryStatusPending,
+			"nextAttemptAt": bson.M{"$lte": now},
+		},
+		bson.M{"$set": bson.M{"nextAttemptAt": now.Add(lease)}},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	_, err := r.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
+
+func (r *WebhookRepository) FindDeliveries(ctx context.Context, subscriptionID string, limit int64) ([]models.WebhookDelivery, error) {
+	cursor, err := r.deliveries.Find(ctx,
+		bson.M{"subscriptionId": subscriptionID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	err = cursor.All(ctx, &deliveries)
+	return deliveries, err
+}
+
+func (r *WebhookRepository) FindCursor(ctx context.Context) (int64, error) {
+	var cursor cursorDocument
+	err := r.cursors.FindOne(ctx, bson.M{"_id": dispatcherCursorID}).Decode(&cursor)
+	if err != nil {
+		return 0, notFound(err)
+	}
+	return cursor.LastEventID, nil
+}
+
+// SaveCursor uses $max, so that replicas enqueueing the same events in
+// parallel cannot move the cursor back.
+func (r *WebhookRepository) SaveCursor(ctx context.Context, eventID int64) error {
+	_, err := r.cursors.UpdateOne(ctx,
+		bson.M{"_id": dispatcherCursorID},
+		bson.M{"$max": bson.M{"lastEventId": eventID}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,91 @@
+package publishing
+
+import (
+	"context"
+	"log"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+)
+
+// SwiftRepository publishes a change event for every successful mutation.
+type SwiftRepository struct {
+	interfaces.SwiftRepository
+	publisher interfaces.EventPublisher
+}
+
+func NewSwiftRepository(repo interfaces.SwiftRepository, publisher interfaces.EventPublisher) *SwiftRepository {
+	return &SwiftRepository{
+		SwiftRepository: repo,
+		publisher:       publisher,
+	}
+}
+
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	if err := r.SwiftRepository.AddSwiftCode(ctx, swiftCode); err != nil {
+		return err
+	}
+
+	record, err := r.SwiftRepository.FindByCode(ctx, swiftCode.SwiftCode)
+	if err != nil {
+		record = &swiftCode
+	}
+
+	r.publish(ctx, models.EventTypeCreated, record)
+	return nil
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	if err := r.SwiftRepository.UpdateSwiftCode(ctx, swiftCode, expectedRevision); err != nil {
+		return err
+	}
+
+	record, err := r.SwiftRepository.FindByCode(ctx, swiftCode.SwiftCode)
+	if err != nil {
+		record = &swiftCode
+	}
+
+	r.publish(ctx, models.EventTypeUpdated, record)
+	return nil
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	if err := r.SwiftRepository.DeleteSwiftCode(ctx, code, expectedRevision); err != nil {
+		return err
+	}
+
+	record, err := r.SwiftRepository.FindByCode(reqctx.WithIncludeDeleted(ctx), code)
+	if err != nil {
+		record = &models.SwiftCode{SwiftCode: code, CountryISO2: code[4:6]}
+	}
+
+	r.publish(ctx, models.EventTypeDeleted, record)
+	return nil
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	if err := r.SwiftRepository.RestoreSwiftCode(ctx, code); err != nil {
+		return err
+	}
+
+	record, err := r.SwiftRepository.FindByCode(ctx, code)
+	if err != nil {
+		record = &models.SwiftCode{SwiftCode: code, CountryISO2: code[4:6]}
+	}
+
+	r.publish(ctx, models.EventTypeRestored, record)
+	return nil
+}
+
+func (r *SwiftRepository) publish(ctx context.Context, eventType string, record *models.SwiftCode) {
+	event := models.Event{
+		Type:        eventType,
+		SwiftCode:   record.SwiftCode,
+		CountryISO2: record.CountryISO2,
+		Record:      record,
+	}
+
+	if err := r.publisher.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for %s: %v", eventType, record.SwiftCode, err)
+	}
+}
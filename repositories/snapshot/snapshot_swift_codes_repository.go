@@ -0,0 +1,276 @@
+package snapshot
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"swift-codes-api/internal/snapshot"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// handle is an open snapshot shared by the lookups in flight. The repository
+// holds one reference until it swaps in another snapshot.
+type handle struct {
+	snapshot *snapshot.Snapshot
+	modTime  time.Time
+	size     int64
+	refs     atomic.Int64
+	close    sync.Once
+}
+
+func (h *handle) release() {
+	if h.refs.Add(-1) == 0 {
+		h.close.Do(func() { h.snapshot.Close() })
+	}
+}
+
+// SwiftRepository answers lookups from a memory-mapped snapshot file and
+// rejects every change with interfaces.ErrReadOnly. Reload swaps in a newer
+// file without interrupting the lookups in flight.
+type SwiftRepository struct {
+	path    string
+	current atomic.Pointer[handle]
+	mu      sync.Mutex
+}
+
+func NewSwiftRepository(path string) (*SwiftRepository, error) {
+	r := &SwiftRepository{path: path}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload opens the snapshot file again if it changed since it was opened.
+// The old snapshot is unmapped once the last lookup using it returns. New
+// snapshots must replace the file by renaming over it: writing to it in
+// place changes the mapped snapshot under the lookups.
+func (r *SwiftRepository) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	old := r.current.Load()
+	if old != nil && old.modTime.Equal(info.ModTime()) && old.size == info.Size() {
+		return nil
+	}
+
+	opened, err := snapshot.Open(r.path)
+	if err != nil {
+		return err
+	}
+	h := &handle{snapshot: opened, modTime: info.ModTime(), size: info.Size()}
+	h.refs.Store(1)
+
+	r.current.Store(h)
+	if old != nil {
+		old.release()
+	}
+	return nil
+}
+
+// Watch reloads the snapshot every interval until ctx is cancelled.
+func (r *SwiftRepository) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := r.Reload(); err != nil {
+				log.Printf("Failed to reload the snapshot, still serving the previous one: %v", err)
+			}
+		}
+	}()
+}
+
+// Close unmaps the snapshot once the lookups in flight return. The
+// repository must not be used afterwards.
+func (r *SwiftRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if h := r.current.Swap(nil); h != nil {
+		h.release()
+	}
+	return nil
+}
+
+// CreatedAt returns when the snapshot being served was taken.
+func (r *SwiftRepository) CreatedAt() time.Time {
+	h := r.acquire()
+	defer h.release()
+	return h.snapshot.CreatedAt()
+}
+
+// acquire takes a reference to the current snapshot, retrying if Reload
+// swapped it out in between.
+func (r *SwiftRepository) acquire() *handle {
+	for {
+		h := r.current.Load()
+		h.refs.Add(1)
+		if r.current.Load() == h {
+			return h
+		}
+		h.release()
+	}
+}
+
+func (r *SwiftRepository) FindByCode(_ context.Context, code string) (*models.SwiftCode, error) {
+	h := r.acquire()
+	defer h.release()
+
+	i, ok := h.snapshot.Find(code)
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	swiftCode := h.snapshot.Record(i)
+	return &swiftCode, nil
+}
+
+func (r *SwiftRepository) FindByCodes(_ context.Context, codes []string) ([]models.SwiftCode, error) {
+	h := r.acquire()
+	defer h.release()
+
+	var swiftCodes []models.SwiftCode
+	for _, code := range codes {
+		if i, ok := h.snapshot.Find(code); ok {
+			swiftCodes = append(swiftCodes, h.snapshot.Record(i))
+		}
+	}
+	return swiftCodes, nil
+}
+
+func (r *SwiftRepository) FindBranchesByPrefix(_ context.Context, prefix string) ([]models.SwiftCode, error) {
+	h := r.acquire()
+	defer h.release()
+
+	first, end := h.snapshot.Prefix(prefix)
+	var swiftCodes []models.SwiftCode
+	for i := first; i < end; i++ {
+		if swiftCode := h.snapshot.Record(i); !swiftCode.IsHeadquarter {
+			swiftCodes = append(swiftCodes, swiftCode)
+		}
+	}
+	return swiftCodes, nil
+}
+
+func (r *SwiftRepository) FindBranchesByPrefixes(_ context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	h := r.acquire()
+	defer h.release()
+
+	var swiftCodes []models.SwiftCode
+	for _, prefix := range prefixes {
+		first, end := h.snapshot.Prefix(prefix)
+		for i := first; i < end; i++ {
+			if swiftCode := h.snapshot.Record(i); !swiftCode.IsHeadquarter {
+				swiftCodes = append(swiftCodes, swiftCode)
+			}
+		}
+	}
+	return swiftCodes, nil
+}
+
+func (r *SwiftRepository) FindByCountryISO2(_ context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
+	h := r.acquire()
+	defer h.release()
+
+	first, end := h.snapshot.Country(countryISO2)
+	if first == end {
+		return nil, "", interfaces.ErrNotFound
+	}
+
+	swiftCodes := make([]models.SwiftCode, 0, end-first)
+	for i := first; i < end; i++ {
+		swiftCodes = append(swiftCodes, h.snapshot.Record(h.snapshot.Posting(i)))
+	}
+	return swiftCodes, swiftCodes[0].CountryName, nil
+}
+
+func (r *SwiftRepository) StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error {
+	h := r.acquire()
+	defer h.release()
+
+	first, end := 0, h.snapshot.Len()
+	record := func(i int) int { return i }
+	if countryISO2 != "" {
+		first, end = h.snapshot.Country(countryISO2)
+		record = h.snapshot.Posting
+	}
+
+	for i := first; i < end; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(h.snapshot.Record(record(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchSwiftCodes scans the mapped records, in code order, until it has
+// found limit matches.
+func (r *SwiftRepository) SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error) {
+	h := r.acquire()
+	defer h.release()
+
+	first, end := 0, h.snapshot.Len()
+	record := func(i int) int { return i }
+	if countryISO2 != "" {
+		first, end = h.snapshot.Country(countryISO2)
+		record = h.snapshot.Posting
+	}
+
+	bankName, codePrefix := strings.ToLower(query), strings.ToUpper(query)
+	var swiftCodes []models.SwiftCode
+	for i := first; i < end && len(swiftCodes) < limit; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		swiftCode := h.snapshot.Record(record(i))
+		if strings.HasPrefix(swiftCode.SwiftCode, codePrefix) || strings.Contains(strings.ToLower(swiftCode.BankName), bankName) {
+			swiftCodes = append(swiftCodes, swiftCode)
+		}
+	}
+	return swiftCodes, nil
+}
+
+func (r *SwiftRepository) AddSwiftCode(context.Context, models.SwiftCode) error {
+	return interfaces.ErrReadOnly
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(context.Context, models.SwiftCode, int64) error {
+	return interfaces.ErrReadOnly
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(context.Context, string, int64) error {
+	return interfaces.ErrReadOnly
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(context.Context, string) error {
+	return interfaces.ErrReadOnly
+}
+
+// PurgeDeletedSwiftCodes has nothing to purge: snapshots hold no deleted
+// records.
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(context.Context, time.Time) ([]models.SwiftCode, error) {
+	return nil, nil
+}
@@ -0,0 +1,125 @@
+// Package sqlite stores the SWIFT code directory, its versions, audit log and
+// change events in a single SQLite file, for deployments without MongoDB.
+package sqlite
+
+import (
+	"database/sql"
+	"net/url"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS swift_codes (
+		swift_code     TEXT PRIMARY KEY,
+		swift_prefix   TEXT NOT NULL,
+		is_headquarter INTEGER NOT NULL,
+		bank_name      TEXT NOT NULL,
+		address        TEXT NOT NULL,
+		country_iso2   TEXT NOT NULL,
+		country_name   TEXT NOT NULL,
+		deleted_at     INTEGER,
+		updated_at     INTEGER,
+		revision       INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX IF NOT EXISTS swift_codes_prefix ON swift_codes (swift_prefix, is_headquarter)`,
+	`CREATE INDEX IF NOT EXISTS swift_codes_country ON swift_codes (country_iso2)`,
+	`CREATE INDEX IF NOT EXISTS swift_codes_deleted_at ON swift_codes (deleted_at) WHERE deleted_at IS NOT NULL`,
+
+	`CREATE TABLE IF NOT EXISTS swift_code_versions (
+		swift_code     TEXT NOT NULL,
+		version        INTEGER NOT NULL,
+		valid_from     INTEGER NOT NULL,
+		valid_to       INTEGER,
+		deleted        INTEGER NOT NULL,
+		swift_prefix   TEXT NOT NULL,
+		is_headquarter INTEGER NOT NULL,
+		bank_name      TEXT NOT NULL,
+		address        TEXT NOT NULL,
+		country_iso2   TEXT NOT NULL,
+		country_name   TEXT NOT NULL,
+		deleted_at     INTEGER,
+		updated_at     INTEGER,
+		revision       INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (swift_code, version)
+	)`,
+	`CREATE INDEX IF NOT EXISTS swift_code_versions_prefix ON swift_code_versions (swift_prefix, valid_from)`,
+	`CREATE INDEX IF NOT EXISTS swift_code_versions_country ON swift_code_versions (country_iso2, valid_from)`,
+
+	`CREATE TABLE IF NOT EXISTS audit (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		swift_code TEXT NOT NULL,
+		operation  TEXT NOT NULL,
+		actor      TEXT NOT NULL,
+		request_id TEXT NOT NULL,
+		before     TEXT,
+		after      TEXT,
+		timestamp  INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS audit_swift_code ON audit (swift_code, timestamp)`,
+	`CREATE INDEX IF NOT EXISTS audit_timestamp ON audit (timestamp)`,
+
+	`CREATE TABLE IF NOT EXISTS events (
+		id           INTEGER PRIMARY KEY,
+		type         TEXT NOT NULL,
+		swift_code   TEXT NOT NULL,
+		country_iso2 TEXT NOT NULL,
+		record       TEXT,
+		timestamp    INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS counters (
+		name TEXT PRIMARY KEY,
+		seq  INTEGER NOT NULL
+	)`,
+}
+
+// Open opens the database file at path, creating it and its schema when
+// needed. The file is put in WAL mode so that reads do not wait for writes,
+// and transactions begin with BEGIN IMMEDIATE, taking the write lock before
+// their first read so that what they read cannot change before they commit.
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_txlock", "immediate")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range schema {
+		if _, err = db.Exec(statement); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Times are stored as Unix nanoseconds, which sort and compare correctly.
+
+func nanos(t time.Time) int64 {
+	return t.UnixNano()
+}
+
+func nullNanos(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+func fromNanos(n int64) time.Time {
+	return time.Unix(0, n).UTC()
+}
+
+func fromNullNanos(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+	t := fromNanos(n.Int64)
+	return &t
+}
@@ -0,0 +1,109 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"swift-codes-api/models"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// Records are kept as JSON in the audit log and the event log, the way they
+// are served.
+
+func marshalRecord(record *models.SwiftCode) (sql.NullString, error) {
+	if record == nil {
+		return sql.NullString{}, nil
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func unmarshalRecord(data sql.NullString) (*models.SwiftCode, error) {
+	if !data.Valid {
+		return nil, nil
+	}
+	var record models.SwiftCode
+	if err := json.Unmarshal([]byte(data.String), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *AuditRepository) Record(ctx context.Context, entry models.AuditEntry) error {
+	before, err := marshalRecord(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := marshalRecord(entry.After)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		"INSERT INTO audit (swift_code, operation, actor, request_id, before, after, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		entry.SwiftCode, entry.Operation, entry.Actor, entry.RequestID, before, after, nanos(entry.Timestamp),
+	)
+	return err
+}
+
+func (r *AuditRepository) Find(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	where, args := "1 = 1", []any{}
+	if filter.SwiftCode != "" {
+		where, args = where+" AND swift_code = ?", append(args, filter.SwiftCode)
+	}
+	if filter.Actor != "" {
+		where, args = where+" AND actor = ?", append(args, filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		where, args = where+" AND timestamp >= ?", append(args, nanos(filter.From))
+	}
+	if !filter.To.IsZero() {
+		where, args = where+" AND timestamp <= ?", append(args, nanos(filter.To))
+	}
+
+	query := "SELECT swift_code, operation, actor, request_id, before, after, timestamp FROM audit WHERE " + where +
+		" ORDER BY timestamp DESC, id DESC"
+	if filter.Limit > 0 {
+		query, args = query+" LIMIT ?", append(args, filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after sql.NullString
+		var timestamp int64
+		err = rows.Scan(&entry.SwiftCode, &entry.Operation, &entry.Actor, &entry.RequestID, &before, &after, &timestamp)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.Before, err = unmarshalRecord(before); err != nil {
+			return nil, err
+		}
+		if entry.After, err = unmarshalRecord(after); err != nil {
+			return nil, err
+		}
+		entry.Timestamp = fromNanos(timestamp)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"swift-codes-api/models"
+)
+
+const eventCounterID = "events"
+
+type EventRepository struct {
+	db *sql.DB
+}
+
+func NewEventRepository(db *sql.DB) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+// Append takes the next ID from the counter and inserts the event in one
+// transaction, which holds the write lock of the database from the counter
+// on, so events are committed in the order of their IDs. SQLite has no
+// change feed, so events never carry a SourceID here.
+func (r *EventRepository) Append(ctx context.Context, event models.Event) (models.Event, error) {
+	record, err := marshalRecord(event.Record)
+	if err != nil {
+		return event, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return event, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		"INSERT INTO counters (name, seq) VALUES (?, 1) ON CONFLICT (name) DO UPDATE SET seq = seq + 1 RETURNING seq",
+		eventCounterID,
+	).Scan(&event.ID)
+	if err != nil {
+		return event, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO events (id, type, swift_code, country_iso2, record, timestamp) VALUES (?, ?, ?, ?, ?, ?)",
+		event.ID, event.Type, event.SwiftCode, event.CountryISO2, record, nanos(event.Timestamp),
+	)
+	if err != nil {
+		return event, err
+	}
+	return event, tx.Commit()
+}
+
+func (r *EventRepository) LatestID(ctx context.Context) (int64, error) {
+	var id int64
+	err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM events").Scan(&id)
+	return id, err
+}
+
+func (r *EventRepository) FindAfter(ctx context.Context, afterID int64, countryISO2 string, limit int64) ([]models.Event, error) {
+	where, args := "id > ?", []any{afterID}
+	if countryISO2 != "" {
+		where, args = where+" AND country_iso2 = ?", append(args, countryISO2)
+	}
+
+	if limit <= 0 {
+		// A negative limit is no limit to SQLite.
+		limit = -1
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, type, swift_code, country_iso2, record, timestamp FROM events WHERE "+where+" ORDER BY id LIMIT ?",
+		append(args, limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.Event
+	for rows.Next() {
+		var event models.Event
+		var record sql.NullString
+		var timestamp int64
+		if err = rows.Scan(&event.ID, &event.Type, &event.SwiftCode, &event.CountryISO2, &record, &timestamp); err != nil {
+			return nil, err
+		}
+
+		if event.Record, err = unmarshalRecord(record); err != nil {
+			return nil, err
+		}
+		event.Timestamp = fromNanos(timestamp)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
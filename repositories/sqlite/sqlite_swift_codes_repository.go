@@ -0,0 +1,331 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+const swiftCodeColumns = "swift_code, swift_prefix, is_headquarter, bank_name, address, country_iso2, country_name, deleted_at, updated_at, revision"
+
+type SwiftRepository struct {
+	db *sql.DB
+}
+
+func NewSwiftRepository(db *sql.DB) *SwiftRepository {
+	return &SwiftRepository{
+		db: db,
+	}
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSwiftCode(row scanner, extra ...any) (models.SwiftCode, error) {
+	var swiftCode models.SwiftCode
+	var deletedAt, updatedAt sql.NullInt64
+	dest := append([]any{
+		&swiftCode.SwiftCode, &swiftCode.SwiftPrefix, &swiftCode.IsHeadquarter, &swiftCode.BankName,
+		&swiftCode.Address, &swiftCode.CountryISO2, &swiftCode.CountryName, &deletedAt, &updatedAt, &swiftCode.Revision,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return models.SwiftCode{}, err
+	}
+
+	swiftCode.DeletedAt = fromNullNanos(deletedAt)
+	if updatedAt.Valid {
+		swiftCode.UpdatedAt = fromNanos(updatedAt.Int64)
+	}
+	return swiftCode, nil
+}
+
+func collect(rows *sql.Rows) ([]models.SwiftCode, error) {
+	defer rows.Close()
+
+	var swiftCodes []models.SwiftCode
+	for rows.Next() {
+		swiftCode, err := scanSwiftCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		swiftCodes = append(swiftCodes, swiftCode)
+	}
+	return swiftCodes, rows.Err()
+}
+
+// live restricts a query to records that have not been soft-deleted, unless
+// the caller asked for deleted records as well.
+func live(ctx context.Context, where string) string {
+	if !reqctx.IncludeDeleted(ctx) {
+		where += " AND deleted_at IS NULL"
+	}
+	return where
+}
+
+// atRevision restricts a mutation to a record still at the expected revision.
+func atRevision(where string, args []any, expectedRevision int64) (string, []any) {
+	if expectedRevision == interfaces.AnyRevision {
+		return where, args
+	}
+	return where + " AND revision = ?", append(args, expectedRevision)
+}
+
+func (r *SwiftRepository) FindByCode(ctx context.Context, code string) (*models.SwiftCode, error) {
+	row := r.db.QueryRowContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, "swift_code = ?"),
+		code,
+	)
+	swiftCode, err := scanSwiftCode(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, interfaces.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &swiftCode, nil
+}
+
+func (r *SwiftRepository) FindByCodes(ctx context.Context, codes []string) ([]models.SwiftCode, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(codes)), ", ")
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, "swift_code IN ("+placeholders+")"),
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *SwiftRepository) FindBranchesByPrefix(ctx context.Context, prefix string) ([]models.SwiftCode, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, "swift_prefix = ? AND is_headquarter = 0")+" ORDER BY swift_code",
+		prefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *SwiftRepository) FindBranchesByPrefixes(ctx context.Context, prefixes []string) ([]models.SwiftCode, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+
+	args := make([]any, len(prefixes))
+	for i, prefix := range prefixes {
+		args[i] = prefix
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(prefixes)), ", ")
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, "swift_prefix IN ("+placeholders+") AND is_headquarter = 0")+" ORDER BY swift_code",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+func (r *SwiftRepository) FindByCountryISO2(ctx context.Context, countryISO2 string) ([]models.SwiftCode, string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, "country_iso2 = ?")+" ORDER BY swift_code",
+		countryISO2,
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	swiftCodes, err := collect(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(swiftCodes) == 0 {
+		return nil, "", interfaces.ErrNotFound
+	}
+
+	return swiftCodes, swiftCodes[0].CountryName, nil
+}
+
+func (r *SwiftRepository) StreamSwiftCodes(ctx context.Context, countryISO2 string, fn func(models.SwiftCode) error) error {
+	where, args := "1 = 1", []any{}
+	if countryISO2 != "" {
+		where, args = "country_iso2 = ?", append(args, countryISO2)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, where)+" ORDER BY swift_code",
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		swiftCode, err := scanSwiftCode(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(swiftCode); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, for ESCAPE '\'.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchSwiftCodes relies on LIKE, which ignores the case of ASCII letters
+// only.
+func (r *SwiftRepository) SearchSwiftCodes(ctx context.Context, query, countryISO2 string, limit int) ([]models.SwiftCode, error) {
+	pattern := likeEscaper.Replace(query)
+	where, args := `(swift_code LIKE ? ESCAPE '\' OR bank_name LIKE ? ESCAPE '\')`, []any{pattern + "%", "%" + pattern + "%"}
+	if countryISO2 != "" {
+		where, args = where+" AND country_iso2 = ?", append(args, countryISO2)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+" FROM swift_codes WHERE "+live(ctx, where)+" ORDER BY swift_code LIMIT ?",
+		append(args, limit)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
+
+// AddSwiftCode inserts a record, or revives a soft-deleted one at its next
+// revision, in a single statement.
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO swift_codes (`+swiftCodeColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, NULL, ?, 1)
+		ON CONFLICT (swift_code) DO UPDATE SET
+			swift_prefix = excluded.swift_prefix,
+			is_headquarter = excluded.is_headquarter,
+			bank_name = excluded.bank_name,
+			address = excluded.address,
+			country_iso2 = excluded.country_iso2,
+			country_name = excluded.country_name,
+			deleted_at = NULL,
+			updated_at = excluded.updated_at,
+			revision = swift_codes.revision + 1
+		WHERE swift_codes.deleted_at IS NOT NULL`,
+		swiftCode.SwiftCode, swiftCode.SwiftCode[:8], swiftCode.IsHeadquarter, swiftCode.BankName,
+		swiftCode.Address, swiftCode.CountryISO2, swiftCode.CountryName, nanos(time.Now().UTC()),
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return fmt.Errorf("SWIFT code %s %w", swiftCode.SwiftCode, interfaces.ErrAlreadyExists)
+	}
+
+	return nil
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	where, args := atRevision("swift_code = ? AND deleted_at IS NULL", []any{swiftCode.SwiftCode}, expectedRevision)
+	args = append([]any{
+		swiftCode.IsHeadquarter, swiftCode.BankName, swiftCode.Address,
+		swiftCode.CountryISO2, swiftCode.CountryName, nanos(time.Now().UTC()),
+	}, args...)
+
+	return r.mutate(ctx, swiftCode.SwiftCode,
+		`UPDATE swift_codes SET is_headquarter = ?, bank_name = ?, address = ?, country_iso2 = ?, country_name = ?,
+			updated_at = ?, revision = revision + 1
+		WHERE `+where,
+		args...,
+	)
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	now := nanos(time.Now().UTC())
+	where, args := atRevision("swift_code = ? AND deleted_at IS NULL", []any{now, now, code}, expectedRevision)
+
+	return r.mutate(ctx, code,
+		"UPDATE swift_codes SET deleted_at = ?, updated_at = ?, revision = revision + 1 WHERE "+where,
+		args...,
+	)
+}
+
+// mutate runs a conditional mutation of a live record and explains why it
+// matched nothing: either the record is gone or it has moved past the
+// expected revision.
+func (r *SwiftRepository) mutate(ctx context.Context, code, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	var exists bool
+	err = r.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM swift_codes WHERE swift_code = ? AND deleted_at IS NULL)",
+		code,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return interfaces.ErrRevisionMismatch
+	}
+	return interfaces.ErrNotFound
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE swift_codes SET deleted_at = NULL, updated_at = ?, revision = revision + 1
+		WHERE swift_code = ? AND deleted_at IS NOT NULL`,
+		nanos(time.Now().UTC()), code,
+	)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return interfaces.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *SwiftRepository) PurgeDeletedSwiftCodes(ctx context.Context, deletedBefore time.Time) ([]models.SwiftCode, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"DELETE FROM swift_codes WHERE deleted_at < ? RETURNING "+swiftCodeColumns,
+		nanos(deletedBefore),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collect(rows)
+}
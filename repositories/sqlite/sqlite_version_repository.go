@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+type VersionRepository struct {
+	db *sql.DB
+}
+
+func NewVersionRepository(db *sql.DB) *VersionRepository {
+	return &VersionRepository{
+		db: db,
+	}
+}
+
+// SaveVersion closes the open version of the code and appends the next one
+// in one transaction, so that concurrent saves neither leave two versions
+// open nor number two versions alike.
+func (r *VersionRepository) SaveVersion(ctx context.Context, record models.SwiftCode, deleted bool, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE swift_code_versions SET valid_to = ? WHERE swift_code = ? AND valid_to IS NULL",
+		nanos(at), record.SwiftCode,
+	)
+	if err != nil {
+		return err
+	}
+
+	var updatedAt sql.NullInt64
+	if !record.UpdatedAt.IsZero() {
+		updatedAt = sql.NullInt64{Int64: nanos(record.UpdatedAt), Valid: true}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO swift_code_versions (`+swiftCodeColumns+`, version, valid_from, deleted)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, COALESCE(MAX(version), 0) + 1, ?, ?
+		FROM swift_code_versions WHERE swift_code = ?`,
+		record.SwiftCode, record.SwiftPrefix, record.IsHeadquarter, record.BankName, record.Address,
+		record.CountryISO2, record.CountryName, nullNanos(record.DeletedAt), updatedAt, record.Revision,
+		nanos(at), deleted, record.SwiftCode,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *VersionRepository) SaveInitialVersion(ctx context.Context, record models.SwiftCode, validFrom time.Time) error {
+	var updatedAt sql.NullInt64
+	if !record.UpdatedAt.IsZero() {
+		updatedAt = sql.NullInt64{Int64: nanos(record.UpdatedAt), Valid: true}
+	}
+
+	// The first version of a code is always version 1, which the primary key
+	// keeps from being saved twice.
+	_, err := r.db.ExecContext(ctx,
+		`INSERT OR IGNORE INTO swift_code_versions (`+swiftCodeColumns+`, version, valid_from, valid_to, deleted)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, 1, ?, ?, 0)`,
+		record.SwiftCode, record.SwiftPrefix, record.IsHeadquarter, record.BankName, record.Address,
+		record.CountryISO2, record.CountryName, nullNanos(record.DeletedAt), updatedAt, record.Revision,
+		nanos(validFrom), nullNanos(record.DeletedAt),
+	)
+	return err
+}
+
+func scanVersion(row scanner) (models.SwiftCodeVersion, error) {
+	var version models.SwiftCodeVersion
+	var validFrom int64
+	var validTo sql.NullInt64
+	record, err := scanSwiftCode(row, &version.Version, &validFrom, &validTo, &version.Deleted)
+	if err != nil {
+		return models.SwiftCodeVersion{}, err
+	}
+
+	version.SwiftCode = record.SwiftCode
+	version.Record = record
+	version.ValidFrom = fromNanos(validFrom)
+	version.ValidTo = fromNullNanos(validTo)
+	return version, nil
+}
+
+func (r *VersionRepository) findVersions(ctx context.Context, where string, args ...any) ([]models.SwiftCodeVersion, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+swiftCodeColumns+", version, valid_from, valid_to, deleted FROM swift_code_versions WHERE "+where,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.SwiftCodeVersion
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+func (r *VersionRepository) FindHistory(ctx context.Context, code string) ([]models.SwiftCodeVersion, error) {
+	versions, err := r.findVersions(ctx, "swift_code = ? ORDER BY version", code)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(versions) == 0 {
+		return nil, interfaces.ErrNotFound
+	}
+
+	return versions, nil
+}
+
+// findRecordsAsOf returns the records of the versions that were current at
+// asOf and not deleted.
+func (r *VersionRepository) findRecordsAsOf(ctx context.Context, where string, asOf time.Time, args ...any) ([]models.SwiftCode, error) {
+	at := nanos(asOf)
+	versions, err := r.findVersions(ctx,
+		where+" AND deleted = 0 AND valid_from <= ? AND (valid_to IS NULL OR valid_to > ?) ORDER BY swift_code",
+		append(args, at, at)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.SwiftCode, len(versions))
+	for i, version := range versions {
+		records[i] = version.Record
+		records[i].DeletedAt = nil
+	}
+	return records, nil
+}
+
+func (r *VersionRepository) FindByCodeAsOf(ctx context.Context, code string, asOf time.Time) (*models.SwiftCode, error) {
+	records, err := r.findRecordsAsOf(ctx, "swift_code = ?", asOf, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, interfaces.ErrNotFound
+	}
+
+	return &records[0], nil
+}
+
+func (r *VersionRepository) FindBranchesByPrefixAsOf(ctx context.Context, prefix string, asOf time.Time) ([]models.SwiftCode, error) {
+	return r.findRecordsAsOf(ctx, "swift_prefix = ? AND is_headquarter = 0", asOf, prefix)
+}
+
+func (r *VersionRepository) FindByCountryISO2AsOf(ctx context.Context, countryISO2 string, asOf time.Time) ([]models.SwiftCode, string, error) {
+	records, err := r.findRecordsAsOf(ctx, "country_iso2 = ?", asOf, countryISO2)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(records) == 0 {
+		return nil, "", interfaces.ErrNotFound
+	}
+
+	return records, records[0].CountryName, nil
+}
@@ -0,0 +1,113 @@
+package versioned
+
+import (
+	"context"
+	"fmt"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+)
+
+// SwiftRepository saves a new version of a SWIFT code every time a mutation
+// changes its state, so that past states can be queried later.
+type SwiftRepository struct {
+	interfaces.SwiftRepository
+	versions interfaces.VersionRepository
+}
+
+func NewSwiftRepository(repo interfaces.SwiftRepository, versions interfaces.VersionRepository) *SwiftRepository {
+	return &SwiftRepository{
+		SwiftRepository: repo,
+		versions:        versions,
+	}
+}
+
+// Backfill saves a first version of every record, deleted ones included,
+// whose code has none yet, so that lookups at a past point in time find the
+// records stored before versions were kept. Such a record is valid from its
+// last update, or from the epoch when it was never updated.
+func (r *SwiftRepository) Backfill(ctx context.Context) error {
+	return r.SwiftRepository.StreamSwiftCodes(reqctx.WithIncludeDeleted(ctx), "", func(record models.SwiftCode) error {
+		return r.versions.SaveInitialVersion(ctx, record, initialValidFrom(record))
+	})
+}
+
+func (r *SwiftRepository) AddSwiftCode(ctx context.Context, swiftCode models.SwiftCode) error {
+	before := r.findBefore(ctx, swiftCode.SwiftCode)
+	if err := r.SwiftRepository.AddSwiftCode(ctx, swiftCode); err != nil {
+		return err
+	}
+
+	return r.saveCurrent(ctx, swiftCode.SwiftCode, false, before)
+}
+
+func (r *SwiftRepository) UpdateSwiftCode(ctx context.Context, swiftCode models.SwiftCode, expectedRevision int64) error {
+	before := r.findBefore(ctx, swiftCode.SwiftCode)
+	if err := r.SwiftRepository.UpdateSwiftCode(ctx, swiftCode, expectedRevision); err != nil {
+		return err
+	}
+
+	return r.saveCurrent(ctx, swiftCode.SwiftCode, false, before)
+}
+
+func (r *SwiftRepository) DeleteSwiftCode(ctx context.Context, code string, expectedRevision int64) error {
+	before := r.findBefore(ctx, code)
+	if err := r.SwiftRepository.DeleteSwiftCode(ctx, code, expectedRevision); err != nil {
+		return err
+	}
+
+	return r.saveCurrent(reqctx.WithIncludeDeleted(ctx), code, true, before)
+}
+
+func (r *SwiftRepository) RestoreSwiftCode(ctx context.Context, code string) error {
+	before := r.findBefore(ctx, code)
+	if err := r.SwiftRepository.RestoreSwiftCode(ctx, code); err != nil {
+		return err
+	}
+
+	return r.saveCurrent(ctx, code, false, before)
+}
+
+// findBefore returns the record a mutation is about to change, deleted or
+// not, or nil when there is none.
+func (r *SwiftRepository) findBefore(ctx context.Context, code string) *models.SwiftCode {
+	before, err := r.SwiftRepository.FindByCode(reqctx.WithIncludeDeleted(ctx), code)
+	if err != nil {
+		return nil
+	}
+	return before
+}
+
+// saveCurrent saves the state a mutation left the code in as a version valid
+// from the record's last update. The state before the mutation is saved as
+// the first version of codes that have none yet, as Backfill may not have
+// reached them.
+func (r *SwiftRepository) saveCurrent(ctx context.Context, code string, deleted bool, before *models.SwiftCode) error {
+	if before != nil {
+		if err := r.versions.SaveInitialVersion(ctx, *before, initialValidFrom(*before)); err != nil {
+			return fmt.Errorf("failed to save first version of %s: %w", code, err)
+		}
+	}
+
+	record, err := r.SwiftRepository.FindByCode(ctx, code)
+	if err == nil {
+		validFrom := record.UpdatedAt
+		if validFrom.IsZero() {
+			validFrom = time.Now()
+		}
+		err = r.versions.SaveVersion(ctx, *record, deleted, validFrom.UTC())
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to save version of %s: %w", code, err)
+	}
+	return nil
+}
+
+func initialValidFrom(record models.SwiftCode) time.Time {
+	if record.UpdatedAt.IsZero() {
+		return time.Unix(0, 0).UTC()
+	}
+	return record.UpdatedAt
+}
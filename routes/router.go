@@ -2,20 +2,114 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/mongo"
 	"swift-codes-api/handlers"
 	"swift-codes-api/internal/config"
-	repos "swift-codes-api/repositories/mongo"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/middleware"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
 )
 
-func SetupRoutes(r *gin.Engine, db *mongo.Database, cfg config.Config) {
-	h := handlers.NewSwiftHandler(cfg, repos.NewSwiftRepository(db))
+// Dependencies of the routes. Only SwiftRepo is required: the features of the
+// others are left out when they are nil.
+type Dependencies struct {
+	SwiftRepo   interfaces.SwiftRepository
+	AuditRepo   interfaces.AuditRepository
+	VersionRepo interfaces.VersionRepository
+	Broker      *events.Broker
+	WebhookRepo interfaces.WebhookRepository
+	// RateLimitStore holds the rate limit buckets, in memory when nil.
+	RateLimitStore ratelimit.Store
+	ImportRunner   *importer.Runner
+	// Middleware runs on every request after authentication and rate
+	// limiting.
+	Middleware []gin.HandlerFunc
+}
+
+// SetupRoutes serves the API from r, answering unknown routes with a
+// problem.
+func SetupRoutes(r *gin.Engine, deps Dependencies, cfg config.Config) {
+	Mount(&r.RouterGroup, deps, cfg)
+	r.NoRoute(func(c *gin.Context) {
+		problems.Respond(c, problems.RouteNotFound, "No route matches "+c.Request.Method+" "+c.Request.URL.Path)
+	})
+}
+
+// Mount registers the routes of the API on r. Its middleware only runs for
+// those routes, leaving the others of the engine as they are.
+func Mount(r *gin.RouterGroup, deps Dependencies, cfg config.Config) {
+	h := handlers.NewSwiftHandler(cfg, deps.SwiftRepo, handlers.WithVersions(deps.VersionRepo))
+	gh := handlers.NewGraphQLHandler(deps.SwiftRepo)
+
+	rateLimitStore := deps.RateLimitStore
+	if rateLimitStore == nil {
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+
+	r.Use(middleware.RequestID(), middleware.StaleWarning(), middleware.Authenticate(cfg))
+
+	// Routes that only read, whatever their method, draw on the read budget.
+	reads := r.Group("", middleware.RateLimit(cfg, rateLimitStore, middleware.ReadScope))
+	reads.Use(deps.Middleware...)
+	writes := r.Group("", middleware.RateLimit(cfg, rateLimitStore, middleware.WriteScope))
+	writes.Use(deps.Middleware...)
+
+	v1 := reads.Group("/v1/swift-codes")
+	{
+		v1.GET("/:swift-code", middleware.ConditionalGET(cfg.CacheControlLookup), h.GetSwiftCode)
+		v1.GET("/country/:countryISO2code", middleware.ConditionalGET(cfg.CacheControlCountry), h.GetSwiftCodesByCountry)
+		v1.POST("/lookup", h.LookupSwiftCodes)
+		v1.GET("/:swift-code/history", middleware.ConditionalGET(cfg.CacheControlHistory), h.GetSwiftCodeHistory)
+	}
+
+	v1Writes := writes.Group("/v1/swift-codes")
+	{
+		v1Writes.POST("", h.AddSwiftCode)
+		v1Writes.PUT("/:swift-code", h.UpdateSwiftCode)
+		v1Writes.DELETE("/:swift-code", h.DeleteSwiftCode)
+		v1Writes.POST("/:swift-code/restore", h.RestoreSwiftCode)
+	}
 
-	v1 := r.Group("/v1/swift-codes")
+	v2 := reads.Group("/v2/swift-codes")
 	{
-		v1.GET("/:swift-code", h.GetSwiftCode)
-		v1.GET("/country/:countryISO2code", h.GetSwiftCodesByCountry)
-		v1.POST("", h.AddSwiftCode)
-		v1.DELETE("/:swift-code", h.DeleteSwiftCode)
+		v2.GET("/:swift-code", middleware.ConditionalGET(cfg.CacheControlLookup), h.GetSwiftCodeV2)
+		v2.GET("/country/:countryISO2code", middleware.ConditionalGET(cfg.CacheControlCountry), h.GetSwiftCodesByCountryV2)
+	}
+
+	reads.GET("/v1/export", h.ExportSwiftCodes)
+	reads.POST("/v1/validate", h.ValidateSwiftCode)
+	reads.POST("/v1/screenings", h.ScreenBeneficiaries)
+	reads.POST("/graphql", gh.Query)
+	reads.GET("/debug/vars", handlers.GetDebugVars)
+	reads.GET("/openapi.json", handlers.GetOpenAPISpec)
+	if cfg.SwaggerUI {
+		reads.GET("/docs", handlers.GetSwaggerUI)
+	}
+
+	if deps.AuditRepo != nil {
+		ah := handlers.NewAuditHandler(deps.AuditRepo)
+		reads.GET("/v1/audit", ah.GetAuditEntries)
+	}
+
+	if deps.Broker != nil {
+		eh := handlers.NewEventsHandler(deps.Broker)
+		reads.GET("/v1/events", eh.StreamEvents)
+	}
+
+	if deps.WebhookRepo != nil {
+		wh := handlers.NewWebhooksHandler(deps.WebhookRepo)
+		reads.GET("/v1/webhooks", wh.GetWebhooks)
+		reads.GET("/v1/webhooks/:id/deliveries", wh.GetWebhookDeliveries)
+		writes.POST("/v1/webhooks", wh.CreateWebhook)
+		writes.DELETE("/v1/webhooks/:id", wh.DeleteWebhook)
+	}
+
+	if deps.ImportRunner != nil {
+		ih := handlers.NewImportsHandler(cfg, deps.ImportRunner)
+		reads.GET("/v1/imports/:id", ih.GetImport)
+		writes.POST("/v1/imports", ih.CreateImport)
+		writes.POST("/v1/imports/:id/cancel", ih.CancelImport)
 	}
 }
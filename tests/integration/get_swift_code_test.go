@@ -11,6 +11,7 @@ import (
 	"swift-codes-api/internal/app"
 	"swift-codes-api/internal/config"
 	"swift-codes-api/tests/integration/test_cases"
+	"swift-codes-api/tests/spec"
 	"testing"
 	"time"
 )
@@ -18,12 +19,15 @@ import (
 func TestGetSwiftCode_Integration(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	cfg := config.Load()
-	testApp := app.New(cfg)
+	testApp, err := app.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	pingErr := testApp.MongoDB.Client().Ping(ctx, nil)
+	pingErr := testApp.Storage.MongoDB.Client().Ping(ctx, nil)
 	log.Printf("MongoDB connection test: %v", pingErr)
 
 	for _, tc := range test_cases.GetIntegrationTestCases() {
@@ -31,37 +35,39 @@ func TestGetSwiftCode_Integration(t *testing.T) {
 			testCtx, testCancel := context.WithTimeout(ctx, 5*time.Second)
 			defer testCancel()
 
-			if err := testApp.MongoDB.Collection("swift-codes").Drop(testCtx); err != nil {
+			if err := testApp.Storage.MongoDB.Collection("swift-codes").Drop(testCtx); err != nil {
 				t.Logf("Warning: Failed to drop collection: %v", err)
 			}
 
-			tc.SetupData(testCtx, testApp.MongoDB)
+			tc.SetupData(testCtx, testApp.Storage.MongoDB)
 
-			documents, err := testApp.MongoDB.Collection("swift-codes").CountDocuments(testCtx, bson.M{})
+			documents, err := testApp.Storage.MongoDB.Collection("swift-codes").CountDocuments(testCtx, bson.M{})
 			if err != nil {
 				t.Logf("Warning: Failed to count documents: %v", err)
 			}
 			log.Println(documents)
 
-			response := performRequest(testApp.Router, tc.SwiftCode)
+			response := performRequest(t, testApp.Router, tc.SwiftCode)
 
 			assert.Equal(t, tc.ExpectedStatusCode, response.Code)
 			assert.JSONEq(t, tc.ExpectedResponse, response.Body.String())
 
-			if err := testApp.MongoDB.Collection("swift-codes").Drop(testCtx); err != nil {
+			if err := testApp.Storage.MongoDB.Collection("swift-codes").Drop(testCtx); err != nil {
 				t.Logf("Warning: Failed to drop collection: %v", err)
 			}
 		})
 	}
 
-	if err := testApp.Mongo.Disconnect(ctx); err != nil {
+	if err := testApp.Close(ctx); err != nil {
 		t.Logf("Warning: Failed to disconnect MongoDB client: %v", err)
 	}
 }
 
-func performRequest(router *gin.Engine, swiftCode string) *httptest.ResponseRecorder {
+func performRequest(t *testing.T, router *gin.Engine, swiftCode string) *httptest.ResponseRecorder {
 	req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/"+swiftCode, nil)
+	req.Header.Set("X-Request-ID", "integration-test")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	spec.ValidateResponse(t, req, w)
 	return w
 }
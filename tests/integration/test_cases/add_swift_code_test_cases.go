@@ -102,7 +102,7 @@ func GetAddSwiftCodeIntegrationTestCases() []AddSwiftCodeIntegrationTestCase {
 				return result.BankName == "Existing Bank France"
 			},
 			ExpectedStatusCode: http.StatusConflict,
-			ExpectedResponse:   `{"message":"SWIFT code DUPEFR33XXX already exists"}`,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:swift-code-exists","title":"SWIFT code already exists","status":409,"detail":"SWIFT code DUPEFR33XXX already exists","instance":"/v1/swift-codes","code":"swift-code-exists","requestId":"integration-test"}`,
 		},
 	}
 }
@@ -46,13 +46,14 @@ func GetDeleteSwiftCodeIntegrationTestCases() []DeleteSwiftCodeIntegrationTestCa
 				log.Printf("Documents before test: %d", count)
 			},
 			CheckData: func(ctx context.Context, db *mongo.Database) bool {
-				count, err := db.Collection("swift-codes").CountDocuments(ctx, bson.M{"swiftCode": "BOTKUS33XXX"})
+				var result models.SwiftCode
+				err := db.Collection("swift-codes").FindOne(ctx, bson.M{"swiftCode": "BOTKUS33XXX"}).Decode(&result)
 				if err != nil {
 					log.Printf("Error checking data: %v", err)
 					return false
 				}
-				log.Printf("Documents after deletion: %d", count)
-				return count == 0
+				log.Printf("Deleted at: %v", result.DeletedAt)
+				return result.DeletedAt != nil
 			},
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   `{"message":"SWIFT code deleted successfully"}`,
@@ -92,7 +93,7 @@ func GetDeleteSwiftCodeIntegrationTestCases() []DeleteSwiftCodeIntegrationTestCa
 				return count == 1
 			},
 			ExpectedStatusCode: http.StatusNotFound,
-			ExpectedResponse:   `{"message":"SWIFT code not found"}`,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code NOTFND33XXX exists","instance":"/v1/swift-codes/NOTFND33XXX","code":"swift-code-not-found","requestId":"integration-test"}`,
 		},
 	}
 }
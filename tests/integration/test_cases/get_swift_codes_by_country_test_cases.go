@@ -90,7 +90,7 @@ func GetSwiftCodesByCountryIntegrationTestCases() []CountryIntegrationTestCase {
 				}
 			},
 			ExpectedStatusCode: http.StatusNotFound,
-			ExpectedResponse:   `{"message":"No SWIFT codes found for this country"}`,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:country-not-found","title":"Country not found","status":404,"detail":"No SWIFT codes found for country ZZ","instance":"/v1/swift-codes/country/ZZ","code":"country-not-found","requestId":"integration-test"}`,
 		},
 	}
 }
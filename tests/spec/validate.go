@@ -0,0 +1,117 @@
+package spec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/api"
+	"sync"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+var (
+	loadOnce sync.Once
+	doc      *openapi3.T
+	router   routers.Router
+	loadErr  error
+)
+
+func load() (routers.Router, error) {
+	loadOnce.Do(func() {
+		openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+		openapi3filter.RegisterBodyDecoder("application/xml", openapi3filter.FileBodyDecoder)
+		openapi3filter.RegisterBodyDecoder("text/event-stream", openapi3filter.FileBodyDecoder)
+
+		var err error
+		doc, err = openapi3.NewLoader().LoadFromData(api.Spec)
+		if err != nil {
+			loadErr = err
+			return
+		}
+		if err = doc.Validate(context.Background()); err != nil {
+			loadErr = err
+			return
+		}
+		router, loadErr = gorillamux.NewRouter(doc)
+	})
+	return router, loadErr
+}
+
+// ValidateResponse fails the test when the response to req is not described
+// by the OpenAPI document served by the API, so that handlers and the
+// document cannot drift apart.
+func ValidateResponse(t *testing.T, req *http.Request, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	router, err := load()
+	if err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+
+	// Request bodies have been consumed by the handler and are not checked.
+	req = req.Clone(context.Background())
+	req.Body = http.NoBody
+	req.Host = ""
+	req.URL.Host = ""
+	req.URL.Scheme = ""
+
+	route, pathParams, err := router.FindRoute(req)
+	if err != nil {
+		t.Errorf("%s %s is not described by the OpenAPI document: %v", req.Method, req.URL.Path, err)
+		return
+	}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: w.Code,
+		Header: w.Header(),
+		Body:   io.NopCloser(bytes.NewReader(w.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	}
+	if w.Body.Len() == 0 && strings.TrimSpace(w.Header().Get("Content-Type")) == "" {
+		input.Options.ExcludeResponseBody = true
+	}
+
+	if err := openapi3filter.ValidateResponse(context.Background(), input); err != nil {
+		t.Errorf("response to %s %s (%d) does not match the OpenAPI document: %v", req.Method, req.URL.Path, w.Code, err)
+	}
+}
+
+// ValidateProblem fails the test unless w is a problem document as the
+// OpenAPI document describes them, for responses to routes it cannot
+// describe, such as unknown ones.
+func ValidateProblem(t *testing.T, w *httptest.ResponseRecorder) {
+	t.Helper()
+
+	if _, err := load(); err != nil {
+		t.Fatalf("invalid OpenAPI document: %v", err)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Errorf("problem sent as %q", contentType)
+	}
+	var body any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Errorf("problem is not JSON: %v", err)
+		return
+	}
+	if err := doc.Components.Schemas["Problem"].Value.VisitJSON(body); err != nil {
+		t.Errorf("problem does not match the OpenAPI document: %v", err)
+	}
+}
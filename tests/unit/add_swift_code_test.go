@@ -9,6 +9,7 @@ import (
 	"swift-codes-api/handlers"
 	"swift-codes-api/internal/config"
 	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
 	"swift-codes-api/tests/unit/test_cases"
 	"testing"
 )
@@ -23,12 +24,13 @@ func TestAddSwiftCode(t *testing.T) {
 
 			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
 			router := gin.Default()
-			router.POST("/swift-codes", handler.AddSwiftCode)
+			router.POST("/v1/swift-codes", handler.AddSwiftCode)
 
-			req := httptest.NewRequest(http.MethodPost, "/swift-codes", bytes.NewBufferString(tc.RequestBody))
+			req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", bytes.NewBufferString(tc.RequestBody))
 			req.Header.Set("Content-Type", "application/json")
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
 
 			assert.Equal(t, tc.ExpectedStatus, w.Code)
 			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
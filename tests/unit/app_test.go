@@ -0,0 +1,240 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"swift-codes-api/internal/app"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"testing"
+	"time"
+)
+
+func TestNewApp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	t.Run("Options replace the storage, router, middleware and logger", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(&models.SwiftCode{
+			SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY",
+		}, nil).Once()
+
+		var logs bytes.Buffer
+		router := gin.New()
+		application, err := app.New(config.Config{},
+			app.WithStorage(func(context.Context, config.Config) (*app.Storage, error) {
+				return &app.Storage{}, nil
+			}),
+			app.WithSwiftRepository(repo),
+			app.WithRouter(router),
+			app.WithMiddleware(func(c *gin.Context) {
+				c.Header("X-Embedded", "true")
+			}),
+			app.WithLogger(log.New(&logs, "", 0)),
+		)
+		require.NoError(t, err)
+		assert.Same(t, router, application.Router)
+		router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		assert.Nil(t, application.Dispatcher)
+		assert.Nil(t, application.Importer)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/DEUTDEFF500", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get("X-Embedded"))
+		repo.AssertExpectations(t)
+
+		// Features without a repository are left out, and with them their
+		// routes: the router answers, so the responses are not the API's.
+		for _, path := range []string{"/v1/audit", "/v1/events", "/v1/webhooks"} {
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+		}
+
+		// The other routes of the router are left alone.
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("X-Embedded"))
+		assert.Empty(t, w.Header().Get("X-Request-ID"))
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NotContains(t, w.Body.String(), "route-not-found")
+
+		assert.NoError(t, application.Close(ctx))
+	})
+
+	t.Run("A repository alone opens no storage backend", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(&models.SwiftCode{
+			SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY",
+		}, nil).Once()
+
+		// The backend configured does not exist, so opening it would fail.
+		application, err := app.New(config.Config{Storage: "unknown"}, app.WithSwiftRepository(repo))
+		require.NoError(t, err)
+		assert.Nil(t, application.Dispatcher)
+		assert.Nil(t, application.Importer)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/DEUTDEFF500", nil)
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+
+		req = httptest.NewRequest(http.MethodGet, "/v1/audit", nil)
+		w = httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"route-not-found"`)
+		assert.NoError(t, application.Close(ctx))
+	})
+
+	t.Run("The storage backend is selected by configuration", func(t *testing.T) {
+		application, err := app.New(config.Config{
+			Storage:    "sqlite",
+			SQLitePath: filepath.Join(t.TempDir(), "swift-codes.db"),
+			APIKeys:    map[string]config.APIKey{"admin-key": {Actor: "alice", Admin: true}},
+		})
+		require.NoError(t, err)
+		t.Cleanup(func() { application.Close(ctx) })
+		assert.NotNil(t, application.Storage.SQLite)
+		assert.Nil(t, application.Storage.MongoDB)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes", strings.NewReader(
+			`{"swiftCode":"DEUTDEFFXXX","bankName":"Deutsche Bank","address":"Frankfurt","countryISO2":"DE","countryName":"GERMANY","isHeadquarter":true}`,
+		))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "admin-key")
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		req = httptest.NewRequest(http.MethodGet, "/v1/audit?swiftCode=DEUTDEFFXXX", nil)
+		req.Header.Set("X-API-Key", "admin-key")
+		w = httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"actor":"alice"`)
+	})
+
+	t.Run("Client IPs are only read from trusted proxies", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(&models.SwiftCode{
+			SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY",
+		}, nil)
+		newApp := func(trustedProxies []string) *app.App {
+			application, err := app.New(config.Config{
+				RateLimitRead:  ratelimit.Limit{Requests: 1, Window: time.Minute},
+				TrustedProxies: trustedProxies,
+			},
+				app.WithStorage(func(context.Context, config.Config) (*app.Storage, error) {
+					return &app.Storage{}, nil
+				}),
+				app.WithSwiftRepository(repo),
+			)
+			require.NoError(t, err)
+			return application
+		}
+		get := func(application *app.App, forwardedFor string) int {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/DEUTDEFF500", nil)
+			req.RemoteAddr = "10.0.0.1:41000"
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			w := httptest.NewRecorder()
+			application.Router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+			return w.Code
+		}
+
+		untrusted := newApp(nil)
+		assert.Equal(t, http.StatusOK, get(untrusted, "198.51.100.1"))
+		assert.Equal(t, http.StatusTooManyRequests, get(untrusted, "198.51.100.2"))
+
+		trusted := newApp([]string{"10.0.0.0/8"})
+		assert.Equal(t, http.StatusOK, get(trusted, "198.51.100.1"))
+		assert.Equal(t, http.StatusOK, get(trusted, "198.51.100.2"))
+
+		_, err := app.New(config.Config{TrustedProxies: []string{"not-an-address"}})
+		assert.ErrorContains(t, err, "invalid TRUSTED_PROXIES")
+	})
+
+	t.Run("Runtime metrics are those of the latest app and are only served to administrators", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(&models.SwiftCode{
+			SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY",
+		}, nil)
+		newApp := func() *app.App {
+			application, err := app.New(config.Config{
+				CacheSize: 10,
+				CacheTTL:  time.Minute,
+				APIKeys: map[string]config.APIKey{
+					"admin-key": {Actor: "alice", Admin: true},
+					"user-key":  {Actor: "bob"},
+				},
+			},
+				app.WithStorage(func(context.Context, config.Config) (*app.Storage, error) {
+					return &app.Storage{}, nil
+				}),
+				app.WithSwiftRepository(repo),
+			)
+			require.NoError(t, err)
+			return application
+		}
+		get := func(application *app.App, path, key string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			req.Header.Set("X-API-Key", key)
+			w := httptest.NewRecorder()
+			application.Router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+			return w
+		}
+
+		first := newApp()
+		for range 3 {
+			require.Equal(t, http.StatusOK, get(first, "/v1/swift-codes/DEUTDEFF500", "user-key").Code)
+		}
+		latest := newApp()
+		require.Equal(t, http.StatusOK, get(latest, "/v1/swift-codes/DEUTDEFF500", "user-key").Code)
+
+		assert.Equal(t, http.StatusForbidden, get(latest, "/debug/vars", "user-key").Code)
+
+		w := get(latest, "/debug/vars", "admin-key")
+		require.Equal(t, http.StatusOK, w.Code)
+		var vars struct {
+			SwiftCodesCache struct {
+				Hits   int64 `json:"hits"`
+				Misses int64 `json:"misses"`
+			} `json:"swiftCodesCache"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &vars))
+		assert.Equal(t, int64(0), vars.SwiftCodesCache.Hits)
+		assert.Equal(t, int64(1), vars.SwiftCodesCache.Misses)
+	})
+
+	t.Run("An unknown storage backend is an error", func(t *testing.T) {
+		_, err := app.New(config.Config{Storage: "postgres"})
+		assert.EqualError(t, err, `unknown storage "postgres", expected mongo, sqlite or snapshot`)
+	})
+}
@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/audit"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+	"time"
+)
+
+func TestAuditSwiftRepository(t *testing.T) {
+	for _, tc := range test_cases.GetAuditSwiftRepositoryTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			mockAudit := new(mockRepos.AuditRepository)
+			tc.SetupMocks(mockRepo, mockAudit)
+
+			ctx := reqctx.WithActor(reqctx.WithRequestID(context.Background(), "req-1"), "alice")
+			err := tc.Mutate(ctx, audit.NewSwiftRepository(mockRepo, mockAudit))
+
+			if tc.ExpectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockAudit.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditSwiftRepositoryRetries(t *testing.T) {
+	swiftCode := models.SwiftCode{SwiftCode: "ABCDUS12XXX", CountryISO2: "US"}
+	recorded := make(chan models.AuditEntry, 1)
+
+	mockRepo := new(mockRepos.SwiftRepository)
+	mockRepo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&swiftCode, nil)
+	mockRepo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX", interfaces.AnyRevision).Return(nil)
+	mockAudit := new(mockRepos.AuditRepository)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(errors.New("database error")).Twice()
+	mockAudit.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded <- args.Get(1).(models.AuditEntry)
+	}).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(reqctx.WithActor(context.Background(), "alice"))
+	repo := audit.NewSwiftRepository(mockRepo, mockAudit, audit.WithRetries(3, time.Millisecond))
+	require.NoError(t, repo.DeleteSwiftCode(ctx, "ABCDUS12XXX", interfaces.AnyRevision))
+	cancel()
+
+	select {
+	case entry := <-recorded:
+		assert.Equal(t, models.AuditOperationDelete, entry.Operation)
+		assert.Equal(t, "alice", entry.Actor)
+	case <-time.After(time.Second):
+		t.Fatal("the audit entry was not recorded again")
+	}
+	mockAudit.AssertExpectations(t)
+}
@@ -0,0 +1,206 @@
+package unit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/cache"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"testing"
+	"time"
+)
+
+func newTestCache(repo *mockRepos.SwiftRepository, size int, ttl time.Duration) *cache.SwiftRepository {
+	return cache.NewSwiftRepository(repo, cache.Options{Size: size, TTL: ttl, NegativeTTL: ttl})
+}
+
+func TestCachedSwiftRepository(t *testing.T) {
+	ctx := context.Background()
+	hq := &models.SwiftCode{SwiftCode: "DEUTDE11XXX", SwiftPrefix: "DEUTDE11", CountryISO2: "DE", IsHeadquarter: true}
+	branches := []models.SwiftCode{{SwiftCode: "DEUTDE11BER", SwiftPrefix: "DEUTDE11", CountryISO2: "DE"}}
+
+	t.Run("Repeated lookups are served from the cache", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(hq, nil).Once()
+		mockRepo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			found, err := repo.FindByCode(ctx, "DEUTDE11XXX")
+			require.NoError(t, err)
+			assert.Equal(t, "DEUTDE11XXX", found.SwiftCode)
+
+			found.BankName = "Modified by caller"
+
+			cachedBranches, err := repo.FindBranchesByPrefix(ctx, "DEUTDE11")
+			require.NoError(t, err)
+			assert.Equal(t, branches, cachedBranches)
+		}
+
+		found, _ := repo.FindByCode(ctx, "DEUTDE11XXX")
+		assert.Empty(t, found.BankName)
+
+		stats := repo.Stats()
+		assert.Equal(t, int64(2), stats.Misses)
+		assert.Equal(t, int64(5), stats.Hits)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Not found results are cached", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "NOTFDE11XXX").Return(nil, interfaces.ErrNotFound).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		for i := 0; i < 2; i++ {
+			_, err := repo.FindByCode(ctx, "NOTFDE11XXX")
+			assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		}
+
+		assert.Equal(t, int64(1), repo.Stats().NegativeHits)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Mutations invalidate the code, its prefix and its country", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11BER").Return(nil, interfaces.ErrNotFound).Once()
+		mockRepo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return([]models.SwiftCode{}, nil).Once()
+		mockRepo.On("FindByCountryISO2", mock.Anything, "DE").Return([]models.SwiftCode{*hq}, "GERMANY", nil).Once()
+		mockRepo.On("AddSwiftCode", mock.Anything, branches[0]).Return(nil)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11BER").Return(&branches[0], nil).Once()
+		mockRepo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil).Once()
+		mockRepo.On("FindByCountryISO2", mock.Anything, "DE").Return([]models.SwiftCode{*hq, branches[0]}, "GERMANY", nil).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		_, _ = repo.FindByCode(ctx, "DEUTDE11BER")
+		_, _ = repo.FindBranchesByPrefix(ctx, "DEUTDE11")
+		_, _, _ = repo.FindByCountryISO2(ctx, "DE")
+
+		require.NoError(t, repo.AddSwiftCode(ctx, branches[0]))
+
+		found, err := repo.FindByCode(ctx, "DEUTDE11BER")
+		require.NoError(t, err)
+		assert.Equal(t, "DEUTDE11BER", found.SwiftCode)
+		cachedBranches, _ := repo.FindBranchesByPrefix(ctx, "DEUTDE11")
+		assert.Len(t, cachedBranches, 1)
+		country, _, _ := repo.FindByCountryISO2(ctx, "DE")
+		assert.Len(t, country, 2)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("A lookup overtaken by a mutation is not cached", func(t *testing.T) {
+		before := &models.SwiftCode{SwiftCode: "DEUTDE11BER", BankName: "BEFORE"}
+		after := &models.SwiftCode{SwiftCode: "DEUTDE11BER", BankName: "AFTER"}
+		fetching := make(chan struct{})
+		release := make(chan struct{})
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11BER").Run(func(mock.Arguments) {
+			close(fetching)
+			<-release
+		}).Return(before, nil).Once()
+		mockRepo.On("UpdateSwiftCode", mock.Anything, *after, int64(1)).Return(nil).Once()
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11BER").Return(after, nil).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		done := make(chan *models.SwiftCode)
+		go func() {
+			found, _ := repo.FindByCode(ctx, "DEUTDE11BER")
+			done <- found
+		}()
+		<-fetching
+		require.NoError(t, repo.UpdateSwiftCode(ctx, *after, 1))
+		close(release)
+		assert.Equal(t, "BEFORE", (<-done).BankName)
+
+		found, err := repo.FindByCode(ctx, "DEUTDE11BER")
+		require.NoError(t, err)
+		assert.Equal(t, "AFTER", found.BankName)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Least recently used entries are evicted", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "AAAADE11XXX").Return(&models.SwiftCode{SwiftCode: "AAAADE11XXX"}, nil).Twice()
+		mockRepo.On("FindByCode", mock.Anything, "BBBBDE11XXX").Return(&models.SwiftCode{SwiftCode: "BBBBDE11XXX"}, nil).Once()
+		mockRepo.On("FindByCode", mock.Anything, "CCCCDE11XXX").Return(&models.SwiftCode{SwiftCode: "CCCCDE11XXX"}, nil).Once()
+		repo := newTestCache(mockRepo, 2, time.Minute)
+
+		_, _ = repo.FindByCode(ctx, "AAAADE11XXX")
+		_, _ = repo.FindByCode(ctx, "BBBBDE11XXX")
+		_, _ = repo.FindByCode(ctx, "BBBBDE11XXX")
+		_, _ = repo.FindByCode(ctx, "CCCCDE11XXX")
+		_, _ = repo.FindByCode(ctx, "BBBBDE11XXX")
+		_, _ = repo.FindByCode(ctx, "AAAADE11XXX")
+
+		assert.Equal(t, int64(2), repo.Stats().Evictions)
+		assert.Equal(t, 2, repo.Stats().Entries)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Entries expire after the TTL", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(hq, nil).Twice()
+		repo := newTestCache(mockRepo, 10, 20*time.Millisecond)
+
+		_, _ = repo.FindByCode(ctx, "DEUTDE11XXX")
+		time.Sleep(30 * time.Millisecond)
+		_, _ = repo.FindByCode(ctx, "DEUTDE11XXX")
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Reads including deleted records bypass the cache", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(hq, nil).Times(3)
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		adminCtx := reqctx.WithIncludeDeleted(ctx)
+		_, _ = repo.FindByCode(adminCtx, "DEUTDE11XXX")
+		_, _ = repo.FindByCode(adminCtx, "DEUTDE11XXX")
+		_, _ = repo.FindByCode(ctx, "DEUTDE11XXX")
+		_, _ = repo.FindByCode(ctx, "DEUTDE11XXX")
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Batch lookups only fetch uncached codes", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(hq, nil).Once()
+		mockRepo.On("FindByCodes", mock.Anything, []string{"DEUTDE11BER", "NOTFDE11XXX"}).Return(branches, nil).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		_, _ = repo.FindByCode(ctx, "DEUTDE11XXX")
+		for i := 0; i < 2; i++ {
+			found, err := repo.FindByCodes(ctx, []string{"DEUTDE11XXX", "DEUTDE11BER", "NOTFDE11XXX"})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []models.SwiftCode{*hq, branches[0]}, found)
+		}
+
+		_, err := repo.FindByCode(ctx, "NOTFDE11XXX")
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Batch branch lookups only fetch uncached prefixes", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil).Once()
+		mockRepo.On("FindBranchesByPrefixes", mock.Anything, []string{"COBADEFF"}).Return([]models.SwiftCode{}, nil).Once()
+		repo := newTestCache(mockRepo, 10, time.Minute)
+
+		_, _ = repo.FindBranchesByPrefix(ctx, "DEUTDE11")
+		for i := 0; i < 2; i++ {
+			found, err := repo.FindBranchesByPrefixes(ctx, []string{"DEUTDE11", "COBADEFF"})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, branches, found)
+		}
+
+		found, err := repo.FindBranchesByPrefix(ctx, "COBADEFF")
+		require.NoError(t, err)
+		assert.Empty(t, found)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,171 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/middleware"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/breaker"
+	"swift-codes-api/repositories/cache"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errDatabaseDown = errors.New("server selection timeout")
+
+func breakerOptions() breaker.Options {
+	return breaker.Options{FailureRate: 0.5, MinCalls: 4, Window: time.Minute, OpenTimeout: 20 * time.Millisecond, Probes: 2}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	branch := &models.SwiftCode{SwiftCode: "DEUTDEFF500", SwiftPrefix: "DEUTDEFF", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "GERMANY"}
+
+	t.Run("The circuit opens at the failure rate and then fails fast", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(branch, nil).Twice()
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(nil, errDatabaseDown).Twice()
+		breaking := breaker.NewSwiftRepository(repo, breakerOptions())
+
+		for range 2 {
+			_, err := breaking.FindByCode(ctx, "DEUTDEFF500")
+			require.NoError(t, err)
+		}
+		_, err := breaking.FindByCode(ctx, "DEUTDEFF500")
+		assert.ErrorIs(t, err, interfaces.ErrUnavailable)
+		assert.ErrorIs(t, err, errDatabaseDown)
+		assert.Equal(t, breaker.StateClosed, breaking.Stats().State)
+
+		_, err = breaking.FindByCode(ctx, "DEUTDEFF500")
+		assert.ErrorIs(t, err, errDatabaseDown)
+		assert.Equal(t, breaker.StateOpen, breaking.Stats().State)
+
+		_, err = breaking.FindByCode(ctx, "DEUTDEFF500")
+		assert.ErrorIs(t, err, breaker.ErrOpen)
+		assert.ErrorIs(t, err, interfaces.ErrUnavailable)
+		assert.Equal(t, int64(1), breaking.Stats().Rejected)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Missing records and conflicts are not failures", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(nil, interfaces.ErrNotFound).Times(4)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDEFF500", int64(1)).Return(interfaces.ErrRevisionMismatch).Times(4)
+		breaking := breaker.NewSwiftRepository(repo, breakerOptions())
+
+		for range 4 {
+			_, err := breaking.FindByCode(ctx, "DEUTDEFF500")
+			assert.Equal(t, interfaces.ErrNotFound, err)
+			assert.Equal(t, interfaces.ErrRevisionMismatch, breaking.DeleteSwiftCode(ctx, "DEUTDEFF500", 1))
+		}
+		assert.Equal(t, breaker.StateClosed, breaking.Stats().State)
+	})
+
+	t.Run("Probes close the circuit once they all succeed", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(nil, errDatabaseDown).Times(4)
+		breaking := breaker.NewSwiftRepository(repo, breakerOptions())
+		for range 4 {
+			_, _ = breaking.FindByCode(ctx, "DEUTDEFF500")
+		}
+		require.Equal(t, breaker.StateOpen, breaking.Stats().State)
+
+		// A failed probe opens the circuit again.
+		time.Sleep(25 * time.Millisecond)
+		assert.Equal(t, breaker.StateHalfOpen, breaking.Stats().State)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(nil, errDatabaseDown).Once()
+		_, err := breaking.FindByCode(ctx, "DEUTDEFF500")
+		assert.ErrorIs(t, err, errDatabaseDown)
+		assert.Equal(t, breaker.StateOpen, breaking.Stats().State)
+
+		time.Sleep(25 * time.Millisecond)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(branch, nil).Twice()
+		for range 2 {
+			_, err = breaking.FindByCode(ctx, "DEUTDEFF500")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, breaker.StateClosed, breaking.Stats().State)
+		assert.Equal(t, int64(2), breaking.Stats().Trips)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Lookups are served stale from the cache while the circuit is open", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDEFF500").Return(branch, nil).Once()
+		repo.On("FindByCode", mock.Anything, mock.Anything).Return(nil, errDatabaseDown)
+
+		options := breakerOptions()
+		options.MinCalls = 1
+		options.OpenTimeout = time.Minute
+		cached := cache.NewSwiftRepository(breaker.NewSwiftRepository(repo, options), cache.Options{
+			Size: 10, TTL: time.Millisecond, StaleTTL: time.Hour,
+		})
+		router := gin.New()
+		routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: cached}, config.Config{})
+
+		get := func(code string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/"+code, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+			return w
+		}
+
+		w := get("DEUTDEFF500")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Warning"))
+		time.Sleep(5 * time.Millisecond)
+
+		w = get("DEUTDEFF500")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `110 - "Response is Stale"`, w.Header().Get("Warning"))
+		assert.Equal(t, "0", w.Header().Get("Age"))
+		assert.Contains(t, w.Body.String(), `"bankName":"Deutsche Bank"`)
+
+		w = get("BNPAFRPP100")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"service-unavailable"`)
+		assert.Equal(t, int64(1), cached.Stats().StaleHits)
+	})
+
+	t.Run("Stale data reported from concurrent resolvers flags the response once", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		now := time.Now()
+		router := gin.New()
+		router.Use(middleware.StaleWarning())
+		router.GET("/parallel", func(c *gin.Context) {
+			var wg sync.WaitGroup
+			for i := range 20 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					reqctx.MarkStale(c.Request.Context(), now.Add(-time.Duration(i)*time.Minute))
+				}()
+			}
+			wg.Wait()
+			c.Status(http.StatusOK)
+			c.Writer.WriteHeaderNow()
+			reqctx.MarkStale(c.Request.Context(), now.Add(-time.Hour))
+		})
+
+		// The route only exists in this test, so the spec does not describe it.
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/parallel", nil))
+		assert.Equal(t, `110 - "Response is Stale"`, w.Header().Get("Warning"))
+		assert.Equal(t, "1140", w.Header().Get("Age"))
+	})
+}
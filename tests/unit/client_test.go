@@ -0,0 +1,305 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/client"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newClientTestServer(t *testing.T, repo *mockRepos.SwiftRepository) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: repo}, config.Config{
+		APIKeys: map[string]config.APIKey{"admin-key": {Actor: "alice", Admin: true}},
+	})
+
+	// Every response the client is given is checked against the OpenAPI
+	// document on the way.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := httptest.NewRecorder()
+		router.ServeHTTP(recorded, r)
+		spec.ValidateResponse(t, r, recorded)
+
+		for key, values := range recorded.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(recorded.Code)
+		_, _ = w.Write(recorded.Body.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient(t *testing.T) {
+	headquarter := &models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+		Revision:      2,
+	}
+	branch := models.SwiftCode{
+		SwiftCode:   "DEUTDE11MUN",
+		SwiftPrefix: "DEUTDE11",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "789 Branch St, Munich",
+	}
+	fastRetries := client.WithRetries(3, time.Millisecond, 5*time.Millisecond)
+
+	t.Run("GetSwiftCode returns a headquarter with its branches", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+		repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return([]models.SwiftCode{branch}, nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		lookup, err := c.GetSwiftCode(context.Background(), "DEUTDE11XXX")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deutsche Bank", lookup.BankName)
+		assert.Equal(t, int64(2), lookup.Revision)
+		require.Len(t, lookup.Branches, 1)
+		assert.Equal(t, "DEUTDE11MUN", lookup.Branches[0].SwiftCode)
+	})
+
+	t.Run("Errors carry the API's error code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, errors.New("not found"))
+
+		c := client.New(newClientTestServer(t, repo).URL, fastRetries)
+		_, err := c.GetSwiftCode(context.Background(), "ABCDEF12XXX")
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+		assert.Equal(t, client.SwiftCodeNotFound, apiErr.Code)
+		assert.NotEmpty(t, apiErr.RequestID)
+		assert.True(t, client.IsCode(err, client.SwiftCodeNotFound))
+		repo.AssertNumberOfCalls(t, "FindByCode", 1)
+	})
+
+	t.Run("Validation errors list the failing fields", func(t *testing.T) {
+		c := client.New(newClientTestServer(t, new(mockRepos.SwiftRepository)).URL)
+		err := c.Add(context.Background(), client.SwiftCodeRequest{SwiftCode: "DEUTDE11XXX", CountryISO2: "DE"})
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, client.ValidationFailed, apiErr.Code)
+		assert.Len(t, apiErr.Fields, 3)
+	})
+
+	t.Run("ListByCountry", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCountryISO2", mock.Anything, "DE").Return([]models.SwiftCode{*headquarter, branch}, "Germany", nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		country, err := c.ListByCountry(context.Background(), "de")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Germany", country.CountryName)
+		assert.Len(t, country.SwiftCodes, 2)
+	})
+
+	t.Run("LookupMany", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, []string{"DEUTDE11XXX", "ABCDEF12XXX"}).Return([]models.SwiftCode{*headquarter}, nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		results, err := c.LookupMany(context.Background(), []string{"DEUTDE11", "ABCDEF12XXX"})
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Deutsche Bank", results[0].Record.BankName)
+		assert.Nil(t, results[1].Record)
+	})
+
+	t.Run("API key grants administrator options", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&branch, nil)
+		url := newClientTestServer(t, repo).URL
+
+		_, err := client.New(url).GetSwiftCode(context.Background(), "DEUTDE11MUN", client.IncludeDeleted())
+		assert.True(t, client.IsCode(err, client.Forbidden))
+
+		_, err = client.New(url, client.WithAPIKey("admin-key")).GetSwiftCode(context.Background(), "DEUTDE11MUN", client.IncludeDeleted())
+		assert.NoError(t, err)
+	})
+
+	t.Run("Add sends the SWIFT code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("AddSwiftCode", mock.Anything, mock.MatchedBy(func(sc models.SwiftCode) bool {
+			return sc.SwiftCode == "DEUTDE11MUN" && sc.CountryISO2 == "DE" && !sc.IsHeadquarter
+		})).Return(nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		err := c.Add(context.Background(), client.SwiftCodeRequest{
+			SwiftCode:   "DEUTDE11MUN",
+			BankName:    "Deutsche Bank",
+			Address:     "789 Branch St, Munich",
+			CountryISO2: "de",
+			CountryName: "Germany",
+		})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Delete with a stale revision", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		err := c.Delete(context.Background(), "DEUTDE11XXX", client.IfRevision(1))
+
+		assert.True(t, client.IsCode(err, client.RevisionMismatch))
+	})
+
+	t.Run("Delete is retried after a server error", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", int64(2)).Return(errors.New("database error")).Once()
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", int64(2)).Return(nil).Once()
+
+		c := client.New(newClientTestServer(t, repo).URL, fastRetries)
+		err := c.Delete(context.Background(), "DEUTDE11XXX", client.IfRevision(2))
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Add is not retried after a server error", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(errors.New("database error")).Once()
+
+		c := client.New(newClientTestServer(t, repo).URL, fastRetries)
+		err := c.Add(context.Background(), client.SwiftCodeRequest{
+			SwiftCode:   "DEUTDE11MUN",
+			BankName:    "Deutsche Bank",
+			Address:     "789 Branch St, Munich",
+			CountryISO2: "DE",
+			CountryName: "Germany",
+		})
+
+		assert.True(t, client.IsCode(err, client.Internal))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Export streams every SWIFT code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("StreamSwiftCodes", mock.Anything, "DE").Return([]models.SwiftCode{*headquarter, branch}, nil)
+
+		c := client.New(newClientTestServer(t, repo).URL)
+		var codes []string
+		err := c.Export(context.Background(), "de", func(sc client.SwiftCode) error {
+			codes = append(codes, sc.SwiftCode)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DEUTDE11XXX", "DEUTDE11MUN"}, codes)
+	})
+
+	t.Run("Rate-limited requests honour Retry-After", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		err := client.New(server.URL, fastRetries).Delete(context.Background(), "DEUTDE11MUN")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("Rate-limited POST requests are not retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		err := client.New(server.URL, fastRetries).Add(context.Background(), client.SwiftCodeRequest{SwiftCode: "DEUTDE11MUN"})
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("A Retry-After beyond the maximum backoff fails right away", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		_, err := client.New(server.URL, fastRetries).GetSwiftCode(context.Background(), "DEUTDE11XXX")
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+		assert.Equal(t, int32(1), attempts.Load())
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Retries give up after the limit", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := client.New(server.URL, fastRetries).GetSwiftCode(context.Background(), "DEUTDE11XXX")
+
+		var apiErr *client.Error
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+		assert.Empty(t, apiErr.Code)
+		assert.Equal(t, int32(4), attempts.Load())
+	})
+
+	t.Run("Cancelled context stops retrying", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.New(server.URL, client.WithRetries(100, time.Second, time.Second)).GetSwiftCode(ctx, "DEUTDE11XXX")
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
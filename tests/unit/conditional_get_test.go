@@ -0,0 +1,73 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/middleware"
+	"swift-codes-api/tests/unit/test_cases"
+	"swift-codes-api/utils"
+	"testing"
+)
+
+func TestConditionalGET(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	etag := utils.ETag([]byte(`{"swiftCode":"AAISALTRXXX"}`))
+
+	for _, tc := range test_cases.GetConditionalGETTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			router := gin.Default()
+			router.GET("/resource", middleware.ConditionalGET("max-age=60"), func(c *gin.Context) {
+				c.Header("Last-Modified", "Sun, 01 Mar 2026 12:00:00 GMT")
+				c.JSON(tc.HandlerStatus, gin.H{"swiftCode": "AAISALTRXXX"})
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+			for k, v := range tc.Headers {
+				req.Header.Set(k, strings.ReplaceAll(v, "{etag}", etag))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			if tc.ExpectedResponse == "" {
+				assert.Empty(t, w.Body.String())
+			} else {
+				assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+			}
+
+			if tc.HandlerStatus == http.StatusOK {
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+				assert.Equal(t, "max-age=60", w.Header().Get("Cache-Control"))
+			} else {
+				assert.Empty(t, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestConditionalGETPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.Recovery(log.New(io.Discard, "", 0)))
+	router.GET("/resource", middleware.ConditionalGET("max-age=60"), func(c *gin.Context) {
+		_, _ = c.Writer.WriteString(`{"swiftCode":`)
+		c.Writer.Flush()
+		panic("lost connection halfway")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/resource", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "Internal server error")
+	assert.Empty(t, w.Header().Get("ETag"))
+}
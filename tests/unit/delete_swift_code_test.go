@@ -8,6 +8,7 @@ import (
 	"swift-codes-api/handlers"
 	"swift-codes-api/internal/config"
 	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
 	"swift-codes-api/tests/unit/test_cases"
 	"testing"
 )
@@ -22,11 +23,15 @@ func TestDeleteSwiftCode(t *testing.T) {
 
 			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
 			router := gin.Default()
-			router.DELETE("/swift-codes/:swift-code", handler.DeleteSwiftCode)
+			router.DELETE("/v1/swift-codes/:swift-code", handler.DeleteSwiftCode)
 
-			req := httptest.NewRequest(http.MethodDelete, "/swift-codes/"+tc.SwiftCode, nil)
+			req := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/"+tc.SwiftCode, nil)
+			if tc.IfMatch != "" {
+				req.Header.Set("If-Match", tc.IfMatch)
+			}
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
 
 			assert.Equal(t, tc.ExpectedStatus, w.Code)
 			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
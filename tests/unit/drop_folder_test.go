@@ -0,0 +1,230 @@
+package unit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"swift-codes-api/internal/dropfolder"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"testing"
+	"time"
+)
+
+const dropFolderFile = `[{"swiftCode":"DEUTDEFFXXX","bankName":"Deutsche Bank","address":"Frankfurt","countryISO2":"DE","countryName":"GERMANY","isHeadquarter":true}]`
+
+func newDropFolder(t *testing.T) (string, *mockRepos.DropFolderRepository, *mockRepos.ImportRepository, *dropfolder.Watcher) {
+	dir := t.TempDir()
+	for _, sub := range []string{dropfolder.ProcessingDir, dropfolder.ProcessedDir, dropfolder.FailedDir} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0o755))
+	}
+
+	runs := new(mockRepos.DropFolderRepository)
+	jobs := new(mockRepos.ImportRepository)
+	watcher := dropfolder.NewWatcher(dir, time.Second, runs, importer.NewRunner(jobs, new(mockRepos.SwiftRepository), 1))
+	return dir, runs, jobs, watcher
+}
+
+// dropFile writes a file into the folder, old enough to be picked up unless
+// fresh is set.
+func dropFile(t *testing.T, dir, name, content string, fresh bool) {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	if !fresh {
+		old := time.Now().Add(-time.Minute)
+		require.NoError(t, os.Chtimes(path, old, old))
+	}
+}
+
+// expectRuns answers the lookups of importing and claimed runs made by a
+// poll.
+func expectRuns(runs *mockRepos.DropFolderRepository, importing, claimed []models.DropFolderRun) {
+	runs.On("FindRunsByStatus", mock.Anything, models.DropFolderRunImporting).Return(importing, nil).Once()
+	runs.On("FindRunsByStatus", mock.Anything, models.DropFolderRunClaimed).Return(claimed, nil).Once()
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDropFolderWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("A new file is imported and moved to processed once its job succeeds", func(t *testing.T) {
+		dir, runs, jobs, watcher := newDropFolder(t)
+		dropFile(t, dir, "directory.json", dropFolderFile, false)
+
+		var claimed, created models.DropFolderRun
+		expectRuns(runs, []models.DropFolderRun{}, []models.DropFolderRun{})
+		runs.On("FindImportedRun", mock.Anything, checksum(dropFolderFile)).Return(nil, interfaces.ErrNotFound).Once()
+		runs.On("CreateRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			claimed = args.Get(1).(models.DropFolderRun)
+		}).Return(nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.Anything, models.DropFolderRunClaimed).Run(func(args mock.Arguments) {
+			created = args.Get(1).(models.DropFolderRun)
+		}).Return(nil).Once()
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "directory.json", mock.Anything).Return(int64(len(dropFolderFile)), nil).Once()
+		jobs.On("CreateJob", mock.Anything, mock.MatchedBy(func(job models.ImportJob) bool {
+			return job.Actor == dropfolder.Actor && job.Format == "json"
+		})).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		assert.Equal(t, models.DropFolderRunClaimed, claimed.Status)
+		assert.Empty(t, claimed.ImportID)
+		assert.Equal(t, claimed.ID, created.ID)
+		assert.Equal(t, models.DropFolderRunImporting, created.Status)
+		assert.NotEmpty(t, created.ImportID)
+		assert.NoFileExists(t, filepath.Join(dir, "directory.json"))
+		assert.FileExists(t, filepath.Join(dir, dropfolder.ProcessingDir, created.ID+"-directory.json"))
+
+		expectRuns(runs, []models.DropFolderRun{created}, []models.DropFolderRun{})
+		jobs.On("FindJob", mock.Anything, created.ImportID).Return(&models.ImportJob{
+			ID: created.ImportID, Status: models.ImportStatusSucceeded, Processed: 1, Created: 1,
+		}, nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.MatchedBy(func(run models.DropFolderRun) bool {
+			return run.ID == created.ID && run.Status == models.DropFolderRunSucceeded && run.Created == 1 &&
+				run.MovedTo == filepath.Join(dropfolder.ProcessedDir, "directory.json") && run.FinishedAt != nil
+		}), models.DropFolderRunImporting).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		assert.FileExists(t, filepath.Join(dir, dropfolder.ProcessedDir, "directory.json"))
+		assert.NoFileExists(t, filepath.Join(dir, dropfolder.ProcessingDir, created.ID+"-directory.json"))
+		runs.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("A file already imported is recorded as a duplicate", func(t *testing.T) {
+		dir, runs, jobs, watcher := newDropFolder(t)
+		dropFile(t, dir, "directory-copy.json", dropFolderFile, false)
+
+		expectRuns(runs, []models.DropFolderRun{}, []models.DropFolderRun{})
+		runs.On("FindImportedRun", mock.Anything, checksum(dropFolderFile)).
+			Return(&models.DropFolderRun{ID: "run-0", Status: models.DropFolderRunSucceeded}, nil).Once()
+		runs.On("CreateRun", mock.Anything, mock.Anything).Return(nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.MatchedBy(func(run models.DropFolderRun) bool {
+			return run.Status == models.DropFolderRunDuplicate && run.DuplicateOf == "run-0" && run.Checksum == checksum(dropFolderFile)
+		}), models.DropFolderRunClaimed).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		assert.FileExists(t, filepath.Join(dir, dropfolder.ProcessedDir, "directory-copy.json"))
+		runs.AssertExpectations(t)
+		jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("A file of unknown format is moved to failed", func(t *testing.T) {
+		dir, runs, _, watcher := newDropFolder(t)
+		dropFile(t, dir, "directory.xlsx", "PK", false)
+
+		expectRuns(runs, []models.DropFolderRun{}, []models.DropFolderRun{})
+		runs.On("CreateRun", mock.Anything, mock.Anything).Return(nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.MatchedBy(func(run models.DropFolderRun) bool {
+			return run.Status == models.DropFolderRunFailed && run.Error == "Only .json, .ndjson and .csv files can be imported"
+		}), models.DropFolderRunClaimed).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		assert.FileExists(t, filepath.Join(dir, dropfolder.FailedDir, "directory.xlsx"))
+		runs.AssertExpectations(t)
+	})
+
+	t.Run("The file of a failed job is moved to failed without overwriting another", func(t *testing.T) {
+		dir, runs, jobs, watcher := newDropFolder(t)
+		run := models.DropFolderRun{ID: "run-1", FileName: "directory.csv", Status: models.DropFolderRunImporting, ImportID: "job-1"}
+		require.NoError(t, os.WriteFile(filepath.Join(dir, dropfolder.ProcessingDir, "run-1-directory.csv"), []byte("new"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, dropfolder.FailedDir, "directory.csv"), []byte("old"), 0o644))
+
+		expectRuns(runs, []models.DropFolderRun{run}, []models.DropFolderRun{})
+		jobs.On("FindJob", mock.Anything, "job-1").Return(&models.ImportJob{ID: "job-1", Status: models.ImportStatusFailed, Error: "the CSV header has no bankName column"}, nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.MatchedBy(func(run models.DropFolderRun) bool {
+			return run.Status == models.DropFolderRunFailed && run.Error == "the CSV header has no bankName column" &&
+				run.MovedTo == filepath.Join(dropfolder.FailedDir, "directory.run-1.csv")
+		}), models.DropFolderRunImporting).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		content, err := os.ReadFile(filepath.Join(dir, dropfolder.FailedDir, "directory.run-1.csv"))
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(content))
+		runs.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Running jobs and files still being written are left alone", func(t *testing.T) {
+		dir, runs, jobs, watcher := newDropFolder(t)
+		dropFile(t, dir, "directory.json", dropFolderFile, true)
+		dropFile(t, dir, ".directory.json.part", dropFolderFile, false)
+		run := models.DropFolderRun{ID: "run-2", FileName: "other.json", Status: models.DropFolderRunImporting, ImportID: "job-2"}
+
+		expectRuns(runs, []models.DropFolderRun{run}, []models.DropFolderRun{})
+		jobs.On("FindJob", mock.Anything, "job-2").Return(&models.ImportJob{ID: "job-2", Status: models.ImportStatusRunning}, nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		assert.FileExists(t, filepath.Join(dir, "directory.json"))
+		assert.FileExists(t, filepath.Join(dir, ".directory.json.part"))
+		runs.AssertExpectations(t)
+		runs.AssertNotCalled(t, "UpdateRun", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Files left in processing are returned to the folder only after the claim timeout", func(t *testing.T) {
+		dir, runs, _, watcher := newDropFolder(t)
+		processing := filepath.Join(dir, dropfolder.ProcessingDir)
+		require.NoError(t, os.WriteFile(filepath.Join(processing, "r3-orphan.json"), []byte(dropFolderFile), 0o644))
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(filepath.Join(processing, "r3-orphan.json"), old, old))
+		// Claimed a moment ago by another replica, which has not recorded
+		// its run yet.
+		require.NoError(t, os.WriteFile(filepath.Join(processing, "r4-claimed.json"), []byte(dropFolderFile), 0o644))
+		stale := models.DropFolderRun{ID: "r5", FileName: "stale.json", Status: models.DropFolderRunClaimed, StartedAt: time.Now().Add(-time.Hour)}
+		require.NoError(t, os.WriteFile(filepath.Join(processing, "r5-stale.json"), []byte(dropFolderFile), 0o644))
+		recent := models.DropFolderRun{ID: "r6", FileName: "recent.json", Status: models.DropFolderRunClaimed, StartedAt: time.Now()}
+		require.NoError(t, os.WriteFile(filepath.Join(processing, "r6-recent.json"), []byte(dropFolderFile), 0o644))
+
+		expectRuns(runs, []models.DropFolderRun{}, []models.DropFolderRun{stale, recent})
+		runs.On("UpdateRun", mock.Anything, mock.MatchedBy(func(run models.DropFolderRun) bool {
+			return run.ID == "r5" && run.Status == models.DropFolderRunFailed && run.FinishedAt != nil &&
+				run.Error == "Returned to the drop folder: the import was not queued within 10m0s"
+		}), models.DropFolderRunClaimed).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		// Both were touched as they were returned, so they wait for the
+		// next interval before being picked up again.
+		assert.FileExists(t, filepath.Join(dir, "orphan.json"))
+		assert.FileExists(t, filepath.Join(dir, "stale.json"))
+		assert.FileExists(t, filepath.Join(processing, "r4-claimed.json"))
+		assert.FileExists(t, filepath.Join(processing, "r6-recent.json"))
+		runs.AssertExpectations(t)
+	})
+
+	t.Run("A run released while its job was queued cancels the job", func(t *testing.T) {
+		dir, runs, jobs, watcher := newDropFolder(t)
+		dropFile(t, dir, "directory.json", dropFolderFile, false)
+
+		expectRuns(runs, []models.DropFolderRun{}, []models.DropFolderRun{})
+		runs.On("FindImportedRun", mock.Anything, checksum(dropFolderFile)).Return(nil, interfaces.ErrNotFound).Once()
+		runs.On("CreateRun", mock.Anything, mock.Anything).Return(nil).Once()
+		runs.On("UpdateRun", mock.Anything, mock.Anything, models.DropFolderRunClaimed).Return(interfaces.ErrNotFound).Once()
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "directory.json", mock.Anything).Return(int64(len(dropFolderFile)), nil).Once()
+		jobs.On("CreateJob", mock.Anything, mock.Anything).Return(nil).Once()
+		jobs.On("CancelJob", mock.Anything, mock.Anything, mock.Anything).
+			Return(&models.ImportJob{Status: models.ImportStatusCancelled}, nil).Once()
+		jobs.On("DeleteFile", mock.Anything, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, watcher.Poll(ctx))
+
+		runs.AssertExpectations(t)
+		jobs.AssertExpectations(t)
+	})
+}
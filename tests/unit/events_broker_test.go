@@ -0,0 +1,123 @@
+package unit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/repositories/publishing"
+	"testing"
+	"time"
+)
+
+func TestBrokerPublish(t *testing.T) {
+	t.Run("Assigns an ID, logs and fans out to matching subscribers", func(t *testing.T) {
+		mockRepo := new(mockRepos.EventRepository)
+		mockRepo.On("Append", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+			return e.SwiftCode == "DEUTDE11XXX" && !e.Timestamp.IsZero()
+		})).Return(models.Event{ID: 42, Type: models.EventTypeCreated, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE"}, nil)
+
+		broker := events.NewBroker(mockRepo)
+		german := broker.Subscribe(events.Filter{CountryISO2: "DE"})
+		polish := broker.Subscribe(events.Filter{CountryISO2: "PL"})
+		deletions := broker.Subscribe(events.Filter{Types: []string{models.EventTypeDeleted}})
+
+		err := broker.Publish(context.Background(), models.Event{
+			Type:        models.EventTypeCreated,
+			SwiftCode:   "DEUTDE11XXX",
+			CountryISO2: "DE",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, german.Events(), 1)
+		assert.Equal(t, int64(42), (<-german.Events()).ID)
+		assert.Len(t, polish.Events(), 0)
+		assert.Len(t, deletions.Events(), 0)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Fans out changes logged by another replica under their ID", func(t *testing.T) {
+		mockRepo := new(mockRepos.EventRepository)
+		mockRepo.On("Append", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+			return e.SourceID == "token"
+		})).Return(models.Event{ID: 7, SourceID: "token"}, nil)
+
+		broker := events.NewBroker(mockRepo)
+		sub := broker.Subscribe(events.Filter{})
+		require.NoError(t, broker.Publish(context.Background(), models.Event{SourceID: "token"}))
+
+		assert.Equal(t, int64(7), (<-sub.Events()).ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Subscribing does not wait for the event log", func(t *testing.T) {
+		release := make(chan struct{})
+		mockRepo := new(mockRepos.EventRepository)
+		mockRepo.On("Append", mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { <-release }).Return(models.Event{ID: 1}, nil)
+
+		broker := events.NewBroker(mockRepo)
+		published := make(chan error)
+		go func() { published <- broker.Publish(context.Background(), models.Event{}) }()
+
+		subscribed := make(chan *events.Subscription)
+		go func() { subscribed <- broker.Subscribe(events.Filter{}) }()
+		select {
+		case sub := <-subscribed:
+			broker.Unsubscribe(sub)
+		case <-time.After(time.Second):
+			t.Fatal("Subscribe waited for Publish")
+		}
+
+		close(release)
+		require.NoError(t, <-published)
+	})
+
+	t.Run("Drops subscribers that fall behind", func(t *testing.T) {
+		mockRepo := new(mockRepos.EventRepository)
+		mockRepo.On("Append", mock.Anything, mock.Anything).Return(models.Event{ID: 1}, nil)
+
+		broker := events.NewBroker(mockRepo)
+		sub := broker.Subscribe(events.Filter{})
+
+		for i := 0; i <= cap(sub.Events()); i++ {
+			require.NoError(t, broker.Publish(context.Background(), models.Event{}))
+		}
+
+		received := 0
+		for range sub.Events() {
+			received++
+		}
+		assert.Equal(t, cap(sub.Events()), received)
+	})
+}
+
+func TestPublishingSwiftRepository(t *testing.T) {
+	swiftCode := models.SwiftCode{
+		SwiftCode:   "DEUTDE11XXX",
+		SwiftPrefix: "DEUTDE11",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+	}
+
+	mockRepo := new(mockRepos.SwiftRepository)
+	mockRepo.On("AddSwiftCode", mock.Anything, swiftCode).Return(nil)
+	mockRepo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(&swiftCode, nil)
+
+	mockPublisher := new(mockRepos.EventPublisher)
+	mockPublisher.On("Publish", mock.Anything, mock.MatchedBy(func(e models.Event) bool {
+		return e.Type == models.EventTypeCreated && e.SwiftCode == "DEUTDE11XXX" &&
+			e.CountryISO2 == "DE" && e.Record.BankName == "Deutsche Bank"
+	})).Return(nil)
+
+	repo := publishing.NewSwiftRepository(mockRepo, mockPublisher)
+	require.NoError(t, repo.AddSwiftCode(context.Background(), swiftCode))
+
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/middleware"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestExportFormats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetExportTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
+			router := gin.Default()
+			router.GET("/v1/swift-codes/country/:countryISO2code", handler.GetSwiftCodesByCountry)
+			router.GET("/v1/export", handler.ExportSwiftCodes)
+
+			req := httptest.NewRequest(http.MethodGet, tc.Path, nil)
+			if tc.Accept != "" {
+				req.Header.Set("Accept", tc.Accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.Equal(t, tc.ExpectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tc.ExpectedBody, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportBrokenOff(t *testing.T) {
+	// More records than fit in the server's buffer, so that the response
+	// has started when the cursor fails.
+	swiftCodes := make([]models.SwiftCode, 100)
+	for i := range swiftCodes {
+		swiftCodes[i] = models.SwiftCode{SwiftCode: "BREXPLPWXXX", BankName: "mBank S.A.", Address: "Prosta 18, Warszawa", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true}
+	}
+	repo := new(mockRepos.SwiftRepository)
+	repo.On("StreamSwiftCodes", mock.Anything, "").Return(swiftCodes, errors.New("cursor error"))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Recovery(log.New(io.Discard, "", 0)))
+	router.GET("/v1/export", handlers.NewSwiftHandler(config.Config{}, repo).ExportSwiftCodes)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/v1/export?format=ndjson")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Contains(t, string(body), `"swiftCode":"BREXPLPWXXX"`)
+}
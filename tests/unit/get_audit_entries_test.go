@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/reqctx"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestGetAuditEntries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetAuditEntriesTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.AuditRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewAuditHandler(mockRepo)
+			router := gin.Default()
+			if !tc.NotAdmin {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(reqctx.WithAdmin(c.Request.Context()))
+				})
+			}
+			router.GET("/v1/audit", handler.GetAuditEntries)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/audit"+tc.Query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
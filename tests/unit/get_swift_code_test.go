@@ -9,8 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"swift-codes-api/handlers"
-	"swift-codes-api/internal/config"
 	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
 )
 
 func TestGetSwiftCode(t *testing.T) {
@@ -21,13 +21,14 @@ func TestGetSwiftCode(t *testing.T) {
 			mockRepo := new(mockRepos.SwiftRepository)
 			tc.SetupMocks(mockRepo)
 
-			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
+			handler := handlers.NewSwiftHandler(tc.Config, mockRepo)
 			router := setupRouter(handler)
 
-			response := performRequest(router, tc.SwiftCode)
+			response := performRequest(t, router, tc.SwiftCode)
 
 			assert.Equal(t, tc.ExpectedStatusCode, response.Code)
 			assert.JSONEq(t, tc.ExpectedResponse, response.Body.String())
+			assert.Equal(t, tc.ExpectedLastModified, response.Header().Get("Last-Modified"))
 
 			mockRepo.AssertExpectations(t)
 		})
@@ -36,13 +37,14 @@ func TestGetSwiftCode(t *testing.T) {
 
 func setupRouter(handler *handlers.SwiftCodesHandler) *gin.Engine {
 	router := gin.Default()
-	router.GET("/swift/:swift-code", handler.GetSwiftCode)
+	router.GET("/v1/swift-codes/:swift-code", handler.GetSwiftCode)
 	return router
 }
 
-func performRequest(router *gin.Engine, swiftCode string) *httptest.ResponseRecorder {
-	req := httptest.NewRequest(http.MethodGet, "/swift/"+swiftCode, nil)
+func performRequest(t *testing.T, router *gin.Engine, swiftCode string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/"+swiftCode, nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	spec.ValidateResponse(t, req, w)
 	return w
 }
@@ -8,6 +8,7 @@ import (
 	"swift-codes-api/handlers"
 	"swift-codes-api/internal/config"
 	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
 	"swift-codes-api/tests/unit/test_cases"
 	"testing"
 )
@@ -23,11 +24,12 @@ func TestGetSwiftCodesByCountry(t *testing.T) {
 			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
 
 			router := gin.Default()
-			router.GET("/swift-codes/country/:countryISO2code", handler.GetSwiftCodesByCountry)
+			router.GET("/v1/swift-codes/country/:countryISO2code", handler.GetSwiftCodesByCountry)
 
 			recorder := httptest.NewRecorder()
-			req, _ := http.NewRequest(http.MethodGet, "/swift-codes/country/"+tc.CountryISO2, nil)
+			req, _ := http.NewRequest(http.MethodGet, "/v1/swift-codes/country/"+tc.CountryISO2, nil)
 			router.ServeHTTP(recorder, req)
+			spec.ValidateResponse(t, req, recorder)
 
 			respBody := recorder.Body.String()
 			require.Equal(t, tc.ExpectedStatus, recorder.Code)
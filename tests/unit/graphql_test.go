@@ -0,0 +1,267 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"testing"
+)
+
+func graphQL(t *testing.T, repo *mockRepos.SwiftRepository, query string, variables map[string]interface{}) string {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: repo}, config.Config{})
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	spec.ValidateResponse(t, req, w)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestGraphQL(t *testing.T) {
+	headquarter := models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+		Revision:      2,
+	}
+	branches := []models.SwiftCode{
+		{SwiftCode: "DEUTDE11MUN", SwiftPrefix: "DEUTDE11", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "Germany", Address: "789 Branch St, Munich", Revision: 1},
+		{SwiftCode: "DEUTDE11HAM", SwiftPrefix: "DEUTDE11", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "Germany", Address: "12 Harbour St, Hamburg", Revision: 1},
+	}
+	commerzbank := models.SwiftCode{
+		SwiftCode:     "COBADEFFXXX",
+		SwiftPrefix:   "COBADEFF",
+		BankName:      "Commerzbank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "Kaiserplatz, Frankfurt",
+		IsHeadquarter: true,
+		Revision:      1,
+	}
+	germany := append([]models.SwiftCode{headquarter, commerzbank}, branches...)
+
+	t.Run("lookup returns a bank with selected branch fields", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(&headquarter, nil)
+		repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil)
+
+		body := graphQL(t, repo, `{
+			lookup(code: "DEUTDE11XXX") {
+				bankName
+				country { iso2 name }
+				bank { branches { edges { node { code address } } } }
+			}
+		}`, nil)
+
+		assert.JSONEq(t, `{"data":{"lookup":{
+			"bankName":"Deutsche Bank",
+			"country":{"iso2":"DE","name":"Germany"},
+			"bank":{"branches":{"edges":[
+				{"node":{"code":"DEUTDE11MUN","address":"789 Branch St, Munich"}},
+				{"node":{"code":"DEUTDE11HAM","address":"12 Harbour St, Hamburg"}}
+			]}}
+		}}}`, body)
+	})
+
+	t.Run("lookup of an unknown code is null", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, interfaces.ErrNotFound)
+
+		body := graphQL(t, repo, `{ lookup(code: "ABCDEF12XXX") { code } }`, nil)
+
+		assert.JSONEq(t, `{"data":{"lookup":null}}`, body)
+	})
+
+	t.Run("lookup with an invalid code", func(t *testing.T) {
+		body := graphQL(t, new(mockRepos.SwiftRepository), `{ lookup(code: "INVALID") { code } }`, nil)
+
+		assert.Contains(t, body, `"extensions":{"code":"invalid-swift-code"}`)
+	})
+
+	t.Run("byCountry loads branches of every bank at once", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCountryISO2", mock.Anything, "DE").Return(germany, "Germany", nil)
+
+		body := graphQL(t, repo, `{
+			byCountry(iso2: "de") {
+				name
+				banks { code branches { edges { node { code } } } }
+			}
+		}`, nil)
+
+		assert.JSONEq(t, `{"data":{"byCountry":{"name":"Germany","banks":[
+			{"code":"DEUTDE11XXX","branches":{"edges":[{"node":{"code":"DEUTDE11MUN"}},{"node":{"code":"DEUTDE11HAM"}}]}},
+			{"code":"COBADEFFXXX","branches":{"edges":[]}}
+		]}}}`, body)
+		repo.AssertNumberOfCalls(t, "FindByCountryISO2", 1)
+		repo.AssertNotCalled(t, "FindBranchesByPrefix", mock.Anything, mock.Anything)
+	})
+
+	t.Run("search batches the banks and branches of its matches", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("SearchSwiftCodes", mock.Anything, "bank", "", 21).Return(germany, nil).Once()
+		repo.On("FindByCodes", mock.Anything, []string{"DEUTDE11XXX"}).Return([]models.SwiftCode{headquarter}, nil).Once()
+		repo.On("FindBranchesByPrefixes", mock.Anything, []string{"COBADEFF", "DEUTDE11"}).Return(branches, nil).Once()
+
+		body := graphQL(t, repo, `{
+			search(query: "bank") {
+				edges { node { code bank { branches(first: 1) { pageInfo { hasNextPage } } } } }
+			}
+		}`, nil)
+
+		assert.Contains(t, body, `"code":"DEUTDE11MUN"`)
+		assert.NotContains(t, body, `"errors"`)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "FindByCountryISO2", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "FindByCode", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "FindBranchesByPrefix", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Query nested too deeply", func(t *testing.T) {
+		body := graphQL(t, new(mockRepos.SwiftRepository), `{
+			lookup(code: "DEUTDE11XXX") { bank { headquarter { bank { headquarter { bank { headquarter { bank { headquarter { bank { code } } } } } } } } } }
+		}`, nil)
+
+		assert.Contains(t, body, `"errors"`)
+		assert.Contains(t, body, "exceeds max depth 10")
+	})
+
+	t.Run("Query returning too many records", func(t *testing.T) {
+		manyBranches := []models.SwiftCode{headquarter}
+		for i := range 100 {
+			manyBranches = append(manyBranches, models.SwiftCode{
+				SwiftCode: fmt.Sprintf("DEUTDE11%03d", i), SwiftPrefix: "DEUTDE11", BankName: "Deutsche Bank",
+				CountryISO2: "DE", CountryName: "Germany", Address: "Branch St",
+			})
+		}
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCountryISO2", mock.Anything, "DE").Return(manyBranches, "Germany", nil)
+
+		body := graphQL(t, repo, `{
+			byCountry(iso2: "DE") {
+				swiftCodes(first: 100) { edges { node { bank { branches(first: 100) { edges { node { code } } } } } } }
+			}
+		}`, nil)
+
+		assert.Contains(t, body, `"extensions":{"code":"invalid-parameter"}`)
+		assert.Contains(t, body, "records, ask for smaller pages")
+	})
+
+	t.Run("search pages through matches", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		deutscheBank := append([]models.SwiftCode{headquarter}, branches...)
+		repo.On("SearchSwiftCodes", mock.Anything, "deut", "DE", 2).Return(deutscheBank[:2], nil).Once()
+		repo.On("SearchSwiftCodes", mock.Anything, "deut", "DE", 3).Return(deutscheBank, nil).Once()
+
+		query := `query($after: String) {
+			search(query: "deut", country: "DE", first: 1, after: $after) {
+				edges { node { code } }
+				pageInfo { hasNextPage endCursor }
+			}
+		}`
+		var first struct {
+			Data struct {
+				Search struct {
+					PageInfo struct {
+						HasNextPage bool
+						EndCursor   string
+					}
+				}
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(graphQL(t, repo, query, nil)), &first))
+		assert.True(t, first.Data.Search.PageInfo.HasNextPage)
+
+		body := graphQL(t, repo, query, map[string]interface{}{"after": first.Data.Search.PageInfo.EndCursor})
+		assert.Contains(t, body, `"edges":[{"node":{"code":"DEUTDE11MUN"}}]`)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("addSwiftCode reports every invalid field", func(t *testing.T) {
+		body := graphQL(t, new(mockRepos.SwiftRepository), `mutation {
+			addSwiftCode(input: {code: "ABCDJP12XXX", bankName: "", address: "", countryISO2: "US", countryName: ""}) { code }
+		}`, nil)
+
+		assert.Contains(t, body, `"code":"validation-failed"`)
+		assert.Contains(t, body, `{"field":"countryISO2","code":"mismatch","message":"Must match characters 5-6 of the SWIFT code"}`)
+	})
+
+	t.Run("addSwiftCode returns the stored record", func(t *testing.T) {
+		added := branches[0]
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("AddSwiftCode", mock.Anything, mock.MatchedBy(func(sc models.SwiftCode) bool {
+			return sc.SwiftCode == "DEUTDE11MUN" && sc.CountryISO2 == "DE" && !sc.IsHeadquarter
+		})).Return(nil)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&added, nil)
+
+		body := graphQL(t, repo, `mutation($input: SwiftCodeInput!) { addSwiftCode(input: $input) { code revision } }`, map[string]interface{}{
+			"input": map[string]interface{}{
+				"code":        "DEUTDE11MUN",
+				"bankName":    "Deutsche Bank",
+				"address":     "789 Branch St, Munich",
+				"countryISO2": "de",
+				"countryName": "Germany",
+			},
+		})
+
+		assert.JSONEq(t, `{"data":{"addSwiftCode":{"code":"DEUTDE11MUN","revision":1}}}`, body)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("deleteSwiftCode without a revision", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", interfaces.AnyRevision).Return(nil)
+
+		body := graphQL(t, repo, `mutation { deleteSwiftCode(code: "DEUTDE11XXX") }`, nil)
+
+		assert.JSONEq(t, `{"data":{"deleteSwiftCode":true}}`, body)
+	})
+
+	t.Run("deleteSwiftCode at a stale revision", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", int64(1)).Return(interfaces.ErrRevisionMismatch)
+
+		body := graphQL(t, repo, `mutation { deleteSwiftCode(code: "DEUTDE11XXX", expectedRevision: 1) }`, nil)
+
+		assert.Contains(t, body, `"extensions":{"code":"revision-mismatch"}`)
+	})
+
+	t.Run("Request without a query", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: new(mockRepos.SwiftRepository)}, config.Config{})
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"malformed-request"`)
+	})
+}
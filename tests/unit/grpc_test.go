@@ -0,0 +1,294 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	swiftcodesv1 "swift-codes-api/api/proto/swiftcodes/v1"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/grpcapi"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/problems"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"testing"
+	"time"
+)
+
+func newGRPCClient(t *testing.T, repo *mockRepos.SwiftRepository) swiftcodesv1.SwiftCodesClient {
+	return serveGRPC(t, grpcapi.NewServer(config.Config{
+		APIKeys: map[string]config.APIKey{"alice-key": {Actor: "alice"}},
+	}, repo, nil))
+}
+
+func serveGRPC(t *testing.T, server *grpc.Server) swiftcodesv1.SwiftCodesClient {
+	lis := bufconn.Listen(1 << 20)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return swiftcodesv1.NewSwiftCodesClient(conn)
+}
+
+// problemCode returns the problem code carried by a gRPC error.
+func problemCode(t *testing.T, err error) problems.Code {
+	st, ok := status.FromError(err)
+	require.True(t, ok, "not a gRPC status: %v", err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return problems.Code(info.Reason)
+		}
+	}
+	return ""
+}
+
+func TestGRPCServer(t *testing.T) {
+	ctx := context.Background()
+	headquarter := &models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+		Revision:      2,
+	}
+	branch := models.SwiftCode{
+		SwiftCode:   "DEUTDE11MUN",
+		SwiftPrefix: "DEUTDE11",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "789 Branch St, Munich",
+	}
+
+	t.Run("GetSwiftCode returns a headquarter with its branches", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+		repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return([]models.SwiftCode{branch}, nil)
+
+		resp, err := newGRPCClient(t, repo).GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "DEUTDE11XXX"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Deutsche Bank", resp.GetSwiftCode().GetBankName())
+		assert.Equal(t, int64(2), resp.GetSwiftCode().GetRevision())
+		require.Len(t, resp.GetBranches(), 1)
+		assert.Equal(t, "DEUTDE11MUN", resp.GetBranches()[0].GetSwiftCode())
+	})
+
+	t.Run("GetSwiftCode with an invalid code", func(t *testing.T) {
+		_, err := newGRPCClient(t, new(mockRepos.SwiftRepository)).GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "INVALID"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, problems.InvalidSwiftCode, problemCode(t, err))
+	})
+
+	t.Run("GetSwiftCode of an unknown code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, interfaces.ErrNotFound)
+
+		_, err := newGRPCClient(t, repo).GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "ABCDEF12XXX"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, problems.SwiftCodeNotFound, problemCode(t, err))
+	})
+
+	t.Run("GetSwiftCode when the repository fails", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, errors.New("database error"))
+
+		_, err := newGRPCClient(t, repo).GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "ABCDEF12XXX"})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("ListByCountry normalises the country code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCountryISO2", mock.Anything, "DE").Return([]models.SwiftCode{*headquarter, branch}, "Germany", nil)
+
+		resp, err := newGRPCClient(t, repo).ListByCountry(ctx, &swiftcodesv1.ListByCountryRequest{CountryIso2: "de"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "DE", resp.GetCountryIso2())
+		assert.Equal(t, "Germany", resp.GetCountryName())
+		assert.Len(t, resp.GetSwiftCodes(), 2)
+	})
+
+	t.Run("ListByCountry of a country without banks", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCountryISO2", mock.Anything, "ZZ").Return(nil, "", interfaces.ErrNotFound)
+
+		_, err := newGRPCClient(t, repo).ListByCountry(ctx, &swiftcodesv1.ListByCountryRequest{CountryIso2: "ZZ"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, problems.CountryNotFound, problemCode(t, err))
+	})
+
+	t.Run("StreamSwiftCodes sends every SWIFT code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("StreamSwiftCodes", mock.Anything, "DE").Return([]models.SwiftCode{*headquarter, branch}, nil)
+
+		stream, err := newGRPCClient(t, repo).StreamSwiftCodes(ctx, &swiftcodesv1.StreamSwiftCodesRequest{CountryIso2: "de"})
+		require.NoError(t, err)
+
+		var received []string
+		for {
+			swiftCode, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			received = append(received, swiftCode.GetSwiftCode())
+		}
+		assert.Equal(t, []string{"DEUTDE11XXX", "DEUTDE11MUN"}, received)
+	})
+
+	t.Run("AddSwiftCode records the caller", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("AddSwiftCode", mock.MatchedBy(func(ctx context.Context) bool {
+			return reqctx.Actor(ctx) == "alice" && reqctx.RequestID(ctx) == "req-1"
+		}), mock.MatchedBy(func(sc models.SwiftCode) bool {
+			return sc.SwiftCode == "DEUTDE11MUN" && sc.CountryISO2 == "DE"
+		})).Return(nil)
+
+		ctx := metadata.AppendToOutgoingContext(ctx, "x-api-key", "alice-key", "x-request-id", "req-1")
+		_, err := newGRPCClient(t, repo).AddSwiftCode(ctx, &swiftcodesv1.AddSwiftCodeRequest{SwiftCode: &swiftcodesv1.SwiftCode{
+			SwiftCode:   "DEUTDE11MUN",
+			BankName:    "Deutsche Bank",
+			Address:     "789 Branch St, Munich",
+			CountryIso2: "de",
+			CountryName: "Germany",
+		}})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("AddSwiftCode reports every invalid field", func(t *testing.T) {
+		_, err := newGRPCClient(t, new(mockRepos.SwiftRepository)).AddSwiftCode(ctx, &swiftcodesv1.AddSwiftCodeRequest{SwiftCode: &swiftcodesv1.SwiftCode{
+			SwiftCode:   "ABCDJP12XXX",
+			CountryIso2: "US",
+		}})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, problems.ValidationFailed, problemCode(t, err))
+
+		var violations []string
+		for _, detail := range st.Details() {
+			if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+				for _, v := range badRequest.GetFieldViolations() {
+					violations = append(violations, v.GetField()+":"+v.GetReason())
+				}
+			}
+		}
+		assert.Equal(t, []string{"bankName:required", "address:required", "countryName:required", "countryISO2:mismatch"}, violations)
+	})
+
+	t.Run("AddSwiftCode of an existing code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(fmt.Errorf("SWIFT code DEUTDE11MUN %w", interfaces.ErrAlreadyExists))
+
+		_, err := newGRPCClient(t, repo).AddSwiftCode(ctx, &swiftcodesv1.AddSwiftCodeRequest{SwiftCode: &swiftcodesv1.SwiftCode{
+			SwiftCode:   "DEUTDE11MUN",
+			BankName:    "Deutsche Bank",
+			Address:     "789 Branch St, Munich",
+			CountryIso2: "DE",
+			CountryName: "Germany",
+		}})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		assert.Equal(t, problems.SwiftCodeExists, problemCode(t, err))
+	})
+
+	t.Run("A panicking handler fails the call", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Run(func(mock.Arguments) { panic("boom") })
+
+		client := newGRPCClient(t, repo)
+		_, err := client.GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "DEUTDE11XXX"})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, problems.Internal, problemCode(t, err))
+
+		repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&branch, nil)
+		_, err = client.GetSwiftCode(ctx, &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "DEUTDE11MUN"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("DeleteSwiftCode without a revision", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", interfaces.AnyRevision).Return(nil)
+
+		_, err := newGRPCClient(t, repo).DeleteSwiftCode(ctx, &swiftcodesv1.DeleteSwiftCodeRequest{SwiftCode: "DEUTDE11XXX"})
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteSwiftCode at a stale revision", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", int64(1)).Return(interfaces.ErrRevisionMismatch)
+
+		revision := int64(1)
+		_, err := newGRPCClient(t, repo).DeleteSwiftCode(ctx, &swiftcodesv1.DeleteSwiftCodeRequest{SwiftCode: "DEUTDE11XXX", ExpectedRevision: &revision})
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+		assert.Equal(t, problems.RevisionMismatch, problemCode(t, err))
+	})
+
+	t.Run("DeleteSwiftCode of an unknown code", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("DeleteSwiftCode", mock.Anything, "ABCDEF12XXX", interfaces.AnyRevision).Return(interfaces.ErrNotFound)
+
+		_, err := newGRPCClient(t, repo).DeleteSwiftCode(ctx, &swiftcodesv1.DeleteSwiftCodeRequest{SwiftCode: "ABCDEF12XXX"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Calls over the rate limit are rejected", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&branch, nil)
+		repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11MUN", interfaces.AnyRevision).Return(nil)
+		client := serveGRPC(t, grpcapi.NewServer(config.Config{
+			RateLimitRead:  ratelimit.Limit{Requests: 1, Window: time.Minute},
+			RateLimitWrite: ratelimit.Limit{Requests: 1, Window: time.Minute},
+		}, repo, ratelimit.NewMemoryStore()))
+
+		request := &swiftcodesv1.GetSwiftCodeRequest{SwiftCode: "DEUTDE11MUN"}
+		_, err := client.GetSwiftCode(ctx, request)
+		require.NoError(t, err)
+
+		var header metadata.MD
+		_, err = client.GetSwiftCode(ctx, request, grpc.Header(&header))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, problems.RateLimited, problemCode(t, err))
+		assert.Equal(t, []string{"60"}, header.Get("retry-after"))
+
+		// Writes have a budget of their own.
+		_, err = client.DeleteSwiftCode(ctx, &swiftcodesv1.DeleteSwiftCodeRequest{SwiftCode: "DEUTDE11MUN"})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,373 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/importer"
+	"swift-codes-api/middleware"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"testing"
+)
+
+const importCSV = "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+	"DEUTDEFFXXX,Deutsche Bank,Taunusanlage 12,DE,GERMANY,true\n" +
+	"DEUTDEFF500,Deutsche Bank,Frankfurt,DE,GERMANY,false\n" +
+	"COBADEFFXXX,Commerzbank,Kaiserplatz,DE,GERMANY,true\n" +
+	"NOT-A-BIC,Nobody,Nowhere,DE,GERMANY,false\n" +
+	"BNPAFRPPXXX,BNP Paribas,Paris,FR,FRANCE,maybe\n"
+
+// runImport runs job to the end and returns it as last stored, along with the
+// rows processed at each checkpoint. With cancel set, the job is cancelled at
+// its first checkpoint.
+func runImport(t *testing.T, job models.ImportJob, file string, swiftRepo *mockRepos.SwiftRepository, cancel bool) (models.ImportJob, []int) {
+	jobs := new(mockRepos.ImportRepository)
+	jobs.On("ClaimJob", mock.Anything, mock.Anything, mock.Anything).Return(&job, nil).Once()
+	jobs.On("OpenFile", mock.Anything, job.ID).Return(io.NopCloser(strings.NewReader(file)), nil)
+	jobs.On("DeleteFile", mock.Anything, job.ID).Return(nil)
+
+	var final models.ImportJob
+	var checkpoints []int
+	jobs.On("UpdateJob", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		final = args.Get(1).(models.ImportJob)
+		if !final.Finished() {
+			checkpoints = append(checkpoints, final.Processed)
+		}
+	}).Return(&models.ImportJob{CancelRequested: cancel}, nil)
+
+	ran, err := importer.NewRunner(jobs, swiftRepo, 1).RunNext(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ran)
+	jobs.AssertExpectations(t)
+	return final, checkpoints
+}
+
+func TestImportRunner(t *testing.T) {
+	headquarter := models.SwiftCode{SwiftCode: "DEUTDEFFXXX", BankName: "Deutsche Bank", Address: "Taunusanlage 12", CountryISO2: "DE", CountryName: "GERMANY", IsHeadquarter: true}
+	branch := models.SwiftCode{SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", Address: "Old address", CountryISO2: "DE", CountryName: "GERMANY"}
+
+	t.Run("Rows are created, updated or left alone and row errors are reported", func(t *testing.T) {
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, []string{"DEUTDEFFXXX", "DEUTDEFF500", "COBADEFFXXX"}).
+			Return([]models.SwiftCode{headquarter, branch}, nil).Once()
+		swiftRepo.On("UpdateSwiftCode", mock.Anything, mock.MatchedBy(func(s models.SwiftCode) bool {
+			return s.SwiftCode == "DEUTDEFF500" && s.Address == "Frankfurt"
+		}), interfaces.AnyRevision).Return(nil).Once()
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.MatchedBy(func(s models.SwiftCode) bool {
+			return s.SwiftCode == "COBADEFFXXX" && s.IsHeadquarter
+		})).Return(nil).Once()
+
+		job, _ := runImport(t, models.ImportJob{ID: "job-1", Format: "csv", Status: models.ImportStatusRunning}, importCSV, swiftRepo, false)
+
+		assert.Equal(t, models.ImportStatusSucceeded, job.Status)
+		assert.NotNil(t, job.FinishedAt)
+		assert.Equal(t, 5, job.Processed)
+		assert.Equal(t, 1, job.Created)
+		assert.Equal(t, 1, job.Updated)
+		assert.Equal(t, 1, job.Unchanged)
+		assert.Equal(t, 2, job.Failed)
+		assert.Equal(t, int64(len(importCSV)), job.BytesRead)
+		require.Len(t, job.Errors, 2)
+		assert.Equal(t, models.ImportRowError{Row: 4, SwiftCode: "NOT-A-BIC", Message: "swiftCode: SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code"}, job.Errors[0])
+		assert.Equal(t, models.ImportRowError{Row: 5, Message: "isHeadquarter must be true or false"}, job.Errors[1])
+		swiftRepo.AssertExpectations(t)
+	})
+
+	t.Run("Cells quoted by the CSV export are imported as they were", func(t *testing.T) {
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, []string{"DEUTDEFFXXX"}).Return([]models.SwiftCode{}, nil).Once()
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.MatchedBy(func(s models.SwiftCode) bool {
+			return s.BankName == "=Deutsche Bank" && s.Address == "'Taunusanlage 12"
+		})).Return(nil).Once()
+
+		file := "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+			"DEUTDEFFXXX,'=Deutsche Bank,'Taunusanlage 12,DE,GERMANY,true\n"
+		job, _ := runImport(t, models.ImportJob{ID: "job-7", Format: "csv"}, file, swiftRepo, false)
+
+		assert.Equal(t, models.ImportStatusSucceeded, job.Status)
+		assert.Equal(t, 1, job.Created)
+		swiftRepo.AssertExpectations(t)
+	})
+
+	t.Run("A resumed job skips the rows of its last checkpoint", func(t *testing.T) {
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, []string{"COBADEFFXXX"}).Return([]models.SwiftCode{}, nil).Once()
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(nil).Once()
+
+		resumed := models.ImportJob{ID: "job-2", Format: "csv", Status: models.ImportStatusRunning, Processed: 2, Created: 2}
+		job, _ := runImport(t, resumed, importCSV, swiftRepo, false)
+
+		assert.Equal(t, models.ImportStatusSucceeded, job.Status)
+		assert.Equal(t, 5, job.Processed)
+		assert.Equal(t, 3, job.Created)
+		assert.Equal(t, 2, job.Failed)
+		swiftRepo.AssertExpectations(t)
+	})
+
+	t.Run("Large files are applied in batches with a checkpoint after each", func(t *testing.T) {
+		var file strings.Builder
+		file.WriteString("[")
+		for i := 0; i < 1200; i++ {
+			if i > 0 {
+				file.WriteString(",")
+			}
+			fmt.Fprintf(&file, `{"swiftCode":"BANKDE%02d%03d","bankName":"Bank","address":"Street","countryISO2":"DE","countryName":"GERMANY"}`, i/100, i%100)
+		}
+		file.WriteString("]")
+
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{}, nil).Times(3)
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(nil).Times(1200)
+
+		job, checkpoints := runImport(t, models.ImportJob{ID: "job-3", Format: "json"}, file.String(), swiftRepo, false)
+
+		assert.Equal(t, []int{500, 1000, 1200}, checkpoints)
+		assert.Equal(t, models.ImportStatusSucceeded, job.Status)
+		assert.Equal(t, 1200, job.Created)
+		swiftRepo.AssertExpectations(t)
+	})
+
+	t.Run("A code created since its batch was looked up is updated", func(t *testing.T) {
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{}, nil).Once()
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(fmt.Errorf("SWIFT code DEUTDEFFXXX %w", interfaces.ErrAlreadyExists)).Once()
+		swiftRepo.On("UpdateSwiftCode", mock.Anything, mock.Anything, interfaces.AnyRevision).Return(nil).Once()
+
+		job, _ := runImport(t, models.ImportJob{ID: "job-6", Format: "csv"}, strings.Join(strings.Split(importCSV, "\n")[:2], "\n"), swiftRepo, false)
+
+		assert.Equal(t, models.ImportStatusSucceeded, job.Status)
+		assert.Equal(t, 1, job.Updated)
+		assert.Equal(t, 0, job.Created)
+		swiftRepo.AssertExpectations(t)
+	})
+
+	t.Run("A cancelled job stops at its next checkpoint", func(t *testing.T) {
+		var file strings.Builder
+		for i := 0; i < 1000; i++ {
+			fmt.Fprintf(&file, `{"swiftCode":"BANKDE%03d","bankName":"Bank","address":"Street","countryISO2":"DE","countryName":"GERMANY"}`+"\n", i)
+		}
+
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{}, nil)
+
+		job, _ := runImport(t, models.ImportJob{ID: "job-4", Format: "ndjson"}, file.String(), swiftRepo, true)
+
+		assert.Equal(t, models.ImportStatusCancelled, job.Status)
+		assert.Equal(t, 500, job.Processed)
+		assert.Equal(t, 500, job.Failed)
+		swiftRepo.AssertNotCalled(t, "AddSwiftCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("An unreadable file fails the job", func(t *testing.T) {
+		job, _ := runImport(t, models.ImportJob{ID: "job-5", Format: "json"}, `{"swiftCode":"DEUTDEFFXXX"}`, new(mockRepos.SwiftRepository), false)
+
+		assert.Equal(t, models.ImportStatusFailed, job.Status)
+		assert.Equal(t, "a JSON file must hold an array of SWIFT codes", job.Error)
+	})
+
+	t.Run("A job taken over by another worker is left to it", func(t *testing.T) {
+		job := models.ImportJob{ID: "job-7", Format: "csv", Status: models.ImportStatusRunning, Claims: 1}
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("ClaimJob", mock.Anything, mock.Anything, mock.Anything).Return(&job, nil).Once()
+		jobs.On("OpenFile", mock.Anything, job.ID).Return(io.NopCloser(strings.NewReader(importCSV)), nil)
+		jobs.On("UpdateJob", mock.Anything, mock.MatchedBy(func(j models.ImportJob) bool {
+			return j.Claims == 1
+		})).Return(nil, interfaces.ErrJobReclaimed).Once()
+
+		swiftRepo := new(mockRepos.SwiftRepository)
+		swiftRepo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{headquarter, branch}, nil)
+		swiftRepo.On("UpdateSwiftCode", mock.Anything, mock.Anything, interfaces.AnyRevision).Return(nil)
+		swiftRepo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(nil)
+
+		ran, err := importer.NewRunner(jobs, swiftRepo, 1).RunNext(context.Background())
+		require.NoError(t, err)
+		assert.True(t, ran)
+		jobs.AssertExpectations(t)
+		jobs.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything)
+	})
+}
+
+func importRouter(jobs *mockRepos.ImportRepository, maxSize int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Config{
+		APIKeys:       map[string]config.APIKey{"admin-key": {Actor: "ops", Admin: true}, "user-key": {Actor: "alice"}},
+		ImportMaxSize: maxSize,
+	}
+	handler := handlers.NewImportsHandler(cfg, importer.NewRunner(jobs, new(mockRepos.SwiftRepository), 1))
+
+	router := gin.New()
+	router.Use(middleware.Authenticate(cfg))
+	router.POST("/v1/imports", handler.CreateImport)
+	router.GET("/v1/imports/:id", handler.GetImport)
+	router.POST("/v1/imports/:id/cancel", handler.CancelImport)
+	return router
+}
+
+func multipartImport(t *testing.T, fileName, content string) (string, *bytes.Buffer) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(content))
+	require.NoError(t, form.Close())
+	return form.FormDataContentType(), &body
+}
+
+func TestImportsHandler(t *testing.T) {
+	serve := func(router *gin.Engine, method, path, apiKey, contentType string, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, body)
+		req.Header.Set(middleware.APIKeyHeader, apiKey)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		return w
+	}
+
+	t.Run("An uploaded file is stored and queued", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "directory.csv", mock.Anything).Run(func(args mock.Arguments) {
+			content, err := io.ReadAll(args.Get(3).(io.Reader))
+			assert.NoError(t, err)
+			assert.Equal(t, importCSV, string(content))
+		}).Return(int64(len(importCSV)), nil).Once()
+		jobs.On("CreateJob", mock.Anything, mock.MatchedBy(func(job models.ImportJob) bool {
+			return job.Status == models.ImportStatusQueued && job.Format == "csv" && job.Actor == "ops" && job.Size == int64(len(importCSV))
+		})).Return(nil).Once()
+
+		contentType, body := multipartImport(t, "directory.csv", importCSV)
+		w := serve(importRouter(jobs, 0), http.MethodPost, "/v1/imports", "admin-key", contentType, body)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Regexp(t, `^/v1/imports/[0-9a-f]{32}$`, w.Header().Get("Location"))
+		assert.Contains(t, w.Body.String(), `"status":"queued"`)
+		assert.Contains(t, w.Body.String(), `"fileName":"directory.csv"`)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("The format of a raw body comes from its media type", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "import", mock.Anything).Return(int64(2), nil).Once()
+		jobs.On("CreateJob", mock.Anything, mock.MatchedBy(func(job models.ImportJob) bool {
+			return job.Format == "ndjson"
+		})).Return(nil).Once()
+
+		w := serve(importRouter(jobs, 0), http.MethodPost, "/v1/imports", "admin-key", "application/x-ndjson", strings.NewReader("{}"))
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Only administrators may import", func(t *testing.T) {
+		contentType, body := multipartImport(t, "directory.csv", importCSV)
+		w := serve(importRouter(new(mockRepos.ImportRepository), 0), http.MethodPost, "/v1/imports", "user-key", contentType, body)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+	})
+
+	t.Run("A file of unknown format is rejected", func(t *testing.T) {
+		contentType, body := multipartImport(t, "directory.xlsx", "PK")
+		w := serve(importRouter(new(mockRepos.ImportRepository), 0), http.MethodPost, "/v1/imports", "admin-key", contentType, body)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"invalid-parameter"`)
+	})
+
+	t.Run("Files above the size limit are rejected", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "import", mock.Anything).Run(func(args mock.Arguments) {
+			_, err := io.Copy(io.Discard, args.Get(3).(io.Reader))
+			var tooLarge *http.MaxBytesError
+			assert.ErrorAs(t, err, &tooLarge)
+		}).Return(int64(0), &http.MaxBytesError{Limit: 16}).Once()
+
+		w := serve(importRouter(jobs, 16), http.MethodPost, "/v1/imports", "admin-key", "text/csv", strings.NewReader(importCSV))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), `"detail":"Files of more than 16 bytes cannot be imported"`)
+		jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Progress of a job", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("FindJob", mock.Anything, "job-1").Return(&models.ImportJob{
+			ID: "job-1", Status: models.ImportStatusRunning, Format: "csv", Processed: 500, Created: 498, Failed: 2,
+			Errors: []models.ImportRowError{{Row: 7, SwiftCode: "BAD", Message: "swiftCode: invalid"}},
+		}, nil)
+		jobs.On("FindJob", mock.Anything, "missing").Return(nil, interfaces.ErrNotFound)
+		router := importRouter(jobs, 0)
+
+		w := serve(router, http.MethodGet, "/v1/imports/job-1", "admin-key", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"processed":500,"created":498`)
+		assert.Contains(t, w.Body.String(), `"errors":[{"row":7,"swiftCode":"BAD","message":"swiftCode: invalid"}]`)
+
+		w = serve(router, http.MethodGet, "/v1/imports/missing", "admin-key", "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"import-not-found"`)
+
+		// Row errors quote the imported file, which only administrators may
+		// read.
+		w = serve(router, http.MethodGet, "/v1/imports/job-1", "user-key", "", nil)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"forbidden"`)
+	})
+
+	t.Run("An unavailable database is reported as such", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("FindJob", mock.Anything, "job-1").Return(nil, fmt.Errorf("find import job: %w", interfaces.ErrUnavailable))
+		jobs.On("SaveFile", mock.Anything, mock.Anything, "import", mock.Anything).Return(int64(0), interfaces.ErrUnavailable)
+		router := importRouter(jobs, 0)
+
+		w := serve(router, http.MethodGet, "/v1/imports/job-1", "admin-key", "", nil)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"service-unavailable"`)
+
+		w = serve(router, http.MethodPost, "/v1/imports", "admin-key", "text/csv", strings.NewReader(importCSV))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("Cancelling a job", func(t *testing.T) {
+		jobs := new(mockRepos.ImportRepository)
+		jobs.On("CancelJob", mock.Anything, "running", mock.Anything).
+			Return(&models.ImportJob{ID: "running", Format: "csv", Errors: []models.ImportRowError{}, Status: models.ImportStatusRunning, CancelRequested: true}, nil)
+		jobs.On("CancelJob", mock.Anything, "done", mock.Anything).
+			Return(&models.ImportJob{ID: "done", Status: models.ImportStatusSucceeded}, nil)
+		jobs.On("CancelJob", mock.Anything, "queued", mock.Anything).
+			Return(&models.ImportJob{ID: "queued", Format: "csv", Errors: []models.ImportRowError{}, Status: models.ImportStatusCancelled, CancelRequested: true}, nil)
+		jobs.On("DeleteFile", mock.Anything, "queued").Return(nil).Once()
+		router := importRouter(jobs, 0)
+
+		w := serve(router, http.MethodPost, "/v1/imports/running/cancel", "admin-key", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"cancelRequested":true`)
+
+		w = serve(router, http.MethodPost, "/v1/imports/done/cancel", "admin-key", "", nil)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), `"detail":"Import done has already succeeded"`)
+
+		// The file of a job cancelled before any worker claimed it is deleted
+		// right away.
+		w = serve(router, http.MethodPost, "/v1/imports/queued/cancel", "admin-key", "", nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		jobs.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"swift-codes-api/api"
+	"swift-codes-api/internal/config"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"testing"
+)
+
+func TestOpenAPISpec(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: new(mockRepos.SwiftRepository)}, config.Config{SwaggerUI: true})
+
+	t.Run("Served as JSON", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, api.Spec, w.Body.Bytes())
+	})
+
+	t.Run("Swagger UI", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+	})
+
+	t.Run("Every SWIFT code route is described", func(t *testing.T) {
+		var doc struct {
+			Paths map[string]map[string]json.RawMessage `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(api.Spec, &doc))
+
+		param := regexp.MustCompile(`:([^/]+)`)
+		for _, route := range router.Routes() {
+			if !strings.Contains(route.Path, "/swift-codes") && route.Path != "/v1/export" {
+				continue
+			}
+
+			path := param.ReplaceAllString(route.Path, "{$1}")
+			_, ok := doc.Paths[path][strings.ToLower(route.Method)]
+			assert.True(t, ok, "%s %s is missing from the OpenAPI document", route.Method, path)
+		}
+	})
+}
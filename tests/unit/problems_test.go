@@ -0,0 +1,45 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/problems"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"testing"
+)
+
+func TestProblemResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: new(mockRepos.SwiftRepository)}, config.Config{})
+
+	t.Run("Unknown route", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v2/swift-codes", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateProblem(t, w)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, problems.ContentType, w.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"type":"urn:swift-codes-api:problem:route-not-found","title":"Not found","status":404,"detail":"No route matches GET /v2/swift-codes","instance":"/v2/swift-codes","code":"route-not-found","requestId":"req-1"}`, w.Body.String())
+	})
+
+	t.Run("Request ID is echoed in handler errors", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/v1/swift-codes/INVALID", nil)
+		req.Header.Set("X-Request-ID", "req-2")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"invalid-swift-code"`)
+		assert.Contains(t, w.Body.String(), `"requestId":"req-2"`)
+	})
+}
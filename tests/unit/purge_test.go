@@ -0,0 +1,37 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"swift-codes-api/internal/purge"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"testing"
+	"time"
+)
+
+func TestPurgeRun(t *testing.T) {
+	retention := 30 * 24 * time.Hour
+
+	t.Run("Purges records deleted before the retention period", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("PurgeDeletedSwiftCodes", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+			expected := time.Now().UTC().Add(-retention)
+			return cutoff.Sub(expected).Abs() < time.Minute
+		})).Return([]models.SwiftCode{{SwiftCode: "ABCDUS12XXX"}}, nil)
+
+		purge.Run(context.Background(), mockRepo, retention)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Repository error is tolerated", func(t *testing.T) {
+		mockRepo := new(mockRepos.SwiftRepository)
+		mockRepo.On("PurgeDeletedSwiftCodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		purge.Run(context.Background(), mockRepo, retention)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/ratelimit"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("600/1m")
+	require.NoError(t, err)
+	assert.Equal(t, ratelimit.Limit{Requests: 600, Window: time.Minute}, limit)
+
+	limit, err = ratelimit.ParseLimit("off")
+	require.NoError(t, err)
+	assert.False(t, limit.Enabled())
+
+	for _, invalid := range []string{"600", "x/1m", "600/x", "600/0s", "-1/1m"} {
+		_, err := ratelimit.ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := ratelimit.NewMemoryStore()
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first, _ := store.Take(ctx, "a", limit, now)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.Equal(t, 30*time.Second, first.Reset)
+
+	second, _ := store.Take(ctx, "a", limit, now)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+
+	third, _ := store.Take(ctx, "a", limit, now)
+	assert.False(t, third.Allowed)
+	assert.Equal(t, 30*time.Second, third.RetryAfter)
+
+	other, _ := store.Take(ctx, "b", limit, now)
+	assert.True(t, other.Allowed, "buckets are per key")
+
+	refilled, _ := store.Take(ctx, "a", limit, now.Add(30*time.Second))
+	assert.True(t, refilled.Allowed)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := new(mockRepos.SwiftRepository)
+	repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&models.SwiftCode{
+		SwiftCode:   "DEUTDE11MUN",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "789 Branch St, Munich",
+	}, nil)
+
+	router := gin.New()
+	routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: repo}, config.Config{
+		APIKeys:        map[string]config.APIKey{"alice-key": {Actor: "alice"}},
+		RateLimitRead:  ratelimit.Limit{Requests: 2, Window: time.Minute},
+		RateLimitWrite: ratelimit.Limit{Requests: 1, Window: time.Minute},
+	})
+
+	send := func(method, apiKey string) *httptest.ResponseRecorder {
+		var req *http.Request
+		if method == http.MethodGet {
+			req = httptest.NewRequest(method, "/v1/swift-codes/DEUTDE11MUN", nil)
+		} else {
+			req = httptest.NewRequest(method, "/v1/swift-codes", strings.NewReader(`{}`))
+		}
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		return w
+	}
+
+	w := send(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "").Code)
+
+	w = send(http.MethodGet, "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Contains(t, w.Body.String(), `"code":"rate-limited"`)
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "alice-key").Code, "API keys have their own budget")
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodGet, "unknown-key").Code, "unknown keys share the IP's budget")
+
+	assert.Equal(t, http.StatusBadRequest, send(http.MethodPost, "").Code, "writes have their own budget")
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodPost, "").Code)
+
+	// Lookups sent as POST draw on the read budget, which is spent.
+	req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes/lookup", strings.NewReader(`{"codes":["DEUTDE11MUN"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	spec.ValidateResponse(t, req, w)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+}
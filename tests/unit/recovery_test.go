@@ -0,0 +1,35 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/middleware"
+	"testing"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.Recovery(log.New(io.Discard, "", 0)))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/abort", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	t.Run("A panicking handler answers with an internal error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"internal-error"`)
+	})
+
+	t.Run("Aborting handlers are left to net/http", func(t *testing.T) {
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+		})
+	})
+}
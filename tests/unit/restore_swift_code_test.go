@@ -0,0 +1,39 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestRestoreSwiftCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetRestoreSwiftCodeTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
+			router := gin.Default()
+			router.POST("/v1/swift-codes/:swift-code/restore", handler.RestoreSwiftCode)
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/swift-codes/"+tc.SwiftCode+"/restore", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
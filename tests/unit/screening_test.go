@@ -0,0 +1,171 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/screening"
+	"swift-codes-api/middleware"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"testing"
+)
+
+func screen(t *testing.T, repo *mockRepos.SwiftRepository, query, contentType string, body *bytes.Buffer) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	handler := handlers.NewSwiftHandler(config.Config{}, repo)
+	router := gin.New()
+	router.POST("/v1/screenings", handler.ScreenBeneficiaries)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/screenings"+query, body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	spec.ValidateResponse(t, req, w)
+	return w
+}
+
+func multipartCSV(t *testing.T, content string) (string, *bytes.Buffer) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "beneficiaries.csv")
+	require.NoError(t, err)
+	_, _ = part.Write([]byte(content))
+	require.NoError(t, form.Close())
+	return form.FormDataContentType(), &body
+}
+
+func TestScreenBeneficiaries(t *testing.T) {
+	headquarter := models.SwiftCode{SwiftCode: "DEUTDEFFXXX", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "Germany", IsHeadquarter: true}
+	branch := models.SwiftCode{SwiftCode: "DEUTDEFF500", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "Germany"}
+
+	t.Run("Rows are annotated with the screening result", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFF500", "DEUTDEFFXXX", "DEUTDEFF501", "ABCDQQ12XXX"}).
+			Return([]models.SwiftCode{headquarter, branch}, nil).Once()
+
+		contentType, body := multipartCSV(t, "\ufeffName;Swift Code\n"+
+			"Alice;deutdeff500\n"+
+			"Bob;DEUTDEFF501\n"+
+			"Carol;DEUTDEFF\n"+
+			"Dave;NOT-A-BIC\n"+
+			"Eve;\n"+
+			"Frank\n"+
+			"Grace;ABCDQQ12\n")
+
+		w := screen(t, repo, "?column=swift+code&delimiter=%3B", contentType, body)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="screened-beneficiaries.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "Name;Swift Code;bicValid;bicBankName;bicCountry;bicHeadquarter;bicReason\n"+
+			"Alice;deutdeff500;true;Deutsche Bank;Germany;DEUTDEFFXXX;\n"+
+			"Bob;DEUTDEFF501;false;;;DEUTDEFFXXX;swift-code-not-found\n"+
+			"Carol;DEUTDEFF;true;Deutsche Bank;Germany;DEUTDEFFXXX;\n"+
+			"Dave;NOT-A-BIC;false;;;;invalid-swift-code\n"+
+			"Eve;;false;;;;missing\n"+
+			"Frank;false;;;;missing\n"+
+			"Grace;ABCDQQ12;false;;;;unknown-country\n", w.Body.String())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Directory values that look like formulas are escaped", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFF500", "DEUTDEFFXXX"}).
+			Return([]models.SwiftCode{{SwiftCode: "DEUTDEFF500", BankName: "=HYPERLINK(\"http://x\")", CountryISO2: "DE", CountryName: "@Germany"}}, nil).Once()
+
+		w := screen(t, repo, "", "text/csv", bytes.NewBufferString("bic\nDEUTDEFF500\n"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "bic,bicValid,bicBankName,bicCountry,bicHeadquarter,bicReason\n"+
+			"DEUTDEFF500,true,\"'=HYPERLINK(\"\"http://x\"\")\",'@Germany,,\n", w.Body.String())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Large files are looked up in batches", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{branch}, nil)
+
+		var content strings.Builder
+		content.WriteString("bic\n")
+		for i := 0; i < 1200; i++ {
+			content.WriteString("DEUTDEFF500\n")
+		}
+
+		w := screen(t, repo, "", "text/csv", bytes.NewBufferString(content.String()))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 1201, strings.Count(w.Body.String(), "\n"))
+		repo.AssertNumberOfCalls(t, "FindByCodes", 3)
+	})
+
+	t.Run("BIC column missing from the header", func(t *testing.T) {
+		w := screen(t, new(mockRepos.SwiftRepository), "?column=iban", "text/csv", bytes.NewBufferString("name,bic\nAlice,DEUTDEFF500\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"invalid-parameter"`)
+	})
+
+	t.Run("Unsupported content type", func(t *testing.T) {
+		w := screen(t, new(mockRepos.SwiftRepository), "", "application/json", bytes.NewBufferString(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"malformed-request"`)
+	})
+
+	t.Run("Invalid delimiter", func(t *testing.T) {
+		w := screen(t, new(mockRepos.SwiftRepository), "?delimiter=ab", "text/csv", bytes.NewBufferString("bic\n"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"invalid-parameter"`)
+	})
+
+	t.Run("Lookup failure before any row is sent", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		w := screen(t, repo, "", "text/csv", bytes.NewBufferString(fmt.Sprintf("bic\n%s\n", "DEUTDEFF500")))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"internal-error"`)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("Lookup failure after rows were sent breaks the response off", func(t *testing.T) {
+		repo := new(mockRepos.SwiftRepository)
+		repo.On("FindByCodes", mock.Anything, mock.Anything).Return([]models.SwiftCode{branch}, nil).Once()
+		repo.On("FindByCodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Recovery(log.New(io.Discard, "", 0)))
+		router.POST("/v1/screenings", handlers.NewSwiftHandler(config.Config{}, repo).ScreenBeneficiaries)
+		server := httptest.NewServer(router)
+		defer server.Close()
+
+		content := "bic\n" + strings.Repeat("DEUTDEFF500\n", screening.DefaultBatchSize+1)
+		resp, err := http.Post(server.URL+"/v1/screenings", "text/csv", strings.NewReader(content))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Contains(t, string(body), "DEUTDEFF500,true")
+	})
+}
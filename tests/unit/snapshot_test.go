@@ -0,0 +1,187 @@
+package unit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"swift-codes-api/internal/config"
+	"swift-codes-api/internal/snapshot"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	snapshotRepo "swift-codes-api/repositories/snapshot"
+	"swift-codes-api/routes"
+	"swift-codes-api/tests/spec"
+	"testing"
+	"time"
+)
+
+var snapshotCodes = []models.SwiftCode{
+	{SwiftCode: "DEUTDEFFXXX", SwiftPrefix: "DEUTDEFF", IsHeadquarter: true, BankName: "Deutsche Bank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY", Revision: 3},
+	{SwiftCode: "DEUTDEFF500", SwiftPrefix: "DEUTDEFF", BankName: "Deutsche Bank", Address: "Berlin", CountryISO2: "DE", CountryName: "GERMANY", Revision: 1},
+	{SwiftCode: "BNPAFRPPXXX", SwiftPrefix: "BNPAFRPP", IsHeadquarter: true, BankName: "BNP Paribas", Address: "Paris", CountryISO2: "FR", CountryName: "FRANCE", Revision: 1},
+	{SwiftCode: "COBADEFFXXX", SwiftPrefix: "COBADEFF", IsHeadquarter: true, BankName: "Commerzbank", Address: "Frankfurt", CountryISO2: "DE", CountryName: "GERMANY", Revision: 2},
+}
+
+func writeSnapshot(t *testing.T, path string, swiftCodes []models.SwiftCode) {
+	file, err := os.Create(path + ".tmp")
+	require.NoError(t, err)
+	require.NoError(t, snapshot.Write(file, swiftCodes, time.Now()))
+	require.NoError(t, file.Close())
+	require.NoError(t, os.Rename(path+".tmp", path))
+}
+
+func openSnapshotRepo(t *testing.T, swiftCodes []models.SwiftCode) (*snapshotRepo.SwiftRepository, string) {
+	path := filepath.Join(t.TempDir(), "swift-codes.snap")
+	writeSnapshot(t, path, swiftCodes)
+
+	repo, err := snapshotRepo.NewSwiftRepository(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo, path
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Records are found by code, prefix and country", func(t *testing.T) {
+		updatedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		swiftCodes := append([]models.SwiftCode{}, snapshotCodes...)
+		swiftCodes[0].UpdatedAt = updatedAt
+		repo, _ := openSnapshotRepo(t, swiftCodes)
+
+		found, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		require.NoError(t, err)
+		assert.Equal(t, swiftCodes[0], *found)
+
+		_, err = repo.FindByCode(ctx, "DEUTDEFF999")
+		assert.Equal(t, interfaces.ErrNotFound, err)
+
+		branches, err := repo.FindBranchesByPrefix(ctx, "DEUTDEFF")
+		require.NoError(t, err)
+		require.Len(t, branches, 1)
+		assert.Equal(t, "DEUTDEFF500", branches[0].SwiftCode)
+
+		country, countryName, err := repo.FindByCountryISO2(ctx, "DE")
+		require.NoError(t, err)
+		assert.Equal(t, "GERMANY", countryName)
+		require.Len(t, country, 3)
+		assert.Equal(t, "COBADEFFXXX", country[0].SwiftCode)
+
+		_, _, err = repo.FindByCountryISO2(ctx, "PL")
+		assert.Equal(t, interfaces.ErrNotFound, err)
+
+		byCodes, err := repo.FindByCodes(ctx, []string{"BNPAFRPPXXX", "NONEXXXXXXX", "DEUTDEFF500"})
+		require.NoError(t, err)
+		assert.Len(t, byCodes, 2)
+
+		byPrefixes, err := repo.FindBranchesByPrefixes(ctx, []string{"DEUTDEFF", "BNPAFRPP"})
+		require.NoError(t, err)
+		require.Len(t, byPrefixes, 1)
+		assert.Equal(t, "DEUTDEFF500", byPrefixes[0].SwiftCode)
+
+		var streamed []string
+		require.NoError(t, repo.StreamSwiftCodes(ctx, "FR", func(swiftCode models.SwiftCode) error {
+			streamed = append(streamed, swiftCode.SwiftCode)
+			return nil
+		}))
+		assert.Equal(t, []string{"BNPAFRPPXXX"}, streamed)
+
+		matches, err := repo.SearchSwiftCodes(ctx, "bank", "DE", 10)
+		require.NoError(t, err)
+		require.Len(t, matches, 3)
+		assert.Equal(t, "COBADEFFXXX", matches[0].SwiftCode)
+
+		matches, err = repo.SearchSwiftCodes(ctx, "deutdeff", "", 1)
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "DEUTDEFF500", matches[0].SwiftCode)
+	})
+
+	t.Run("Lookups do not allocate beyond their result", func(t *testing.T) {
+		_, path := openSnapshotRepo(t, snapshotCodes)
+		opened, err := snapshot.Open(path)
+		require.NoError(t, err)
+		defer opened.Close()
+
+		allocs := testing.AllocsPerRun(100, func() {
+			if i, ok := opened.Find("DEUTDEFF500"); ok {
+				_ = opened.Record(i)
+			}
+		})
+		assert.Equal(t, float64(0), allocs)
+
+		repo, _ := openSnapshotRepo(t, snapshotCodes)
+		allocs = testing.AllocsPerRun(100, func() {
+			_, _ = repo.FindByCode(ctx, "DEUTDEFF500")
+		})
+		assert.Equal(t, float64(1), allocs)
+	})
+
+	t.Run("Changes are rejected as read-only", func(t *testing.T) {
+		repo, _ := openSnapshotRepo(t, snapshotCodes)
+		assert.ErrorIs(t, repo.AddSwiftCode(ctx, snapshotCodes[0]), interfaces.ErrReadOnly)
+		assert.ErrorIs(t, repo.UpdateSwiftCode(ctx, snapshotCodes[0], interfaces.AnyRevision), interfaces.ErrReadOnly)
+		assert.ErrorIs(t, repo.DeleteSwiftCode(ctx, "DEUTDEFF500", interfaces.AnyRevision), interfaces.ErrReadOnly)
+		assert.ErrorIs(t, repo.RestoreSwiftCode(ctx, "DEUTDEFF500"), interfaces.ErrReadOnly)
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		routes.SetupRoutes(router, routes.Dependencies{SwiftRepo: repo}, config.Config{})
+
+		req := httptest.NewRequest(http.MethodDelete, "/v1/swift-codes/DEUTDEFF500", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		assert.Equal(t, http.StatusNotImplemented, w.Code)
+		assert.Contains(t, w.Body.String(), `"code":"not-implemented"`)
+
+		req = httptest.NewRequest(http.MethodGet, "/v1/swift-codes/DEUTDEFF500", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		spec.ValidateResponse(t, req, w)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Reload swaps in a new snapshot", func(t *testing.T) {
+		repo, path := openSnapshotRepo(t, snapshotCodes)
+		before, err := repo.FindByCode(ctx, "DEUTDEFF500")
+		require.NoError(t, err)
+
+		renamed := append([]models.SwiftCode{}, snapshotCodes...)
+		renamed[1].BankName = "Deutsche Bank AG"
+		renamed = append(renamed, models.SwiftCode{SwiftCode: "PKOPPLPWXXX", SwiftPrefix: "PKOPPLPW", IsHeadquarter: true, BankName: "PKO BP", CountryISO2: "PL", CountryName: "POLAND"})
+		writeSnapshot(t, path, renamed)
+		require.NoError(t, repo.Reload())
+
+		after, err := repo.FindByCode(ctx, "DEUTDEFF500")
+		require.NoError(t, err)
+		assert.Equal(t, "Deutsche Bank AG", after.BankName)
+		_, err = repo.FindByCode(ctx, "PKOPPLPWXXX")
+		assert.NoError(t, err)
+
+		// Records read from the old snapshot outlive it.
+		assert.Equal(t, "Deutsche Bank", before.BankName)
+	})
+
+	t.Run("A corrupt snapshot is rejected and the current one kept", func(t *testing.T) {
+		repo, path := openSnapshotRepo(t, snapshotCodes)
+		require.NoError(t, os.WriteFile(path+".tmp", []byte(strings.Repeat("x", 128)), 0o644))
+		require.NoError(t, os.Rename(path+".tmp", path))
+
+		assert.ErrorIs(t, repo.Reload(), snapshot.ErrCorrupt)
+		_, err := repo.FindByCode(ctx, "DEUTDEFF500")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Duplicate codes cannot be written", func(t *testing.T) {
+		var buf strings.Builder
+		err := snapshot.Write(&buf, append(snapshotCodes, snapshotCodes[0]), time.Now())
+		assert.ErrorContains(t, err, "DEUTDEFFXXX appears twice")
+	})
+}
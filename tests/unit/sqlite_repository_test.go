@@ -0,0 +1,317 @@
+package unit
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	"swift-codes-api/repositories/sqlite"
+	"sync"
+	"testing"
+	"time"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	database, err := sqlite.Open(filepath.Join(t.TempDir(), "swift-codes.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func sqliteSwiftCode(code string, headquarter bool) models.SwiftCode {
+	return models.SwiftCode{
+		SwiftCode:     code,
+		IsHeadquarter: headquarter,
+		BankName:      "Deutsche Bank",
+		Address:       "Frankfurt",
+		CountryISO2:   "DE",
+		CountryName:   "GERMANY",
+	}
+}
+
+func TestSQLiteSwiftRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Added records are found by code, prefix and country", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFF500", false)))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFF100", false)))
+
+		found, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		require.NoError(t, err)
+		assert.Equal(t, "DEUTDEFF", found.SwiftPrefix)
+		assert.True(t, found.IsHeadquarter)
+		assert.Equal(t, int64(1), found.Revision)
+		assert.False(t, found.UpdatedAt.IsZero())
+
+		branches, err := repo.FindBranchesByPrefix(ctx, "DEUTDEFF")
+		require.NoError(t, err)
+		require.Len(t, branches, 2)
+		assert.Equal(t, "DEUTDEFF100", branches[0].SwiftCode)
+
+		branches, err = repo.FindBranchesByPrefixes(ctx, []string{"DEUTDEFF", "BNPAFRPP"})
+		require.NoError(t, err)
+		assert.Len(t, branches, 2)
+
+		swiftCodes, countryName, err := repo.FindByCountryISO2(ctx, "DE")
+		require.NoError(t, err)
+		assert.Len(t, swiftCodes, 3)
+		assert.Equal(t, "GERMANY", countryName)
+
+		swiftCodes, err = repo.FindByCodes(ctx, []string{"DEUTDEFF500", "BNPAFRPPXXX"})
+		require.NoError(t, err)
+		require.Len(t, swiftCodes, 1)
+		assert.Equal(t, "DEUTDEFF500", swiftCodes[0].SwiftCode)
+
+		var streamed []string
+		require.NoError(t, repo.StreamSwiftCodes(ctx, "DE", func(swiftCode models.SwiftCode) error {
+			streamed = append(streamed, swiftCode.SwiftCode)
+			return nil
+		}))
+		assert.Equal(t, []string{"DEUTDEFF100", "DEUTDEFF500", "DEUTDEFFXXX"}, streamed)
+	})
+
+	t.Run("Searches match code prefixes and bank names", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFF500", false)))
+		commerzbank := sqliteSwiftCode("COBADEFFXXX", true)
+		commerzbank.BankName = "Commerzbank 100%"
+		require.NoError(t, repo.AddSwiftCode(ctx, commerzbank))
+
+		codes := func(query, countryISO2 string, limit int) []string {
+			matches, err := repo.SearchSwiftCodes(ctx, query, countryISO2, limit)
+			require.NoError(t, err)
+			var codes []string
+			for _, match := range matches {
+				codes = append(codes, match.SwiftCode)
+			}
+			return codes
+		}
+
+		assert.Equal(t, []string{"DEUTDEFF500", "DEUTDEFFXXX"}, codes("deutdeff", "", 10))
+		assert.Equal(t, []string{"COBADEFFXXX", "DEUTDEFF500"}, codes("BANK", "DE", 2))
+		assert.Equal(t, []string{"COBADEFFXXX"}, codes("0%", "", 10))
+		assert.Empty(t, codes("bank", "FR", 10))
+		assert.Empty(t, codes("deut_eff", "", 10))
+	})
+
+	t.Run("Missing records are reported as interfaces.ErrNotFound", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+
+		_, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, _, err = repo.FindByCountryISO2(ctx, "DE")
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		assert.ErrorIs(t, repo.UpdateSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true), interfaces.AnyRevision), interfaces.ErrNotFound)
+		assert.ErrorIs(t, repo.DeleteSwiftCode(ctx, "DEUTDEFFXXX", interfaces.AnyRevision), interfaces.ErrNotFound)
+		assert.ErrorIs(t, repo.RestoreSwiftCode(ctx, "DEUTDEFFXXX"), interfaces.ErrNotFound)
+	})
+
+	t.Run("A live record cannot be added twice", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+
+		err := repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true))
+		assert.EqualError(t, err, "SWIFT code DEUTDEFFXXX already exists")
+	})
+
+	t.Run("Updates and deletes check the expected revision", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+
+		updated := sqliteSwiftCode("DEUTDEFFXXX", true)
+		updated.BankName = "Deutsche Bank AG"
+		require.NoError(t, repo.UpdateSwiftCode(ctx, updated, 1))
+		assert.ErrorIs(t, repo.UpdateSwiftCode(ctx, updated, 1), interfaces.ErrRevisionMismatch)
+		assert.ErrorIs(t, repo.DeleteSwiftCode(ctx, "DEUTDEFFXXX", 1), interfaces.ErrRevisionMismatch)
+
+		found, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		require.NoError(t, err)
+		assert.Equal(t, "Deutsche Bank AG", found.BankName)
+		assert.Equal(t, int64(2), found.Revision)
+	})
+
+	t.Run("Deleted records are hidden until restored or added again", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+		require.NoError(t, repo.DeleteSwiftCode(ctx, "DEUTDEFFXXX", 1))
+
+		_, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+
+		deleted, err := repo.FindByCode(reqctx.WithIncludeDeleted(ctx), "DEUTDEFFXXX")
+		require.NoError(t, err)
+		assert.NotNil(t, deleted.DeletedAt)
+
+		require.NoError(t, repo.RestoreSwiftCode(ctx, "DEUTDEFFXXX"))
+		require.NoError(t, repo.DeleteSwiftCode(ctx, "DEUTDEFFXXX", 3))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+
+		found, err := repo.FindByCode(ctx, "DEUTDEFFXXX")
+		require.NoError(t, err)
+		assert.Nil(t, found.DeletedAt)
+		assert.Equal(t, int64(5), found.Revision)
+	})
+
+	t.Run("Purging removes the records deleted before the cutoff", func(t *testing.T) {
+		repo := sqlite.NewSwiftRepository(openSQLite(t))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFFXXX", true)))
+		require.NoError(t, repo.AddSwiftCode(ctx, sqliteSwiftCode("DEUTDEFF500", false)))
+		require.NoError(t, repo.DeleteSwiftCode(ctx, "DEUTDEFF500", interfaces.AnyRevision))
+
+		purged, err := repo.PurgeDeletedSwiftCodes(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Len(t, purged, 1)
+		assert.Equal(t, "DEUTDEFF500", purged[0].SwiftCode)
+
+		_, err = repo.FindByCode(reqctx.WithIncludeDeleted(ctx), "DEUTDEFF500")
+		assert.ErrorIs(t, err, interfaces.ErrNotFound)
+		_, err = repo.FindByCode(ctx, "DEUTDEFFXXX")
+		assert.NoError(t, err)
+	})
+}
+
+func TestSQLiteVersionRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewVersionRepository(openSQLite(t))
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	updated := created.Add(24 * time.Hour)
+	deleted := updated.Add(24 * time.Hour)
+
+	record := sqliteSwiftCode("DEUTDEFF500", false)
+	record.SwiftPrefix = "DEUTDEFF"
+	require.NoError(t, repo.SaveVersion(ctx, record, false, created))
+	record.BankName = "Deutsche Bank AG"
+	require.NoError(t, repo.SaveVersion(ctx, record, false, updated))
+	require.NoError(t, repo.SaveVersion(ctx, record, true, deleted))
+
+	history, err := repo.FindHistory(ctx, "DEUTDEFF500")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, 1, history[0].Version)
+	assert.Equal(t, updated, *history[0].ValidTo)
+	assert.True(t, history[2].Deleted)
+	assert.Nil(t, history[2].ValidTo)
+
+	found, err := repo.FindByCodeAsOf(ctx, "DEUTDEFF500", created.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "Deutsche Bank", found.BankName)
+
+	branches, err := repo.FindBranchesByPrefixAsOf(ctx, "DEUTDEFF", updated)
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.Equal(t, "Deutsche Bank AG", branches[0].BankName)
+
+	_, _, err = repo.FindByCountryISO2AsOf(ctx, "DE", deleted)
+	assert.ErrorIs(t, err, interfaces.ErrNotFound)
+	_, err = repo.FindHistory(ctx, "BNPAFRPPXXX")
+	assert.ErrorIs(t, err, interfaces.ErrNotFound)
+	// Records stored before versions were kept get a first version, once.
+	backfilled := sqliteSwiftCode("BNPAFRPPXXX", true)
+	backfilled.DeletedAt = &deleted
+	require.NoError(t, repo.SaveInitialVersion(ctx, backfilled, created))
+	require.NoError(t, repo.SaveInitialVersion(ctx, record, created))
+	history, err = repo.FindHistory(ctx, "DEUTDEFF500")
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	found, err = repo.FindByCodeAsOf(ctx, "BNPAFRPPXXX", updated)
+	require.NoError(t, err)
+	assert.Nil(t, found.DeletedAt)
+	_, err = repo.FindByCodeAsOf(ctx, "BNPAFRPPXXX", deleted)
+	assert.ErrorIs(t, err, interfaces.ErrNotFound)
+}
+
+func TestSQLiteVersionRepositoryConcurrentSaves(t *testing.T) {
+	ctx := context.Background()
+	repo := sqlite.NewVersionRepository(openSQLite(t))
+	record := sqliteSwiftCode("DEUTDEFF500", false)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- repo.SaveVersion(ctx, record, false, start.Add(time.Duration(i)*time.Hour))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	history, err := repo.FindHistory(ctx, "DEUTDEFF500")
+	require.NoError(t, err)
+	require.Len(t, history, 20)
+	open := 0
+	for i, version := range history {
+		assert.Equal(t, i+1, version.Version)
+		if version.ValidTo == nil {
+			open++
+		}
+	}
+	assert.Equal(t, 1, open)
+}
+
+func TestSQLiteAuditAndEventRepositories(t *testing.T) {
+	ctx := context.Background()
+	database := openSQLite(t)
+
+	t.Run("Audit entries are found newest first", func(t *testing.T) {
+		repo := sqlite.NewAuditRepository(database)
+		before := sqliteSwiftCode("DEUTDEFFXXX", true)
+		at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		require.NoError(t, repo.Record(ctx, models.AuditEntry{
+			SwiftCode: "DEUTDEFFXXX", Operation: models.AuditOperationCreate, Actor: "alice", RequestID: "req-1", After: &before, Timestamp: at,
+		}))
+		require.NoError(t, repo.Record(ctx, models.AuditEntry{
+			SwiftCode: "DEUTDEFFXXX", Operation: models.AuditOperationDelete, Actor: "bob", RequestID: "req-2", Before: &before, Timestamp: at.Add(time.Hour),
+		}))
+
+		entries, err := repo.Find(ctx, models.AuditFilter{SwiftCode: "DEUTDEFFXXX"})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, models.AuditOperationDelete, entries[0].Operation)
+		assert.Equal(t, "Deutsche Bank", entries[0].Before.BankName)
+		assert.Nil(t, entries[0].After)
+
+		entries, err = repo.Find(ctx, models.AuditFilter{Actor: "alice", To: at})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, at, entries[0].Timestamp)
+	})
+
+	t.Run("Events are numbered as they are appended", func(t *testing.T) {
+		repo := sqlite.NewEventRepository(database)
+
+		latest, err := repo.LatestID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), latest)
+
+		for i, countryISO2 := range []string{"DE", "FR"} {
+			event, err := repo.Append(ctx, models.Event{Type: models.EventTypeCreated, SwiftCode: "DEUTDEFFXXX", CountryISO2: countryISO2, Timestamp: time.Now()})
+			require.NoError(t, err)
+			assert.Equal(t, int64(i+1), event.ID)
+		}
+
+		latest, err = repo.LatestID(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), latest)
+
+		events, err := repo.FindAfter(ctx, 0, "FR", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, int64(2), events[0].ID)
+	})
+}
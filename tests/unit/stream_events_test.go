@@ -0,0 +1,55 @@
+package unit
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/events"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+	"time"
+)
+
+func TestStreamEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetStreamEventsTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.EventRepository)
+			tc.SetupMocks(mockRepo)
+
+			broker := events.NewBroker(mockRepo)
+			handler := handlers.NewEventsHandler(broker)
+			router := gin.Default()
+			router.GET("/v1/events", handler.StreamEvents)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/events"+tc.Query, nil).WithContext(ctx)
+			if tc.LastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.LastEventID)
+			}
+			w := httptest.NewRecorder()
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				for _, event := range tc.Publish {
+					_ = broker.Publish(context.Background(), event)
+				}
+			}()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.Equal(t, tc.ExpectedBody, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
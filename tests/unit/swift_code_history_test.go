@@ -0,0 +1,43 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestSwiftCodeHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetSwiftCodeHistoryTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			mockVersions := new(mockRepos.VersionRepository)
+			tc.SetupMocks(mockVersions)
+
+			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo, handlers.WithVersions(mockVersions))
+			router := gin.Default()
+			router.GET("/v1/swift-codes/:swift-code", handler.GetSwiftCode)
+			router.GET("/v1/swift-codes/:swift-code/history", handler.GetSwiftCodeHistory)
+			router.GET("/v1/swift-codes/country/:countryISO2code", handler.GetSwiftCodesByCountry)
+
+			req := httptest.NewRequest(http.MethodGet, tc.Path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+			mockVersions.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package unit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestSwiftCodesV2(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetSwiftCodesV2TestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
+			router := gin.Default()
+			router.GET("/v2/swift-codes/:swift-code", handler.GetSwiftCodeV2)
+			router.GET("/v2/swift-codes/country/:countryISO2code", handler.GetSwiftCodesByCountryV2)
+
+			req := httptest.NewRequest(http.MethodGet, tc.Path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -2,9 +2,11 @@ package test_cases
 
 import (
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
 	mockRepo "swift-codes-api/repositories/mock"
 )
 
@@ -47,7 +49,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			}`,
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Missing required fields"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes","code":"validation-failed","errors":[{"field":"bankName","code":"required","message":"Must not be empty"},{"field":"address","code":"required","message":"Must not be empty"}]}`,
 		},
 		{
 			Name: "Invalid country code format",
@@ -61,7 +63,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			}`,
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid country code format. Must be a 2-letter ISO country code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes","code":"validation-failed","errors":[{"field":"countryISO2","code":"format","message":"Country code must be a 2-letter ISO 3166-1 alpha-2 code"}]}`,
 		},
 		{
 			Name: "Invalid SWIFT code format",
@@ -75,7 +77,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			}`,
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid SWIFT code format. Must be 11 characters and follow proper format"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes","code":"validation-failed","errors":[{"field":"swiftCode","code":"format","message":"SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code"}]}`,
 		},
 		{
 			Name: "Country code mismatch in SWIFT code",
@@ -89,7 +91,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			}`,
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Country code in SWIFT code does not match the provided country code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes","code":"validation-failed","errors":[{"field":"countryISO2","code":"mismatch","message":"Must match characters 5-6 of the SWIFT code"}]}`,
 		},
 		{
 			Name: "SWIFT code already exists",
@@ -104,10 +106,10 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			SetupMocks: func(repo *mockRepo.SwiftRepository) {
 				repo.On("AddSwiftCode", mock.Anything, mock.MatchedBy(func(sc models.SwiftCode) bool {
 					return sc.SwiftCode == "ABCDUS12XXX"
-				})).Return(errors.New("SWIFT code ABCDUS12XXX already exists"))
+				})).Return(fmt.Errorf("SWIFT code ABCDUS12XXX %w", interfaces.ErrAlreadyExists))
 			},
 			ExpectedStatus:   http.StatusConflict,
-			ExpectedResponse: `{"message":"SWIFT code ABCDUS12XXX already exists"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-exists","title":"SWIFT code already exists","status":409,"detail":"SWIFT code ABCDUS12XXX already exists","instance":"/v1/swift-codes","code":"swift-code-exists"}`,
 		},
 		{
 			Name: "Server error",
@@ -123,7 +125,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 				repo.On("AddSwiftCode", mock.Anything, mock.Anything).Return(errors.New("database error"))
 			},
 			ExpectedStatus:   http.StatusInternalServerError,
-			ExpectedResponse: `{"message":"Failed to add SWIFT code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to add SWIFT code","instance":"/v1/swift-codes","code":"internal-error"}`,
 		},
 		{
 			Name: "Invalid JSON format",
@@ -137,7 +139,7 @@ func GetAddSwiftCodeTestCases() []AddSwiftCodeTestCase {
 			}`,
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid request format"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:malformed-request","title":"Malformed request body","status":400,"detail":"Request body must be a valid JSON object","instance":"/v1/swift-codes","code":"malformed-request"}`,
 		},
 	}
 }
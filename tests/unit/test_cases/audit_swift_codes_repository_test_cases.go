@@ -0,0 +1,134 @@
+package test_cases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type AuditSwiftRepositoryTestCase struct {
+	Name          string
+	Mutate        func(ctx context.Context, repo interfaces.SwiftRepository) error
+	SetupMocks    func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository)
+	ExpectedError bool
+}
+
+func GetAuditSwiftRepositoryTestCases() []AuditSwiftRepositoryTestCase {
+	swiftCode := models.SwiftCode{
+		SwiftCode:     "ABCDUS12XXX",
+		SwiftPrefix:   "ABCDUS12",
+		BankName:      "Bank of America",
+		CountryISO2:   "US",
+		CountryName:   "United States",
+		Address:       "123 Main St, New York",
+		IsHeadquarter: true,
+	}
+
+	return []AuditSwiftRepositoryTestCase{
+		{
+			Name: "Create is recorded with after snapshot",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.AddSwiftCode(ctx, swiftCode)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				repo.On("AddSwiftCode", mock.Anything, swiftCode).Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&swiftCode, nil)
+				audit.On("Record", mock.Anything, mock.MatchedBy(func(e models.AuditEntry) bool {
+					return e.Operation == models.AuditOperationCreate && e.SwiftCode == "ABCDUS12XXX" &&
+						e.Actor == "alice" && e.RequestID == "req-1" &&
+						e.Before == nil && e.After != nil && e.After.BankName == "Bank of America" &&
+						!e.Timestamp.IsZero()
+				})).Return(nil)
+			},
+		},
+		{
+			Name: "Update is recorded with before and after snapshots",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				changed := swiftCode
+				changed.BankName = "Bank of America N.A."
+				return repo.UpdateSwiftCode(ctx, changed, 1)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				after := swiftCode
+				after.BankName = "Bank of America N.A."
+				after.Revision = 2
+
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&swiftCode, nil).Once()
+				repo.On("UpdateSwiftCode", mock.Anything, mock.Anything, int64(1)).Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&after, nil).Once()
+				audit.On("Record", mock.Anything, mock.MatchedBy(func(e models.AuditEntry) bool {
+					return e.Operation == models.AuditOperationUpdate &&
+						e.Before != nil && e.Before.BankName == "Bank of America" &&
+						e.After != nil && e.After.BankName == "Bank of America N.A." && e.After.Revision == 2
+				})).Return(nil)
+			},
+		},
+		{
+			Name: "Delete is recorded with before snapshot",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.DeleteSwiftCode(ctx, "ABCDUS12XXX", interfaces.AnyRevision)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&swiftCode, nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX", interfaces.AnyRevision).Return(nil)
+				audit.On("Record", mock.Anything, mock.MatchedBy(func(e models.AuditEntry) bool {
+					return e.Operation == models.AuditOperationDelete && e.Actor == "alice" &&
+						e.Before != nil && e.Before.SwiftCode == "ABCDUS12XXX" && e.After == nil
+				})).Return(nil)
+			},
+		},
+		{
+			Name: "Failed mutation is not recorded",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.AddSwiftCode(ctx, swiftCode)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				repo.On("AddSwiftCode", mock.Anything, swiftCode).Return(errors.New("database error"))
+			},
+			ExpectedError: true,
+		},
+		{
+			Name: "Restore is recorded with before and after snapshots",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.RestoreSwiftCode(ctx, "ABCDUS12XXX")
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				deletedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+				deleted := swiftCode
+				deleted.DeletedAt = &deletedAt
+
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(&deleted, nil).Once()
+				repo.On("RestoreSwiftCode", mock.Anything, "ABCDUS12XXX").Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&swiftCode, nil).Once()
+				audit.On("Record", mock.Anything, mock.MatchedBy(func(e models.AuditEntry) bool {
+					return e.Operation == models.AuditOperationRestore &&
+						e.Before != nil && e.Before.DeletedAt != nil &&
+						e.After != nil && e.After.DeletedAt == nil
+				})).Return(nil)
+			},
+		},
+		{
+			Name: "Purge records every purged code",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				_, err := repo.PurgeDeletedSwiftCodes(ctx, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+				return err
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, audit *mockRepo.AuditRepository) {
+				repo.On("PurgeDeletedSwiftCodes", mock.Anything, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)).Return([]models.SwiftCode{
+					{SwiftCode: "ABCDUS12XXX"},
+					{SwiftCode: "ABCDUS12NYC"},
+				}, nil)
+				audit.On("Record", mock.Anything, mock.MatchedBy(func(e models.AuditEntry) bool {
+					return e.Operation == models.AuditOperationPurge && e.Before != nil && e.After == nil
+				})).Return(nil).Twice()
+			},
+		},
+	}
+}
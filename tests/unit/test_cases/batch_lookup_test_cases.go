@@ -0,0 +1,100 @@
+package test_cases
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"strings"
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type BatchLookupTestCase struct {
+	Name             string
+	RequestBody      string
+	SetupMocks       func(repository *mockRepo.SwiftRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetBatchLookupTestCases() []BatchLookupTestCase {
+	headquarter := models.SwiftCode{
+		SwiftCode:     "DEUTDEFFXXX",
+		SwiftPrefix:   "DEUTDEFF",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "Taunusanlage 12, Frankfurt",
+		IsHeadquarter: true,
+		Revision:      1,
+	}
+	branch := models.SwiftCode{
+		SwiftCode:   "DEUTDEFF500",
+		SwiftPrefix: "DEUTDEFF",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "Bockenheimer Landstrasse, Frankfurt",
+		Revision:    3,
+	}
+
+	return []BatchLookupTestCase{
+		{
+			Name:        "Found, not found and invalid codes",
+			RequestBody: `{"codes": ["DEUTDEFF500", "deutdeff", "ABCDEF12XXX", "INVALID", "DEUTDEFFXXX"]}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFF500", "DEUTDEFFXXX", "ABCDEF12XXX"}).
+					Return([]models.SwiftCode{headquarter, branch}, nil).Once()
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{"results":[
+				{"code":"DEUTDEFF500","swiftCode":"DEUTDEFF500","status":"found","record":{"swiftCode":"DEUTDEFF500","isHeadquarter":false,"bankName":"Deutsche Bank","address":"Bockenheimer Landstrasse, Frankfurt","countryISO2":"DE","countryName":"Germany","revision":3}},
+				{"code":"deutdeff","swiftCode":"DEUTDEFFXXX","status":"found","record":{"swiftCode":"DEUTDEFFXXX","isHeadquarter":true,"bankName":"Deutsche Bank","address":"Taunusanlage 12, Frankfurt","countryISO2":"DE","countryName":"Germany","revision":1}},
+				{"code":"ABCDEF12XXX","swiftCode":"ABCDEF12XXX","status":"not-found"},
+				{"code":"INVALID","status":"invalid"},
+				{"code":"DEUTDEFFXXX","swiftCode":"DEUTDEFFXXX","status":"found","record":{"swiftCode":"DEUTDEFFXXX","isHeadquarter":true,"bankName":"Deutsche Bank","address":"Taunusanlage 12, Frankfurt","countryISO2":"DE","countryName":"Germany","revision":1}}
+			]}`,
+		},
+		{
+			Name:        "Only invalid codes",
+			RequestBody: `{"codes": ["INVALID"]}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"results":[{"code":"INVALID","status":"invalid"}]}`,
+		},
+		{
+			Name:        "No codes",
+			RequestBody: `{"codes": []}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes/lookup","code":"validation-failed","errors":[{"field":"codes","code":"required","message":"Must not be empty"}]}`,
+		},
+		{
+			Name:        "Too many codes",
+			RequestBody: `{"codes": ["` + strings.Repeat(`DEUTDEFF", "`, 1000) + `DEUTDEFF"]}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes/lookup","code":"validation-failed","errors":[{"field":"codes","code":"format","message":"Must list at most 1000 codes"}]}`,
+		},
+		{
+			Name:        "Malformed body",
+			RequestBody: `{"codes": "DEUTDEFF"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:malformed-request","title":"Malformed request body","status":400,"detail":"Request body must be a valid JSON object","instance":"/v1/swift-codes/lookup","code":"malformed-request"}`,
+		},
+		{
+			Name:        "Lookup failed",
+			RequestBody: `{"codes": ["DEUTDEFF"]}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFFXXX"}).Return(nil, errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to look up SWIFT codes","instance":"/v1/swift-codes/lookup","code":"internal-error"}`,
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package test_cases
+
+import "net/http"
+
+type ConditionalGETTestCase struct {
+	Name             string
+	Headers          map[string]string
+	HandlerStatus    int
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+// "{etag}" in a header value is replaced with the ETag of the handler's body.
+func GetConditionalGETTestCases() []ConditionalGETTestCase {
+	return []ConditionalGETTestCase{
+		{
+			Name:             "No validators",
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"AAISALTRXXX"}`,
+		},
+		{
+			Name:             "Matching If-None-Match",
+			Headers:          map[string]string{"If-None-Match": "{etag}"},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusNotModified,
+			ExpectedResponse: "",
+		},
+		{
+			Name:             "Weak If-None-Match in list",
+			Headers:          map[string]string{"If-None-Match": `"other", W/{etag}`},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusNotModified,
+			ExpectedResponse: "",
+		},
+		{
+			Name:             "Stale If-None-Match",
+			Headers:          map[string]string{"If-None-Match": `"other"`},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"AAISALTRXXX"}`,
+		},
+		{
+			Name:             "If-Modified-Since not modified",
+			Headers:          map[string]string{"If-Modified-Since": "Sun, 01 Mar 2026 12:00:00 GMT"},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusNotModified,
+			ExpectedResponse: "",
+		},
+		{
+			Name:             "If-Modified-Since modified",
+			Headers:          map[string]string{"If-Modified-Since": "Sat, 28 Feb 2026 12:00:00 GMT"},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"AAISALTRXXX"}`,
+		},
+		{
+			Name: "If-None-Match takes precedence over If-Modified-Since",
+			Headers: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": "Sun, 01 Mar 2026 12:00:00 GMT",
+			},
+			HandlerStatus:    http.StatusOK,
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"AAISALTRXXX"}`,
+		},
+		{
+			Name:             "Errors pass through",
+			Headers:          map[string]string{"If-None-Match": "*"},
+			HandlerStatus:    http.StatusNotFound,
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"swiftCode":"AAISALTRXXX"}`,
+		},
+	}
+}
@@ -1,23 +1,36 @@
 package test_cases
 
 import (
+	"encoding/json"
 	"errors"
 	"github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 	"net/http"
 	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
 	mockRepo "swift-codes-api/repositories/mock"
+	"swift-codes-api/utils"
 )
 
 type DeleteSwiftCodeTestCase struct {
 	Name             string
 	SwiftCode        string
+	IfMatch          string
 	SetupMocks       func(repository *mockRepo.SwiftRepository)
 	ExpectedStatus   int
 	ExpectedResponse string
 }
 
 func GetDeleteSwiftCodeTestCases() []DeleteSwiftCodeTestCase {
+	branch := &models.SwiftCode{
+		SwiftCode:   "ABCDUS12ABC",
+		BankName:    "Bank of America",
+		CountryISO2: "US",
+		CountryName: "United States",
+		Address:     "5 Branch Rd, Boston",
+		Revision:    3,
+	}
+	branchJSON, _ := json.Marshal(branch)
+
 	return []DeleteSwiftCodeTestCase{
 		{
 			Name:      "Successful deletion",
@@ -32,7 +45,7 @@ func GetDeleteSwiftCodeTestCases() []DeleteSwiftCodeTestCase {
 					IsHeadquarter: true,
 				}
 				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(swiftCode, nil)
-				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX").Return(nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX", interfaces.AnyRevision).Return(nil)
 			},
 			ExpectedStatus:   http.StatusOK,
 			ExpectedResponse: `{"message":"SWIFT code deleted successfully"}`,
@@ -44,16 +57,16 @@ func GetDeleteSwiftCodeTestCases() []DeleteSwiftCodeTestCase {
 
 			},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid SWIFT code format"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-swift-code","title":"Invalid SWIFT code","status":400,"detail":"SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code","instance":"/v1/swift-codes/INVALID","code":"invalid-swift-code"}`,
 		},
 		{
 			Name:      "SWIFT code not found",
 			SwiftCode: "ABCDJP12XXX",
 			SetupMocks: func(repo *mockRepo.SwiftRepository) {
-				repo.On("FindByCode", mock.Anything, "ABCDJP12XXX").Return(nil, mongo.ErrNoDocuments)
+				repo.On("FindByCode", mock.Anything, "ABCDJP12XXX").Return(nil, interfaces.ErrNotFound)
 			},
 			ExpectedStatus:   http.StatusNotFound,
-			ExpectedResponse: `{"message":"SWIFT code not found"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code ABCDJP12XXX exists","instance":"/v1/swift-codes/ABCDJP12XXX","code":"swift-code-not-found"}`,
 		},
 		{
 			Name:      "Delete operation failed",
@@ -69,10 +82,10 @@ func GetDeleteSwiftCodeTestCases() []DeleteSwiftCodeTestCase {
 					SwiftPrefix:   "DEUTDE",
 				}
 				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(swiftCode, nil)
-				repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX").Return(errors.New("database error"))
+				repo.On("DeleteSwiftCode", mock.Anything, "DEUTDE11XXX", interfaces.AnyRevision).Return(errors.New("database error"))
 			},
 			ExpectedStatus:   http.StatusInternalServerError,
-			ExpectedResponse: `{"message":"Failed to delete SWIFT code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to delete SWIFT code","instance":"/v1/swift-codes/DEUTDE11XXX","code":"internal-error"}`,
 		},
 		{
 			Name:      "Delete headquarter SWIFT code",
@@ -88,10 +101,63 @@ func GetDeleteSwiftCodeTestCases() []DeleteSwiftCodeTestCase {
 					SwiftPrefix:   "BARCGB",
 				}
 				repo.On("FindByCode", mock.Anything, "BARCGB22XXX").Return(swiftCode, nil)
-				repo.On("DeleteSwiftCode", mock.Anything, "BARCGB22XXX").Return(nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "BARCGB22XXX", interfaces.AnyRevision).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code deleted successfully"}`,
+		},
+		{
+			Name:      "If-Match with current revision",
+			SwiftCode: "ABCDUS12ABC",
+			IfMatch:   `"3"`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12ABC").Return(branch, nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12ABC", int64(3)).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code deleted successfully"}`,
+		},
+		{
+			Name:      "If-Match with current ETag",
+			SwiftCode: "ABCDUS12ABC",
+			IfMatch:   `"stale", ` + utils.ETag(branchJSON),
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12ABC").Return(branch, nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12ABC", int64(3)).Return(nil)
 			},
 			ExpectedStatus:   http.StatusOK,
 			ExpectedResponse: `{"message":"SWIFT code deleted successfully"}`,
 		},
+		{
+			Name:      "If-Match with stale revision",
+			SwiftCode: "ABCDUS12ABC",
+			IfMatch:   "2",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12ABC").Return(branch, nil)
+			},
+			ExpectedStatus:   http.StatusPreconditionFailed,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:revision-mismatch","title":"SWIFT code has been modified","status":412,"detail":"SWIFT code has been modified since it was retrieved","instance":"/v1/swift-codes/ABCDUS12ABC","code":"revision-mismatch"}`,
+		},
+		{
+			Name:      "If-Match with weak ETag",
+			SwiftCode: "ABCDUS12ABC",
+			IfMatch:   "W/" + utils.ETag(branchJSON),
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12ABC").Return(branch, nil)
+			},
+			ExpectedStatus:   http.StatusPreconditionFailed,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:revision-mismatch","title":"SWIFT code has been modified","status":412,"detail":"SWIFT code has been modified since it was retrieved","instance":"/v1/swift-codes/ABCDUS12ABC","code":"revision-mismatch"}`,
+		},
+		{
+			Name:      "Revision changes concurrently",
+			SwiftCode: "ABCDUS12ABC",
+			IfMatch:   `"3"`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDUS12ABC").Return(branch, nil)
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12ABC", int64(3)).Return(interfaces.ErrRevisionMismatch)
+			},
+			ExpectedStatus:   http.StatusPreconditionFailed,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:revision-mismatch","title":"SWIFT code has been modified","status":412,"detail":"SWIFT code has been modified since it was retrieved","instance":"/v1/swift-codes/ABCDUS12ABC","code":"revision-mismatch"}`,
+		},
 	}
 }
@@ -0,0 +1,157 @@
+package test_cases
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type ExportTestCase struct {
+	Name                string
+	Path                string
+	Accept              string
+	SetupMocks          func(repository *mockRepo.SwiftRepository)
+	ExpectedStatus      int
+	ExpectedContentType string
+	ExpectedBody        string
+}
+
+func GetExportTestCases() []ExportTestCase {
+	swiftCodes := []models.SwiftCode{
+		{SwiftCode: "BREXPLPWXXX", BankName: "mBank S.A.", Address: "Prosta 18, Warszawa", CountryISO2: "PL", CountryName: "POLAND", IsHeadquarter: true},
+		{SwiftCode: "BREXPLPWWAL", BankName: "mBank S.A.", Address: "Wał, \"Gdańsk\"", CountryISO2: "PL", CountryName: "POLAND"},
+	}
+	findPoland := func(repo *mockRepo.SwiftRepository) {
+		repo.On("FindByCountryISO2", mock.Anything, "PL").Return(swiftCodes, "POLAND", nil)
+	}
+	streamPoland := func(repo *mockRepo.SwiftRepository) {
+		repo.On("StreamSwiftCodes", mock.Anything, "PL").Return(swiftCodes, nil)
+	}
+	exportedJSON := `[{"swiftCode":"BREXPLPWXXX","isHeadquarter":true,"bankName":"mBank S.A.","address":"Prosta 18, Warszawa","countryISO2":"PL","countryName":"POLAND"},` +
+		`{"swiftCode":"BREXPLPWWAL","isHeadquarter":false,"bankName":"mBank S.A.","address":"Wał, \"Gdańsk\"","countryISO2":"PL","countryName":"POLAND"}]`
+
+	return []ExportTestCase{
+		{
+			Name:                "Country as CSV via Accept",
+			Path:                "/v1/swift-codes/country/PL",
+			Accept:              "text/csv",
+			SetupMocks:          findPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/csv; charset=utf-8",
+			ExpectedBody: "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+				"BREXPLPWXXX,mBank S.A.,\"Prosta 18, Warszawa\",PL,POLAND,true\n" +
+				"BREXPLPWWAL,mBank S.A.,\"Wał, \"\"Gdańsk\"\"\",PL,POLAND,false\n",
+		},
+		{
+			Name:                "Country as NDJSON via format",
+			Path:                "/v1/swift-codes/country/PL?format=ndjson",
+			Accept:              "application/json",
+			SetupMocks:          findPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/x-ndjson",
+			ExpectedBody: `{"swiftCode":"BREXPLPWXXX","isHeadquarter":true,"bankName":"mBank S.A.","address":"Prosta 18, Warszawa","countryISO2":"PL","countryName":"POLAND"}` + "\n" +
+				`{"swiftCode":"BREXPLPWWAL","isHeadquarter":false,"bankName":"mBank S.A.","address":"Wał, \"Gdańsk\"","countryISO2":"PL","countryName":"POLAND"}` + "\n",
+		},
+		{
+			Name:                "Country as XML by preference",
+			Path:                "/v1/swift-codes/country/PL",
+			Accept:              "text/csv;q=0.5, application/xml",
+			SetupMocks:          findPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/xml; charset=utf-8",
+			ExpectedBody: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<swiftCodes><swiftCode><code>BREXPLPWXXX</code><bankName>mBank S.A.</bankName><address>Prosta 18, Warszawa</address><countryISO2>PL</countryISO2><countryName>POLAND</countryName><isHeadquarter>true</isHeadquarter></swiftCode>` +
+				`<swiftCode><code>BREXPLPWWAL</code><bankName>mBank S.A.</bankName><address>Wał, &#34;Gdańsk&#34;</address><countryISO2>PL</countryISO2><countryName>POLAND</countryName><isHeadquarter>false</isHeadquarter></swiftCode></swiftCodes>`,
+		},
+		{
+			Name:                "Unknown format",
+			Path:                "/v1/swift-codes/country/PL?format=xlsx",
+			SetupMocks:          func(repo *mockRepo.SwiftRepository) {},
+			ExpectedStatus:      http.StatusBadRequest,
+			ExpectedContentType: "application/problem+json",
+			ExpectedBody:        `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"format must be one of json, csv, ndjson or xml","instance":"/v1/swift-codes/country/PL","code":"invalid-parameter"}`,
+		},
+		{
+			Name:                "Unsupported Accept falls back to JSON",
+			Path:                "/v1/export?country=pl",
+			Accept:              "application/pdf",
+			SetupMocks:          streamPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedBody:        exportedJSON,
+		},
+		{
+			Name:                "Browsers get JSON",
+			Path:                "/v1/export?country=pl",
+			Accept:              "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			SetupMocks:          streamPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedBody:        exportedJSON,
+		},
+		{
+			Name:                "Wildcards get JSON",
+			Path:                "/v1/export?country=pl",
+			Accept:              "text/*",
+			SetupMocks:          streamPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedBody:        exportedJSON,
+		},
+		{
+			Name:                "Export as JSON array",
+			Path:                "/v1/export?country=pl",
+			Accept:              "*/*",
+			SetupMocks:          streamPoland,
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "application/json; charset=utf-8",
+			ExpectedBody:        exportedJSON,
+		},
+		{
+			Name: "Export of an empty directory as CSV",
+			Path: "/v1/export?format=csv",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("StreamSwiftCodes", mock.Anything, "").Return(nil, nil)
+			},
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/csv; charset=utf-8",
+			ExpectedBody:        "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n",
+		},
+		{
+			Name: "Export as CSV defuses cells read as formulas",
+			Path: "/v1/export?format=csv",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("StreamSwiftCodes", mock.Anything, "").Return([]models.SwiftCode{
+					{SwiftCode: "BREXPLPWXXX", BankName: `=HYPERLINK("http://example.com")`, Address: "+48 22 829 00 00", CountryISO2: "PL", CountryName: "@POLAND", IsHeadquarter: true},
+					{SwiftCode: "BREXPLPWWAL", BankName: "-mBank", Address: "\tWał", CountryISO2: "PL", CountryName: "POLAND"},
+				}, nil)
+			},
+			ExpectedStatus:      http.StatusOK,
+			ExpectedContentType: "text/csv; charset=utf-8",
+			ExpectedBody: "swiftCode,bankName,address,countryISO2,countryName,isHeadquarter\n" +
+				"BREXPLPWXXX,\"'=HYPERLINK(\"\"http://example.com\"\")\",'+48 22 829 00 00,PL,'@POLAND,true\n" +
+				"BREXPLPWWAL,'-mBank,'\tWał,PL,POLAND,false\n",
+		},
+		{
+			Name: "Export with invalid country",
+			Path: "/v1/export?country=POL",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:      http.StatusBadRequest,
+			ExpectedContentType: "application/problem+json",
+			ExpectedBody:        `{"type":"urn:swift-codes-api:problem:invalid-country-code","title":"Invalid country code","status":400,"detail":"Country code must be a 2-letter ISO 3166-1 alpha-2 code","instance":"/v1/export","code":"invalid-country-code"}`,
+		},
+		{
+			Name: "Export fails before anything is written",
+			Path: "/v1/export?format=ndjson",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("StreamSwiftCodes", mock.Anything, "").Return(nil, errors.New("database error"))
+			},
+			ExpectedStatus:      http.StatusInternalServerError,
+			ExpectedContentType: "application/problem+json",
+			ExpectedBody:        `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to export SWIFT codes","instance":"/v1/export","code":"internal-error"}`,
+		},
+	}
+}
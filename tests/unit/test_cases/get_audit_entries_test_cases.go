@@ -0,0 +1,117 @@
+package test_cases
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type AuditEntriesTestCase struct {
+	Name             string
+	Query            string
+	NotAdmin         bool
+	SetupMocks       func(repository *mockRepo.AuditRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetAuditEntriesTestCases() []AuditEntriesTestCase {
+	timestamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return []AuditEntriesTestCase{
+		{
+			Name:  "Filter by code, actor and time range",
+			Query: "?swiftCode=abcdus12xxx&actor=alice&from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z",
+			SetupMocks: func(repo *mockRepo.AuditRepository) {
+				repo.On("Find", mock.Anything, models.AuditFilter{
+					SwiftCode: "ABCDUS12XXX",
+					Actor:     "alice",
+					From:      time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+					To:        time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+					Limit:     100,
+				}).Return([]models.AuditEntry{
+					{
+						SwiftCode: "ABCDUS12XXX",
+						Operation: models.AuditOperationDelete,
+						Actor:     "alice",
+						RequestID: "req-1",
+						Before: &models.SwiftCode{
+							SwiftCode:     "ABCDUS12XXX",
+							BankName:      "Bank of America",
+							CountryISO2:   "US",
+							CountryName:   "United States",
+							Address:       "123 Main St, New York",
+							IsHeadquarter: true,
+						},
+						Timestamp: timestamp,
+					},
+				}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"entries": [
+					{
+						"swiftCode": "ABCDUS12XXX",
+						"operation": "delete",
+						"actor": "alice",
+						"requestId": "req-1",
+						"before": {
+							"swiftCode": "ABCDUS12XXX",
+							"bankName": "Bank of America",
+							"countryISO2": "US",
+							"countryName": "United States",
+							"address": "123 Main St, New York",
+							"isHeadquarter": true
+						},
+						"after": null,
+						"timestamp": "2026-03-01T12:00:00Z"
+					}
+				]
+			}`,
+		},
+		{
+			Name:             "Non-administrators are forbidden",
+			NotAdmin:         true,
+			SetupMocks:       func(repo *mockRepo.AuditRepository) {},
+			ExpectedStatus:   http.StatusForbidden,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:forbidden","title":"Forbidden","status":403,"detail":"Only administrators may read the audit log","instance":"/v1/audit","code":"forbidden"}`,
+		},
+		{
+			Name:  "No filters",
+			Query: "",
+			SetupMocks: func(repo *mockRepo.AuditRepository) {
+				repo.On("Find", mock.Anything, models.AuditFilter{Limit: 100}).Return([]models.AuditEntry{}, nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"entries":[]}`,
+		},
+		{
+			Name:             "Invalid from timestamp",
+			Query:            "?from=yesterday",
+			SetupMocks:       func(repo *mockRepo.AuditRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"from must be an RFC 3339 timestamp","instance":"/v1/audit","code":"invalid-parameter"}`,
+		},
+		{
+			Name:             "Invalid limit",
+			Query:            "?limit=5000",
+			SetupMocks:       func(repo *mockRepo.AuditRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"limit must be between 1 and 1000","instance":"/v1/audit","code":"invalid-parameter"}`,
+		},
+		{
+			Name:  "Repository error",
+			Query: "?actor=bob",
+			SetupMocks: func(repo *mockRepo.AuditRepository) {
+				repo.On("Find", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to retrieve audit entries","instance":"/v1/audit","code":"internal-error"}`,
+		},
+	}
+}
@@ -3,19 +3,23 @@ package test_cases
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
+	"swift-codes-api/internal/config"
 	"swift-codes-api/models"
 	mockRep "swift-codes-api/repositories/mock"
 )
 
 type SwiftCodeTestCase struct {
-	Name               string
-	SwiftCode          string
-	SetupMocks         func(repo *mockRep.SwiftRepository)
-	ExpectedStatusCode int
-	ExpectedResponse   string
+	Name                 string
+	SwiftCode            string
+	Config               config.Config
+	SetupMocks           func(repo *mockRep.SwiftRepository)
+	ExpectedStatusCode   int
+	ExpectedResponse     string
+	ExpectedLastModified string
 }
 
 func GetSwiftCodeTestCases() []SwiftCodeTestCase {
@@ -56,6 +60,65 @@ func GetSwiftCodeTestCases() []SwiftCodeTestCase {
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   `{"address":"456 Main St, Berlin","bankName":"Deutsche Bank","branches":[{"address":"789 Branch St, Munich","bankName":"Deutsche Bank Branch","countryISO2":"DE","countryName":"Germany","isHeadquarter":false,"swiftCode":"DEUTDE22XXX"}],"countryISO2":"DE","countryName":"Germany","isHeadquarter":true,"swiftCode":"DEUTDE11XXX"}`,
 		},
+		{
+			Name:      "Headquarter has no Last-Modified since its branches can be deleted",
+			SwiftCode: "DEUTDE11XXX",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(&models.SwiftCode{
+					SwiftCode:     "DEUTDE11XXX",
+					BankName:      "Deutsche Bank",
+					CountryISO2:   "DE",
+					CountryName:   "Germany",
+					Address:       "456 Main St, Berlin",
+					IsHeadquarter: true,
+					SwiftPrefix:   "DEUTDE",
+					UpdatedAt:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+				}, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE").Return([]models.SwiftCode{
+					{SwiftCode: "DEUTDE22XXX", BankName: "Deutsche Bank Branch", CountryISO2: "DE", CountryName: "Germany", Address: "789 Branch St, Munich", UpdatedAt: time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)},
+				}, nil)
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   `{"address":"456 Main St, Berlin","bankName":"Deutsche Bank","branches":[{"address":"789 Branch St, Munich","bankName":"Deutsche Bank Branch","countryISO2":"DE","countryName":"Germany","isHeadquarter":false,"swiftCode":"DEUTDE22XXX"}],"countryISO2":"DE","countryName":"Germany","isHeadquarter":true,"swiftCode":"DEUTDE11XXX"}`,
+		},
+		{
+			Name:      "Branch Last-Modified is its latest update",
+			SwiftCode: "DEUTDE22XXX",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE22XXX").Return(&models.SwiftCode{
+					SwiftCode:   "DEUTDE22XXX",
+					BankName:    "Deutsche Bank Branch",
+					CountryISO2: "DE",
+					CountryName: "Germany",
+					Address:     "789 Branch St, Munich",
+					UpdatedAt:   time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC),
+				}, nil)
+			},
+			ExpectedStatusCode:   http.StatusOK,
+			ExpectedResponse:     `{"address":"789 Branch St, Munich","bankName":"Deutsche Bank Branch","countryISO2":"DE","countryName":"Germany","isHeadquarter":false,"swiftCode":"DEUTDE22XXX"}`,
+			ExpectedLastModified: "Mon, 02 Mar 2026 08:30:00 GMT",
+		},
+		{
+			Name:      "Headquarter branches without country name",
+			SwiftCode: "DEUTDE11XXX",
+			Config:    config.Config{OmitBranchCountryName: true},
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(&models.SwiftCode{
+					SwiftCode:     "DEUTDE11XXX",
+					BankName:      "Deutsche Bank",
+					CountryISO2:   "DE",
+					CountryName:   "Germany",
+					Address:       "456 Main St, Berlin",
+					IsHeadquarter: true,
+					SwiftPrefix:   "DEUTDE",
+				}, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE").Return([]models.SwiftCode{
+					{SwiftCode: "DEUTDE22XXX", BankName: "Deutsche Bank Branch", CountryISO2: "DE", CountryName: "Germany", Address: "789 Branch St, Munich"},
+				}, nil)
+			},
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponse:   `{"address":"456 Main St, Berlin","bankName":"Deutsche Bank","branches":[{"address":"789 Branch St, Munich","bankName":"Deutsche Bank Branch","countryISO2":"DE","isHeadquarter":false,"swiftCode":"DEUTDE22XXX"}],"countryISO2":"DE","countryName":"Germany","isHeadquarter":true,"swiftCode":"DEUTDE11XXX"}`,
+		},
 		{
 			Name:      "Valid SWIFT code - not found",
 			SwiftCode: "ABCDEF12XXX",
@@ -63,7 +126,7 @@ func GetSwiftCodeTestCases() []SwiftCodeTestCase {
 				repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, errors.New("not found"))
 			},
 			ExpectedStatusCode: http.StatusNotFound,
-			ExpectedResponse:   `{"message":"SWIFT code not found"}`,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code ABCDEF12XXX exists","instance":"/v1/swift-codes/ABCDEF12XXX","code":"swift-code-not-found"}`,
 		},
 		{
 			Name:      "Invalid SWIFT code format",
@@ -72,7 +135,7 @@ func GetSwiftCodeTestCases() []SwiftCodeTestCase {
 
 			},
 			ExpectedStatusCode: http.StatusBadRequest,
-			ExpectedResponse:   `{"message":"Invalid SWIFT code format."}`,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:invalid-swift-code","title":"Invalid SWIFT code","status":400,"detail":"SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code","instance":"/v1/swift-codes/INVALID","code":"invalid-swift-code"}`,
 		},
 		{
 			Name:      "Headquarter without branches",
@@ -92,5 +155,19 @@ func GetSwiftCodeTestCases() []SwiftCodeTestCase {
 			ExpectedStatusCode: http.StatusOK,
 			ExpectedResponse:   `{"address":"1 Churchill Place, London","bankName":"Barclays Bank","countryISO2":"GB","countryName":"United Kingdom","isHeadquarter":true,"swiftCode":"BARCGB22XXX"}`,
 		},
+		{
+			Name:               "Include deleted without administrator access",
+			SwiftCode:          "ABCDUS12XXX?includeDeleted=true",
+			SetupMocks:         func(repo *mockRep.SwiftRepository) {},
+			ExpectedStatusCode: http.StatusForbidden,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:forbidden","title":"Forbidden","status":403,"detail":"Only administrators may include deleted SWIFT codes","instance":"/v1/swift-codes/ABCDUS12XXX","code":"forbidden"}`,
+		},
+		{
+			Name:               "Invalid includeDeleted flag",
+			SwiftCode:          "ABCDUS12XXX?includeDeleted=maybe",
+			SetupMocks:         func(repo *mockRep.SwiftRepository) {},
+			ExpectedStatusCode: http.StatusBadRequest,
+			ExpectedResponse:   `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"includeDeleted must be true or false","instance":"/v1/swift-codes/ABCDUS12XXX","code":"invalid-parameter"}`,
+		},
 	}
 }
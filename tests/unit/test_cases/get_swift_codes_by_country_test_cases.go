@@ -2,9 +2,9 @@ package test_cases
 
 import (
 	"net/http"
+	"swift-codes-api/repositories/interfaces"
 
 	"github.com/stretchr/testify/mock"
-	"go.mongodb.org/mongo-driver/mongo"
 
 	"swift-codes-api/models"
 	mockRepo "swift-codes-api/repositories/mock"
@@ -113,14 +113,14 @@ func GetCountryTestCases() []CountryTestCase {
 			CountryISO2:      "USA",
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid country code format. Must be a 2-letter ISO country code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-country-code","title":"Invalid country code","status":400,"detail":"Country code must be a 2-letter ISO 3166-1 alpha-2 code","instance":"/v1/swift-codes/country/USA","code":"invalid-country-code"}`,
 		},
 		{
 			Name:             "Invalid country code format (numbers)",
 			CountryISO2:      "12",
 			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
 			ExpectedStatus:   http.StatusBadRequest,
-			ExpectedResponse: `{"message":"Invalid country code format. Must be a 2-letter ISO country code"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-country-code","title":"Invalid country code","status":400,"detail":"Country code must be a 2-letter ISO 3166-1 alpha-2 code","instance":"/v1/swift-codes/country/12","code":"invalid-country-code"}`,
 		},
 		{
 			Name:        "Country with no banks",
@@ -129,11 +129,18 @@ func GetCountryTestCases() []CountryTestCase {
 				repo.On("FindByCountryISO2", mock.Anything, "ZZ").Return(
 					[]models.SwiftCode{},
 					"",
-					mongo.ErrNoDocuments,
+					interfaces.ErrNotFound,
 				)
 			},
 			ExpectedStatus:   http.StatusNotFound,
-			ExpectedResponse: `{"message":"No SWIFT codes found for this country"}`,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:country-not-found","title":"Country not found","status":404,"detail":"No SWIFT codes found for country ZZ","instance":"/v1/swift-codes/country/ZZ","code":"country-not-found"}`,
+		},
+		{
+			Name:             "Include deleted without administrator access",
+			CountryISO2:      "US?includeDeleted=true",
+			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
+			ExpectedStatus:   http.StatusForbidden,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:forbidden","title":"Forbidden","status":403,"detail":"Only administrators may include deleted SWIFT codes","instance":"/v1/swift-codes/country/US","code":"forbidden"}`,
 		},
 	}
 }
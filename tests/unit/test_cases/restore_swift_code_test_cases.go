@@ -0,0 +1,56 @@
+package test_cases
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"swift-codes-api/repositories/interfaces"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type RestoreSwiftCodeTestCase struct {
+	Name             string
+	SwiftCode        string
+	SetupMocks       func(repository *mockRepo.SwiftRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetRestoreSwiftCodeTestCases() []RestoreSwiftCodeTestCase {
+	return []RestoreSwiftCodeTestCase{
+		{
+			Name:      "Successful restore",
+			SwiftCode: "ABCDUS12XXX",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("RestoreSwiftCode", mock.Anything, "ABCDUS12XXX").Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code restored successfully"}`,
+		},
+		{
+			Name:             "Invalid SWIFT code format",
+			SwiftCode:        "INVALID",
+			SetupMocks:       func(repo *mockRepo.SwiftRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-swift-code","title":"Invalid SWIFT code","status":400,"detail":"SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code","instance":"/v1/swift-codes/INVALID/restore","code":"invalid-swift-code"}`,
+		},
+		{
+			Name:      "SWIFT code not deleted",
+			SwiftCode: "ABCDJP12XXX",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("RestoreSwiftCode", mock.Anything, "ABCDJP12XXX").Return(interfaces.ErrNotFound)
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No deleted SWIFT code ABCDJP12XXX exists","instance":"/v1/swift-codes/ABCDJP12XXX/restore","code":"swift-code-not-found"}`,
+		},
+		{
+			Name:      "Restore operation failed",
+			SwiftCode: "DEUTDE11XXX",
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("RestoreSwiftCode", mock.Anything, "DEUTDE11XXX").Return(errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to restore SWIFT code","instance":"/v1/swift-codes/DEUTDE11XXX/restore","code":"internal-error"}`,
+		},
+	}
+}
@@ -0,0 +1,83 @@
+package test_cases
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type StreamEventsTestCase struct {
+	Name           string
+	Query          string
+	LastEventID    string
+	SetupMocks     func(repo *mockRepo.EventRepository)
+	Publish        []models.Event
+	ExpectedStatus int
+	ExpectedBody   string
+}
+
+func GetStreamEventsTestCases() []StreamEventsTestCase {
+	timestamp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	gbCreated := models.Event{Type: models.EventTypeCreated, SwiftCode: "BARCGB22XXX", CountryISO2: "GB", Timestamp: timestamp}
+	deRestored := models.Event{Type: models.EventTypeRestored, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE", Timestamp: timestamp}
+
+	return []StreamEventsTestCase{
+		{
+			Name:        "Resume from Last-Event-ID replays the log",
+			LastEventID: "10",
+			SetupMocks: func(repo *mockRepo.EventRepository) {
+				repo.On("FindAfter", mock.Anything, int64(10), "", int64(500)).Return([]models.Event{
+					{ID: 11, Type: models.EventTypeCreated, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE", Timestamp: timestamp},
+					{ID: 12, Type: models.EventTypeDeleted, SwiftCode: "BARCGB22XXX", CountryISO2: "GB", Timestamp: timestamp},
+				}, nil).Once()
+				repo.On("FindAfter", mock.Anything, mock.Anything, "", int64(500)).Return([]models.Event{}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody: "id:11\nevent:swift-code.created\ndata:{\"id\":11,\"type\":\"swift-code.created\",\"swiftCode\":\"DEUTDE11XXX\",\"countryISO2\":\"DE\",\"record\":null,\"timestamp\":\"2026-03-01T12:00:00Z\"}\n\n" +
+				"id:12\nevent:swift-code.deleted\ndata:{\"id\":12,\"type\":\"swift-code.deleted\",\"swiftCode\":\"BARCGB22XXX\",\"countryISO2\":\"GB\",\"record\":null,\"timestamp\":\"2026-03-01T12:00:00Z\"}\n\n",
+		},
+		{
+			Name:  "Live events are filtered by country",
+			Query: "?country=de",
+			SetupMocks: func(repo *mockRepo.EventRepository) {
+				repo.On("LatestID", mock.Anything).Return(int64(20), nil)
+				repo.On("FindAfter", mock.Anything, int64(20), "DE", int64(500)).Return([]models.Event{}, nil)
+				for id, event := range map[int64]models.Event{21: gbCreated, 22: deRestored} {
+					logged := event
+					logged.ID = id
+					repo.On("Append", mock.Anything, event).Return(logged, nil)
+				}
+			},
+			Publish:        []models.Event{gbCreated, deRestored},
+			ExpectedStatus: http.StatusOK,
+			ExpectedBody:   "id:22\nevent:swift-code.restored\ndata:{\"id\":22,\"type\":\"swift-code.restored\",\"swiftCode\":\"DEUTDE11XXX\",\"countryISO2\":\"DE\",\"record\":null,\"timestamp\":\"2026-03-01T12:00:00Z\"}\n\n",
+		},
+		{
+			Name:           "Invalid Last-Event-ID",
+			LastEventID:    "abc",
+			SetupMocks:     func(repo *mockRepo.EventRepository) {},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"Last-Event-ID must be a non-negative integer","instance":"/v1/events","code":"invalid-parameter"}`,
+		},
+		{
+			Name:           "Invalid country filter",
+			Query:          "?country=DEU",
+			SetupMocks:     func(repo *mockRepo.EventRepository) {},
+			ExpectedStatus: http.StatusBadRequest,
+			ExpectedBody:   `{"type":"urn:swift-codes-api:problem:invalid-country-code","title":"Invalid country code","status":400,"detail":"Country code must be a 2-letter ISO 3166-1 alpha-2 code","instance":"/v1/events","code":"invalid-country-code"}`,
+		},
+		{
+			Name: "Event log unavailable",
+			SetupMocks: func(repo *mockRepo.EventRepository) {
+				repo.On("LatestID", mock.Anything).Return(int64(0), errors.New("database error"))
+			},
+			ExpectedStatus: http.StatusInternalServerError,
+			ExpectedBody:   `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to open event stream","instance":"/v1/events","code":"internal-error"}`,
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package test_cases
+
+import (
+	"errors"
+	"net/http"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type SwiftCodeHistoryTestCase struct {
+	Name             string
+	Path             string
+	SetupMocks       func(versions *mockRepo.VersionRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetSwiftCodeHistoryTestCases() []SwiftCodeHistoryTestCase {
+	validFrom := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	validTo := time.Date(2026, 2, 20, 15, 30, 0, 0, time.UTC)
+	record := models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+	}
+
+	return []SwiftCodeHistoryTestCase{
+		{
+			Name: "History lists versions",
+			Path: "/v1/swift-codes/DEUTDE11XXX/history",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				versions.On("FindHistory", mock.Anything, "DEUTDE11XXX").Return([]models.SwiftCodeVersion{
+					{SwiftCode: "DEUTDE11XXX", Version: 1, ValidFrom: validFrom, ValidTo: &validTo, Record: record},
+					{SwiftCode: "DEUTDE11XXX", Version: 2, ValidFrom: validTo, Deleted: true, Record: record},
+				}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"swiftCode": "DEUTDE11XXX",
+				"versions": [
+					{
+						"swiftCode": "DEUTDE11XXX",
+						"version": 1,
+						"validFrom": "2026-01-10T09:00:00Z",
+						"validTo": "2026-02-20T15:30:00Z",
+						"deleted": false,
+						"record": {"swiftCode":"DEUTDE11XXX","bankName":"Deutsche Bank","countryISO2":"DE","countryName":"Germany","address":"456 Main St, Berlin","isHeadquarter":true}
+					},
+					{
+						"swiftCode": "DEUTDE11XXX",
+						"version": 2,
+						"validFrom": "2026-02-20T15:30:00Z",
+						"validTo": null,
+						"deleted": true,
+						"record": {"swiftCode":"DEUTDE11XXX","bankName":"Deutsche Bank","countryISO2":"DE","countryName":"Germany","address":"456 Main St, Berlin","isHeadquarter":true}
+					}
+				]
+			}`,
+		},
+		{
+			Name: "History not found",
+			Path: "/v1/swift-codes/ABCDUS12XXX/history",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				versions.On("FindHistory", mock.Anything, "ABCDUS12XXX").Return(nil, interfaces.ErrNotFound)
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:history-not-found","title":"History not found","status":404,"detail":"No history found for SWIFT code ABCDUS12XXX","instance":"/v1/swift-codes/ABCDUS12XXX/history","code":"history-not-found"}`,
+		},
+		{
+			Name: "History repository error",
+			Path: "/v1/swift-codes/ABCDUS12XXX/history",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				versions.On("FindHistory", mock.Anything, "ABCDUS12XXX").Return(nil, errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to retrieve SWIFT code history","instance":"/v1/swift-codes/ABCDUS12XXX/history","code":"internal-error"}`,
+		},
+		{
+			Name: "Lookup as of a date answers from history",
+			Path: "/v1/swift-codes/DEUTDE11XXX?asOf=2026-02-01",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				asOf := time.Date(2026, 2, 1, 23, 59, 59, int(999*time.Millisecond), time.UTC)
+				versions.On("FindByCodeAsOf", mock.Anything, "DEUTDE11XXX", asOf).Return(&record, nil)
+				versions.On("FindBranchesByPrefixAsOf", mock.Anything, "DEUTDE11", asOf).Return([]models.SwiftCode{}, nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"address":"456 Main St, Berlin","bankName":"Deutsche Bank","branches":[],"countryISO2":"DE","countryName":"Germany","isHeadquarter":true,"swiftCode":"DEUTDE11XXX"}`,
+		},
+		{
+			Name: "Lookup as of a timestamp before the code existed",
+			Path: "/v1/swift-codes/DEUTDE11XXX?asOf=2025-12-31T12:00:00Z",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				versions.On("FindByCodeAsOf", mock.Anything, "DEUTDE11XXX", time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)).Return(nil, interfaces.ErrNotFound)
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code DEUTDE11XXX exists","instance":"/v1/swift-codes/DEUTDE11XXX","code":"swift-code-not-found"}`,
+		},
+		{
+			Name: "Country as of a timestamp answers from history",
+			Path: "/v1/swift-codes/country/de?asOf=2026-02-01T00:00:00%2B01:00",
+			SetupMocks: func(versions *mockRepo.VersionRepository) {
+				versions.On("FindByCountryISO2AsOf", mock.Anything, "DE", time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)).Return(
+					[]models.SwiftCode{record}, "Germany", nil,
+				)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"countryISO2":"DE","countryName":"Germany","swiftCodes":[{"swiftCode":"DEUTDE11XXX","bankName":"Deutsche Bank","countryISO2":"DE","countryName":"Germany","address":"456 Main St, Berlin","isHeadquarter":true}]}`,
+		},
+		{
+			Name:             "Invalid asOf",
+			Path:             "/v1/swift-codes/DEUTDE11XXX?asOf=last-week",
+			SetupMocks:       func(versions *mockRepo.VersionRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:invalid-parameter","title":"Invalid parameter","status":400,"detail":"asOf must be an RFC 3339 timestamp or a YYYY-MM-DD date","instance":"/v1/swift-codes/DEUTDE11XXX","code":"invalid-parameter"}`,
+		},
+	}
+}
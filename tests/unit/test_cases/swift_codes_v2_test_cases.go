@@ -0,0 +1,95 @@
+package test_cases
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/models"
+	mockRep "swift-codes-api/repositories/mock"
+)
+
+type SwiftCodesV2TestCase struct {
+	Name             string
+	Path             string
+	SetupMocks       func(repo *mockRep.SwiftRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetSwiftCodesV2TestCases() []SwiftCodesV2TestCase {
+	headquarter := &models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+		UpdatedAt:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Revision:      3,
+	}
+	branch := models.SwiftCode{
+		SwiftCode:   "DEUTDE11MUN",
+		SwiftPrefix: "DEUTDE11",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "789 Branch St, Munich",
+		Revision:    1,
+	}
+
+	return []SwiftCodesV2TestCase{
+		{
+			Name: "Headquarter with branches",
+			Path: "/v2/swift-codes/DEUTDE11XXX",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return([]models.SwiftCode{branch}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"DEUTDE11XXX","kind":"headquarter","bankName":"Deutsche Bank","address":"456 Main St, Berlin","country":{"iso2":"DE","name":"Germany"},"revision":3,"updatedAt":"2026-03-01T12:00:00Z",` +
+				`"branches":[{"swiftCode":"DEUTDE11MUN","bankName":"Deutsche Bank","address":"789 Branch St, Munich","revision":1}]}`,
+		},
+		{
+			Name: "Branch names its headquarter",
+			Path: "/v2/swift-codes/DEUTDE11MUN",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11MUN").Return(&branch, nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"swiftCode":"DEUTDE11MUN","kind":"branch","bankName":"Deutsche Bank","address":"789 Branch St, Munich","country":{"iso2":"DE","name":"Germany"},"headquarter":"DEUTDE11XXX","revision":1}`,
+		},
+		{
+			Name: "SWIFT code not found",
+			Path: "/v2/swift-codes/ABCDEF12XXX",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "ABCDEF12XXX").Return(nil, errors.New("not found"))
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code ABCDEF12XXX exists","instance":"/v2/swift-codes/ABCDEF12XXX","code":"swift-code-not-found"}`,
+		},
+		{
+			Name: "Country listing",
+			Path: "/v2/swift-codes/country/de",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCountryISO2", mock.Anything, "DE").Return([]models.SwiftCode{*headquarter, branch}, "Germany", nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{"country":{"iso2":"DE","name":"Germany"},"swiftCodes":[` +
+				`{"swiftCode":"DEUTDE11XXX","kind":"headquarter","bankName":"Deutsche Bank","address":"456 Main St, Berlin","country":{"iso2":"DE","name":"Germany"},"revision":3,"updatedAt":"2026-03-01T12:00:00Z"},` +
+				`{"swiftCode":"DEUTDE11MUN","kind":"branch","bankName":"Deutsche Bank","address":"789 Branch St, Munich","country":{"iso2":"DE","name":"Germany"},"headquarter":"DEUTDE11XXX","revision":1}]}`,
+		},
+		{
+			Name: "Country with no banks",
+			Path: "/v2/swift-codes/country/ZZ",
+			SetupMocks: func(repo *mockRep.SwiftRepository) {
+				repo.On("FindByCountryISO2", mock.Anything, "ZZ").Return(nil, "", errors.New("not found"))
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:country-not-found","title":"Country not found","status":404,"detail":"No SWIFT codes found for country ZZ","instance":"/v2/swift-codes/country/ZZ","code":"country-not-found"}`,
+		},
+	}
+}
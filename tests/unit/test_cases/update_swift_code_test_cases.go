@@ -0,0 +1,167 @@
+package test_cases
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"swift-codes-api/dto"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepo "swift-codes-api/repositories/mock"
+	"swift-codes-api/utils"
+)
+
+type UpdateSwiftCodeTestCase struct {
+	Name             string
+	SwiftCode        string
+	IfMatch          string
+	RequestBody      string
+	SetupMocks       func(repository *mockRepo.SwiftRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetUpdateSwiftCodeTestCases() []UpdateSwiftCodeTestCase {
+	headquarter := &models.SwiftCode{
+		SwiftCode:     "DEUTDE11XXX",
+		SwiftPrefix:   "DEUTDE11",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "456 Main St, Berlin",
+		IsHeadquarter: true,
+		Revision:      2,
+	}
+	branches := []models.SwiftCode{
+		{SwiftCode: "DEUTDE11MUN", BankName: "Deutsche Bank", CountryISO2: "DE", CountryName: "Germany", Address: "789 Branch St, Munich", Revision: 1},
+	}
+	lookup, _ := json.Marshal(dto.NewHeadquarterResponse(*headquarter, branches, false))
+	lookupV2, _ := json.Marshal(dto.NewSwiftCodeV2(*headquarter, branches))
+
+	body := `{
+		"bankName": "Deutsche Bank AG",
+		"countryISO2": "de",
+		"countryName": "Germany",
+		"address": "Taunusanlage 12, Frankfurt",
+		"isHeadquarter": true
+	}`
+	updated := mock.MatchedBy(func(sc models.SwiftCode) bool {
+		return sc.SwiftCode == "DEUTDE11XXX" && sc.CountryISO2 == "DE" && sc.BankName == "Deutsche Bank AG"
+	})
+
+	return []UpdateSwiftCodeTestCase{
+		{
+			Name:        "Unconditional update",
+			SwiftCode:   "DEUTDE11XXX",
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, interfaces.AnyRevision).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code updated successfully"}`,
+		},
+		{
+			Name:        "If-Match with current revision",
+			SwiftCode:   "DEUTDE11XXX",
+			IfMatch:     "2",
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, int64(2)).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code updated successfully"}`,
+		},
+		{
+			Name:        "If-Match with headquarter lookup ETag",
+			SwiftCode:   "DEUTDE11XXX",
+			IfMatch:     utils.ETag(lookup),
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, int64(2)).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code updated successfully"}`,
+		},
+		{
+			Name:        "If-Match with v2 lookup ETag",
+			SwiftCode:   "DEUTDE11XXX",
+			IfMatch:     utils.ETag(lookupV2),
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, int64(2)).Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"SWIFT code updated successfully"}`,
+		},
+		{
+			Name:        "If-Match with stale ETag",
+			SwiftCode:   "DEUTDE11XXX",
+			IfMatch:     `"0123456789abcdef0123456789abcdef"`,
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("FindBranchesByPrefix", mock.Anything, "DEUTDE11").Return(branches, nil)
+			},
+			ExpectedStatus:   http.StatusPreconditionFailed,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:revision-mismatch","title":"SWIFT code has been modified","status":412,"detail":"SWIFT code has been modified since it was retrieved","instance":"/v1/swift-codes/DEUTDE11XXX","code":"revision-mismatch"}`,
+		},
+		{
+			Name:        "Revision changes concurrently",
+			SwiftCode:   "DEUTDE11XXX",
+			IfMatch:     `"2"`,
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, int64(2)).Return(interfaces.ErrRevisionMismatch)
+			},
+			ExpectedStatus:   http.StatusPreconditionFailed,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:revision-mismatch","title":"SWIFT code has been modified","status":412,"detail":"SWIFT code has been modified since it was retrieved","instance":"/v1/swift-codes/DEUTDE11XXX","code":"revision-mismatch"}`,
+		},
+		{
+			Name:        "SWIFT code not found",
+			SwiftCode:   "DEUTDE11XXX",
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(nil, interfaces.ErrNotFound)
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:swift-code-not-found","title":"SWIFT code not found","status":404,"detail":"No SWIFT code DEUTDE11XXX exists","instance":"/v1/swift-codes/DEUTDE11XXX","code":"swift-code-not-found"}`,
+		},
+		{
+			Name:        "SWIFT code in body does not match URL",
+			SwiftCode:   "DEUTDE11XXX",
+			RequestBody: `{"swiftCode": "BARCGB22XXX", "bankName": "Barclays", "countryISO2": "GB", "countryName": "United Kingdom", "address": "London"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes/DEUTDE11XXX","code":"validation-failed","errors":[{"field":"swiftCode","code":"mismatch","message":"Must match the SWIFT code in the URL"}]}`,
+		},
+		{
+			Name:        "Missing required fields",
+			SwiftCode:   "DEUTDE11XXX",
+			RequestBody: `{"bankName": "Deutsche Bank AG"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/swift-codes/DEUTDE11XXX","code":"validation-failed","errors":[{"field":"address","code":"required","message":"Must not be empty"},{"field":"countryISO2","code":"required","message":"Must not be empty"},{"field":"countryName","code":"required","message":"Must not be empty"}]}`,
+		},
+		{
+			Name:        "Update operation failed",
+			SwiftCode:   "DEUTDE11XXX",
+			RequestBody: body,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCode", mock.Anything, "DEUTDE11XXX").Return(headquarter, nil)
+				repo.On("UpdateSwiftCode", mock.Anything, updated, interfaces.AnyRevision).Return(errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to update SWIFT code","instance":"/v1/swift-codes/DEUTDE11XXX","code":"internal-error"}`,
+		},
+	}
+}
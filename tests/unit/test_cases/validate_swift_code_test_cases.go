@@ -0,0 +1,172 @@
+package test_cases
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type ValidateSwiftCodeTestCase struct {
+	Name             string
+	RequestBody      string
+	SetupMocks       func(repository *mockRepo.SwiftRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetValidateSwiftCodeTestCases() []ValidateSwiftCodeTestCase {
+	headquarter := models.SwiftCode{
+		SwiftCode:     "DEUTDEFFXXX",
+		SwiftPrefix:   "DEUTDEFF",
+		BankName:      "Deutsche Bank",
+		CountryISO2:   "DE",
+		CountryName:   "Germany",
+		Address:       "Taunusanlage 12, Frankfurt",
+		IsHeadquarter: true,
+		Revision:      1,
+	}
+	branch := models.SwiftCode{
+		SwiftCode:   "DEUTDEFF500",
+		SwiftPrefix: "DEUTDEFF",
+		BankName:    "Deutsche Bank",
+		CountryISO2: "DE",
+		CountryName: "Germany",
+		Address:     "Bockenheimer Landstrasse, Frankfurt",
+		Revision:    1,
+	}
+	germany := []models.SwiftCode{
+		headquarter,
+		branch,
+		{SwiftCode: "COBADEFFXXX", SwiftPrefix: "COBADEFF", CountryISO2: "DE", IsHeadquarter: true},
+	}
+	validSegments := `[
+		{"segment":"institution","value":"DEUT","valid":true},
+		{"segment":"country","value":"DE","valid":true},
+		{"segment":"location","value":"FF","valid":true},
+		{"segment":"branch","value":"500","valid":true}
+	]`
+
+	return []ValidateSwiftCodeTestCase{
+		{
+			Name:        "Existing branch",
+			RequestBody: `{"code": "deutdeff500"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFF500", "DEUTDEFFXXX"}).Return([]models.SwiftCode{headquarter, branch}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"input":"deutdeff500","swiftCode":"DEUTDEFF500","valid":true,"length":{"valid":true},
+				"segments":` + validSegments + `,
+				"country":{"iso2":"DE","exists":true},
+				"directory":{"found":true,"record":{"swiftCode":"DEUTDEFF500","isHeadquarter":false,"bankName":"Deutsche Bank","address":"Bockenheimer Landstrasse, Frankfurt","countryISO2":"DE","countryName":"Germany","revision":1},"headquarter":"DEUTDEFFXXX","headquarterFound":true},
+				"suggestions":[]
+			}`,
+		},
+		{
+			Name:        "Unknown branch of a known bank",
+			RequestBody: `{"code": "DEUTDEFF501"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, []string{"DEUTDEFF501", "DEUTDEFFXXX"}).Return([]models.SwiftCode{headquarter}, nil)
+				repo.On("FindByCountryISO2", mock.Anything, "DE").Return(germany, "Germany", nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"input":"DEUTDEFF501","swiftCode":"DEUTDEFF501","valid":true,"length":{"valid":true},
+				"segments":[
+					{"segment":"institution","value":"DEUT","valid":true},
+					{"segment":"country","value":"DE","valid":true},
+					{"segment":"location","value":"FF","valid":true},
+					{"segment":"branch","value":"501","valid":true}
+				],
+				"country":{"iso2":"DE","exists":true},
+				"directory":{"found":false,"headquarter":"DEUTDEFFXXX","headquarterFound":true},
+				"suggestions":["DEUTDEFF500"]
+			}`,
+		},
+		{
+			Name:        "Code with a missing character",
+			RequestBody: `{"code": "DEUTDEF500"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCountryISO2", mock.Anything, "DE").Return(germany, "Germany", nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"input":"DEUTDEF500","swiftCode":"DEUTDEF500","valid":false,
+				"length":{"valid":false,"message":"Must be 8 or 11 characters, got 10"},
+				"segments":[
+					{"segment":"institution","value":"DEUT","valid":true},
+					{"segment":"country","value":"DE","valid":true},
+					{"segment":"location","value":"F5","valid":true},
+					{"segment":"branch","value":"00","valid":false,"rule":"length","message":"Must be 3 characters"}
+				],
+				"country":{"iso2":"DE","exists":true},
+				"suggestions":["DEUTDEFF500"]
+			}`,
+		},
+		{
+			Name:        "Invalid segments and unknown country",
+			RequestBody: `{"code": "DE1TQQ-F"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"input":"DE1TQQ-F","swiftCode":"DE1TQQ-F","valid":false,"length":{"valid":true},
+				"segments":[
+					{"segment":"institution","value":"DE1T","valid":false,"rule":"letters","message":"Must contain only letters A-Z"},
+					{"segment":"country","value":"QQ","valid":true},
+					{"segment":"location","value":"-F","valid":false,"rule":"alphanumeric","message":"Must contain only letters A-Z and digits"}
+				],
+				"country":{"iso2":"QQ","exists":false},
+				"suggestions":[]
+			}`,
+		},
+		{
+			Name:        "BIC8 of a country without codes",
+			RequestBody: `{"code": "ABCDFRPP"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, []string{"ABCDFRPPXXX"}).Return([]models.SwiftCode{}, nil)
+				repo.On("FindByCountryISO2", mock.Anything, "FR").Return(nil, "", interfaces.ErrNotFound)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{
+				"input":"ABCDFRPP","swiftCode":"ABCDFRPPXXX","valid":true,"length":{"valid":true},
+				"segments":[
+					{"segment":"institution","value":"ABCD","valid":true},
+					{"segment":"country","value":"FR","valid":true},
+					{"segment":"location","value":"PP","valid":true}
+				],
+				"country":{"iso2":"FR","exists":true},
+				"directory":{"found":false,"headquarter":"ABCDFRPPXXX","headquarterFound":false},
+				"suggestions":[]
+			}`,
+		},
+		{
+			Name:        "Missing code",
+			RequestBody: `{}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/validate","code":"validation-failed","errors":[{"field":"code","code":"required","message":"Must not be empty"}]}`,
+		},
+		{
+			Name:        "Code longer than a BIC11",
+			RequestBody: `{"code": " DEUTDEFF5000 "}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+			},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/validate","code":"validation-failed","errors":[{"field":"code","code":"format","message":"Must be at most 11 characters, got 12"}]}`,
+		},
+		{
+			Name:        "Lookup failed",
+			RequestBody: `{"code": "DEUTDEFF500"}`,
+			SetupMocks: func(repo *mockRepo.SwiftRepository) {
+				repo.On("FindByCodes", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to look up SWIFT codes","instance":"/v1/validate","code":"internal-error"}`,
+		},
+	}
+}
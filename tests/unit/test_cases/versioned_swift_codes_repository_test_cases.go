@@ -0,0 +1,119 @@
+package test_cases
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepo "swift-codes-api/repositories/mock"
+	"swift-codes-api/repositories/versioned"
+)
+
+type VersionedSwiftRepositoryTestCase struct {
+	Name          string
+	Mutate        func(ctx context.Context, repo interfaces.SwiftRepository) error
+	SetupMocks    func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository)
+	ExpectedError bool
+}
+
+func GetVersionedSwiftRepositoryTestCases() []VersionedSwiftRepositoryTestCase {
+	swiftCode := models.SwiftCode{
+		SwiftCode:     "ABCDUS12XXX",
+		SwiftPrefix:   "ABCDUS12",
+		BankName:      "Bank of America",
+		CountryISO2:   "US",
+		CountryName:   "United States",
+		Address:       "123 Main St, New York",
+		IsHeadquarter: true,
+	}
+	updatedAt := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	updated := swiftCode
+	updated.UpdatedAt = updatedAt
+	deletedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	deleted := swiftCode
+	deleted.DeletedAt = &deletedAt
+	deleted.UpdatedAt = deletedAt
+
+	return []VersionedSwiftRepositoryTestCase{
+		{
+			Name: "Create saves a live version valid from the update",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.AddSwiftCode(ctx, swiftCode)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(nil, interfaces.ErrNotFound).Once()
+				repo.On("AddSwiftCode", mock.Anything, swiftCode).Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&updated, nil).Once()
+				versions.On("SaveVersion", mock.Anything, updated, false, updatedAt).Return(nil)
+			},
+		},
+		{
+			Name: "Delete saves the record before as first version and a deleted version",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.DeleteSwiftCode(ctx, "ABCDUS12XXX", interfaces.AnyRevision)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(&updated, nil).Once()
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX", interfaces.AnyRevision).Return(nil)
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(&deleted, nil).Once()
+				versions.On("SaveInitialVersion", mock.Anything, updated, updatedAt).Return(nil).Once()
+				versions.On("SaveVersion", mock.Anything, deleted, true, deletedAt).Return(nil)
+			},
+		},
+		{
+			Name: "Restore saves a live version",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.RestoreSwiftCode(ctx, "ABCDUS12XXX")
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(&deleted, nil).Once()
+				repo.On("RestoreSwiftCode", mock.Anything, "ABCDUS12XXX").Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&updated, nil).Once()
+				versions.On("SaveInitialVersion", mock.Anything, deleted, deletedAt).Return(nil).Once()
+				versions.On("SaveVersion", mock.Anything, updated, false, updatedAt).Return(nil)
+			},
+		},
+		{
+			Name: "Failed version save fails the mutation",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.UpdateSwiftCode(ctx, swiftCode, interfaces.AnyRevision)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(nil, interfaces.ErrNotFound).Once()
+				repo.On("UpdateSwiftCode", mock.Anything, swiftCode, interfaces.AnyRevision).Return(nil)
+				repo.On("FindByCode", mock.Anything, "ABCDUS12XXX").Return(&updated, nil).Once()
+				versions.On("SaveVersion", mock.Anything, updated, false, updatedAt).Return(errors.New("database error"))
+			},
+			ExpectedError: true,
+		},
+		{
+			Name: "Failed mutation saves no version",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.DeleteSwiftCode(ctx, "ABCDUS12XXX", interfaces.AnyRevision)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				repo.On("FindByCode", mock.MatchedBy(reqctx.IncludeDeleted), "ABCDUS12XXX").Return(&updated, nil).Once()
+				repo.On("DeleteSwiftCode", mock.Anything, "ABCDUS12XXX", interfaces.AnyRevision).Return(errors.New("database error"))
+			},
+			ExpectedError: true,
+		},
+		{
+			Name: "Backfill saves a first version of every record",
+			Mutate: func(ctx context.Context, repo interfaces.SwiftRepository) error {
+				return repo.(*versioned.SwiftRepository).Backfill(ctx)
+			},
+			SetupMocks: func(repo *mockRepo.SwiftRepository, versions *mockRepo.VersionRepository) {
+				branch := updated
+				branch.SwiftCode = "ABCDUS12NYC"
+				repo.On("StreamSwiftCodes", mock.MatchedBy(reqctx.IncludeDeleted), "").Return([]models.SwiftCode{swiftCode, branch}, nil)
+				versions.On("SaveInitialVersion", mock.Anything, swiftCode, time.Unix(0, 0).UTC()).Return(nil).Once()
+				versions.On("SaveInitialVersion", mock.Anything, branch, updatedAt).Return(nil).Once()
+			},
+		},
+	}
+}
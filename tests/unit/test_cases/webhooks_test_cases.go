@@ -0,0 +1,188 @@
+package test_cases
+
+import (
+	"errors"
+	"net/http"
+	"swift-codes-api/repositories/interfaces"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"swift-codes-api/models"
+	mockRepo "swift-codes-api/repositories/mock"
+)
+
+type WebhooksTestCase struct {
+	Name             string
+	Method           string
+	Path             string
+	RequestBody      string
+	NotAdmin         bool
+	SetupMocks       func(repo *mockRepo.WebhookRepository)
+	ExpectedStatus   int
+	ExpectedResponse string
+}
+
+func GetWebhooksTestCases() []WebhooksTestCase {
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	return []WebhooksTestCase{
+		{
+			Name:   "Register webhook",
+			Method: http.MethodPost,
+			Path:   "/v1/webhooks",
+			RequestBody: `{
+				"url": "https://example.com/hooks/swift",
+				"eventTypes": ["swift-code.deleted"],
+				"countryISO2": "de",
+				"secret": "s3cret"
+			}`,
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s models.WebhookSubscription) bool {
+					return s.ID != "" && s.URL == "https://example.com/hooks/swift" &&
+						len(s.EventTypes) == 1 && s.EventTypes[0] == models.EventTypeDeleted &&
+						s.CountryISO2 == "DE" && s.Secret == "s3cret"
+				})).Return(nil)
+			},
+			ExpectedStatus: http.StatusCreated,
+		},
+		{
+			Name:             "Invalid webhook URL",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "ftp://example.com/hooks"}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"url","code":"format","message":"Must be an absolute http or https URL"}]}`,
+		},
+		{
+			Name:             "Webhook URL resolving to a loopback address",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "http://localhost:8080/hooks"}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"url","code":"format","message":"Must not point to a private, loopback or link-local address"}]}`,
+		},
+		{
+			Name:             "Webhook URL of the metadata service",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "http://169.254.169.254/latest/meta-data"}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"url","code":"format","message":"Must not point to a private, loopback or link-local address"}]}`,
+		},
+		{
+			Name:             "Webhook URL of an unknown host",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "https://nowhere.invalid/hooks"}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"url","code":"format","message":"Host could not be resolved"}]}`,
+		},
+		{
+			Name:             "Non-administrators are forbidden",
+			Method:           http.MethodGet,
+			Path:             "/v1/webhooks",
+			NotAdmin:         true,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusForbidden,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:forbidden","title":"Forbidden","status":403,"detail":"Only administrators may manage webhooks","instance":"/v1/webhooks","code":"forbidden"}`,
+		},
+		{
+			Name:             "Unknown event type",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "https://example.com/hooks", "eventTypes": ["swift-code.renamed"]}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"eventTypes[0]","code":"format","message":"Unknown event type swift-code.renamed"}]}`,
+		},
+		{
+			Name:             "Invalid country filter",
+			Method:           http.MethodPost,
+			Path:             "/v1/webhooks",
+			RequestBody:      `{"url": "https://example.com/hooks", "countryISO2": "DEU"}`,
+			SetupMocks:       func(repo *mockRepo.WebhookRepository) {},
+			ExpectedStatus:   http.StatusBadRequest,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:validation-failed","title":"Validation failed","status":400,"detail":"One or more fields are invalid","instance":"/v1/webhooks","code":"validation-failed","errors":[{"field":"countryISO2","code":"format","message":"Country code must be a 2-letter ISO 3166-1 alpha-2 code"}]}`,
+		},
+		{
+			Name:   "List webhooks hides secrets",
+			Method: http.MethodGet,
+			Path:   "/v1/webhooks",
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("FindSubscriptions", mock.Anything).Return([]models.WebhookSubscription{
+					{ID: "sub-1", URL: "https://example.com/hooks", EventTypes: []string{models.EventTypeCreated}, Secret: "s3cret", CreatedAt: createdAt},
+				}, nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"webhooks":[{"id":"sub-1","url":"https://example.com/hooks","eventTypes":["swift-code.created"],"createdAt":"2026-03-01T12:00:00Z"}]}`,
+		},
+		{
+			Name:   "Delivery log",
+			Method: http.MethodGet,
+			Path:   "/v1/webhooks/sub-1/deliveries",
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("FindSubscription", mock.Anything, "sub-1").Return(&models.WebhookSubscription{ID: "sub-1"}, nil)
+				repo.On("FindDeliveries", mock.Anything, "sub-1", int64(100)).Return([]models.WebhookDelivery{
+					{
+						ID:             "sub-1-5",
+						SubscriptionID: "sub-1",
+						Event:          models.Event{ID: 5, Type: models.EventTypeCreated, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE", Timestamp: createdAt},
+						Status:         models.DeliveryStatusDead,
+						Attempts:       8,
+						NextAttemptAt:  createdAt,
+						LastStatusCode: 500,
+						LastError:      "receiver responded with status 500",
+						CreatedAt:      createdAt,
+					},
+				}, nil)
+			},
+			ExpectedStatus: http.StatusOK,
+			ExpectedResponse: `{"deliveries":[{
+				"id":"sub-1-5",
+				"subscriptionId":"sub-1",
+				"event":{"id":5,"type":"swift-code.created","swiftCode":"DEUTDE11XXX","countryISO2":"DE","record":null,"timestamp":"2026-03-01T12:00:00Z"},
+				"status":"dead",
+				"attempts":8,
+				"nextAttemptAt":"2026-03-01T12:00:00Z",
+				"lastStatusCode":500,
+				"lastError":"receiver responded with status 500",
+				"createdAt":"2026-03-01T12:00:00Z"
+			}]}`,
+		},
+		{
+			Name:   "Delivery log of unknown webhook",
+			Method: http.MethodGet,
+			Path:   "/v1/webhooks/missing/deliveries",
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("FindSubscription", mock.Anything, "missing").Return(nil, interfaces.ErrNotFound)
+			},
+			ExpectedStatus:   http.StatusNotFound,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:webhook-not-found","title":"Webhook not found","status":404,"detail":"No webhook missing exists","instance":"/v1/webhooks/missing/deliveries","code":"webhook-not-found"}`,
+		},
+		{
+			Name:   "Delete webhook",
+			Method: http.MethodDelete,
+			Path:   "/v1/webhooks/sub-1",
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("DeleteSubscription", mock.Anything, "sub-1").Return(nil)
+			},
+			ExpectedStatus:   http.StatusOK,
+			ExpectedResponse: `{"message":"Webhook deleted successfully"}`,
+		},
+		{
+			Name:   "Delete webhook fails",
+			Method: http.MethodDelete,
+			Path:   "/v1/webhooks/sub-1",
+			SetupMocks: func(repo *mockRepo.WebhookRepository) {
+				repo.On("DeleteSubscription", mock.Anything, "sub-1").Return(errors.New("database error"))
+			},
+			ExpectedStatus:   http.StatusInternalServerError,
+			ExpectedResponse: `{"type":"urn:swift-codes-api:problem:internal-error","title":"Internal server error","status":500,"detail":"Failed to delete webhook","instance":"/v1/webhooks/sub-1","code":"internal-error"}`,
+		},
+	}
+}
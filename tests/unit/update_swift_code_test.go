@@ -0,0 +1,44 @@
+package unit
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/config"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestUpdateSwiftCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetUpdateSwiftCodeTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewSwiftHandler(config.Config{}, mockRepo)
+			router := gin.Default()
+			router.PUT("/v1/swift-codes/:swift-code", handler.UpdateSwiftCode)
+
+			req := httptest.NewRequest(http.MethodPut, "/v1/swift-codes/"+tc.SwiftCode, bytes.NewBufferString(tc.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.IfMatch != "" {
+				req.Header.Set("If-Match", tc.IfMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
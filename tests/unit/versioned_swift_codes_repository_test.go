@@ -0,0 +1,31 @@
+package unit
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/repositories/versioned"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+func TestVersionedSwiftRepository(t *testing.T) {
+	for _, tc := range test_cases.GetVersionedSwiftRepositoryTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.SwiftRepository)
+			mockVersions := new(mockRepos.VersionRepository)
+			tc.SetupMocks(mockRepo, mockVersions)
+
+			err := tc.Mutate(context.Background(), versioned.NewSwiftRepository(mockRepo, mockVersions))
+
+			if tc.ExpectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockRepo.AssertExpectations(t)
+			mockVersions.AssertExpectations(t)
+		})
+	}
+}
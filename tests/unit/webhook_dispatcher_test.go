@@ -0,0 +1,212 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"swift-codes-api/internal/events"
+	"swift-codes-api/internal/webhooks"
+	"swift-codes-api/models"
+	"swift-codes-api/repositories/interfaces"
+	mockRepos "swift-codes-api/repositories/mock"
+	"testing"
+	"time"
+)
+
+func TestWebhookDispatcherDeliverDue(t *testing.T) {
+	event := models.Event{ID: 5, Type: models.EventTypeCreated, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE"}
+	subscription := &models.WebhookSubscription{ID: "sub-1", Secret: "s3cret"}
+
+	tests := []struct {
+		Name           string
+		ReceiverStatus int
+		Attempts       int
+		Check          func(t *testing.T, delivery models.WebhookDelivery)
+	}{
+		{
+			Name:           "Successful delivery",
+			ReceiverStatus: http.StatusNoContent,
+			Check: func(t *testing.T, delivery models.WebhookDelivery) {
+				assert.Equal(t, models.DeliveryStatusDelivered, delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, http.StatusNoContent, delivery.LastStatusCode)
+				assert.NotNil(t, delivery.DeliveredAt)
+			},
+		},
+		{
+			Name:           "Failed delivery is retried with backoff",
+			ReceiverStatus: http.StatusInternalServerError,
+			Attempts:       2,
+			Check: func(t *testing.T, delivery models.WebhookDelivery) {
+				assert.Equal(t, models.DeliveryStatusPending, delivery.Status)
+				assert.Equal(t, 3, delivery.Attempts)
+				assert.Equal(t, "receiver responded with status 500", delivery.LastError)
+				assert.WithinDuration(t, time.Now().Add(webhooks.Backoff(3)), delivery.NextAttemptAt, 5*time.Second)
+			},
+		},
+		{
+			Name:           "Delivery is dead-lettered after the last attempt",
+			ReceiverStatus: http.StatusBadGateway,
+			Attempts:       webhooks.MaxAttempts - 1,
+			Check: func(t *testing.T, delivery models.WebhookDelivery) {
+				assert.Equal(t, models.DeliveryStatusDead, delivery.Status)
+				assert.Equal(t, webhooks.MaxAttempts, delivery.Attempts)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.True(t, webhooks.Verify("s3cret", r.Header.Get(webhooks.TimestampHeader), body, r.Header.Get(webhooks.SignatureHeader)))
+				assert.Equal(t, models.EventTypeCreated, r.Header.Get(webhooks.EventHeader))
+				assert.Equal(t, "sub-1-5", r.Header.Get(webhooks.DeliveryHeader))
+
+				var received models.Event
+				assert.NoError(t, json.Unmarshal(body, &received))
+				assert.Equal(t, "DEUTDE11XXX", received.SwiftCode)
+
+				w.WriteHeader(tc.ReceiverStatus)
+			}))
+			defer receiver.Close()
+
+			sub := *subscription
+			sub.URL = receiver.URL
+
+			mockRepo := new(mockRepos.WebhookRepository)
+			mockRepo.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&models.WebhookDelivery{
+				ID:             "sub-1-5",
+				SubscriptionID: "sub-1",
+				Event:          event,
+				Status:         models.DeliveryStatusPending,
+				Attempts:       tc.Attempts,
+			}, nil).Once()
+			mockRepo.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(nil, interfaces.ErrNotFound)
+			mockRepo.On("FindSubscription", mock.Anything, "sub-1").Return(&sub, nil)
+
+			var updated models.WebhookDelivery
+			mockRepo.On("UpdateDelivery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(models.WebhookDelivery)
+			}).Return(nil)
+
+			dispatcher := webhooks.NewDispatcher(mockRepo, nil, receiver.Client())
+			require.NoError(t, dispatcher.DeliverDue(context.Background()))
+
+			tc.Check(t, updated)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookDispatcherDeletedSubscription(t *testing.T) {
+	mockRepo := new(mockRepos.WebhookRepository)
+	mockRepo.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(&models.WebhookDelivery{
+		ID:             "gone-1",
+		SubscriptionID: "gone",
+		Status:         models.DeliveryStatusPending,
+	}, nil).Once()
+	mockRepo.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(nil, interfaces.ErrNotFound)
+	mockRepo.On("FindSubscription", mock.Anything, "gone").Return(nil, interfaces.ErrNotFound)
+	mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.Status == models.DeliveryStatusDead && d.Attempts == 0
+	})).Return(nil)
+
+	dispatcher := webhooks.NewDispatcher(mockRepo, nil, http.DefaultClient)
+	require.NoError(t, dispatcher.DeliverDue(context.Background()))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcherEnqueue(t *testing.T) {
+	mockRepo := new(mockRepos.WebhookRepository)
+	mockRepo.On("FindSubscriptions", mock.Anything).Return([]models.WebhookSubscription{
+		{ID: "all", EventTypes: []string{models.EventTypeCreated, models.EventTypeDeleted}},
+		{ID: "german", EventTypes: []string{models.EventTypeCreated}, CountryISO2: "DE"},
+		{ID: "polish", EventTypes: []string{models.EventTypeCreated}, CountryISO2: "PL"},
+		{ID: "deletions", EventTypes: []string{models.EventTypeDeleted}},
+	}, nil)
+	mockRepo.On("EnqueueDelivery", mock.Anything, mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.ID == "all-9" && d.Status == models.DeliveryStatusPending
+	})).Return(nil).Once()
+	mockRepo.On("EnqueueDelivery", mock.Anything, mock.MatchedBy(func(d models.WebhookDelivery) bool {
+		return d.ID == "german-9" && d.SubscriptionID == "german" && d.Event.ID == 9
+	})).Return(nil).Once()
+
+	dispatcher := webhooks.NewDispatcher(mockRepo, events.NewBroker(new(mockRepos.EventRepository)), http.DefaultClient)
+	err := dispatcher.Enqueue(context.Background(), models.Event{
+		ID:          9,
+		Type:        models.EventTypeCreated,
+		SwiftCode:   "DEUTDE11XXX",
+		CountryISO2: "DE",
+	})
+	require.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcherCursor(t *testing.T) {
+	start := func(t *testing.T, repo *mockRepos.WebhookRepository, eventRepo *mockRepos.EventRepository) {
+		repo.On("ClaimDelivery", mock.Anything, mock.Anything, mock.Anything).Return(nil, interfaces.ErrNotFound).Maybe()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		webhooks.NewDispatcher(repo, events.NewBroker(eventRepo), http.DefaultClient).Start(ctx)
+	}
+
+	t.Run("Events after the saved cursor are replayed on start", func(t *testing.T) {
+		saved := make(chan struct{})
+		repo := new(mockRepos.WebhookRepository)
+		repo.On("FindCursor", mock.Anything).Return(int64(3), nil)
+		repo.On("FindSubscriptions", mock.Anything).Return([]models.WebhookSubscription{{ID: "all"}}, nil)
+		repo.On("EnqueueDelivery", mock.Anything, mock.MatchedBy(func(d models.WebhookDelivery) bool {
+			return d.ID == "all-4"
+		})).Return(nil).Once()
+		repo.On("SaveCursor", mock.Anything, int64(4)).Run(func(mock.Arguments) { close(saved) }).Return(nil).Once()
+
+		eventRepo := new(mockRepos.EventRepository)
+		eventRepo.On("FindAfter", mock.Anything, int64(3), "", int64(500)).Return([]models.Event{
+			{ID: 4, Type: models.EventTypeDeleted, SwiftCode: "DEUTDE11XXX", CountryISO2: "DE"},
+		}, nil)
+
+		start(t, repo, eventRepo)
+		select {
+		case <-saved:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the cursor was not moved past the replayed event")
+		}
+	})
+
+	t.Run("The first dispatcher starts from the latest event", func(t *testing.T) {
+		replayed := make(chan struct{})
+		repo := new(mockRepos.WebhookRepository)
+		repo.On("FindCursor", mock.Anything).Return(int64(0), interfaces.ErrNotFound)
+		repo.On("SaveCursor", mock.Anything, int64(7)).Return(nil).Once()
+
+		eventRepo := new(mockRepos.EventRepository)
+		eventRepo.On("LatestID", mock.Anything).Return(int64(7), nil)
+		eventRepo.On("FindAfter", mock.Anything, int64(7), "", int64(500)).Run(func(mock.Arguments) { close(replayed) }).Return([]models.Event{}, nil).Once()
+
+		start(t, repo, eventRepo)
+		select {
+		case <-replayed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the event log was not replayed from the latest event")
+		}
+	})
+}
+
+func TestWebhookClientRefusesPrivateAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the receiver on a loopback address was reached")
+	}))
+	defer receiver.Close()
+
+	_, err := webhooks.NewClient(time.Second).Post(receiver.URL, "application/json", nil)
+	assert.ErrorIs(t, err, webhooks.ErrPrivateTarget)
+}
@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http/httptest"
+	"swift-codes-api/handlers"
+	"swift-codes-api/internal/reqctx"
+	"swift-codes-api/models"
+	mockRepos "swift-codes-api/repositories/mock"
+	"swift-codes-api/tests/spec"
+	"swift-codes-api/tests/unit/test_cases"
+	"testing"
+)
+
+// fakeResolver resolves the hosts it knows and fails on the others.
+type fakeResolver map[string]string
+
+func (r fakeResolver) LookupIPAddr(_ context.Context, host string) ([]net.IPAddr, error) {
+	ip, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+}
+
+func TestWebhooks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tc := range test_cases.GetWebhooksTestCases() {
+		t.Run(tc.Name, func(t *testing.T) {
+			mockRepo := new(mockRepos.WebhookRepository)
+			tc.SetupMocks(mockRepo)
+
+			handler := handlers.NewWebhooksHandler(mockRepo, handlers.WithResolver(fakeResolver{
+				"example.com": "93.184.215.14",
+				"localhost":   "127.0.0.1",
+			}))
+			router := gin.Default()
+			if !tc.NotAdmin {
+				router.Use(func(c *gin.Context) {
+					c.Request = c.Request.WithContext(reqctx.WithAdmin(c.Request.Context()))
+				})
+			}
+			router.POST("/v1/webhooks", handler.CreateWebhook)
+			router.GET("/v1/webhooks", handler.GetWebhooks)
+			router.DELETE("/v1/webhooks/:id", handler.DeleteWebhook)
+			router.GET("/v1/webhooks/:id/deliveries", handler.GetWebhookDeliveries)
+
+			req := httptest.NewRequest(tc.Method, tc.Path, bytes.NewBufferString(tc.RequestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			spec.ValidateResponse(t, req, w)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			if tc.ExpectedResponse != "" {
+				assert.JSONEq(t, tc.ExpectedResponse, w.Body.String())
+			} else {
+				var subscription models.WebhookSubscription
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &subscription))
+				assert.NotEmpty(t, subscription.ID)
+				assert.Equal(t, "s3cret", subscription.Secret)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rules a SWIFT code can break, reported by DiagnoseSwiftCode.
+const (
+	RuleLength       = "length"
+	RuleLetters      = "letters"
+	RuleAlphanumeric = "alphanumeric"
+)
+
+// SegmentDiagnosis is the verdict on one segment of a SWIFT code. Rule and
+// Message are only set when the segment is invalid.
+type SegmentDiagnosis struct {
+	Segment string
+	Value   string
+	Valid   bool
+	Rule    string
+	Message string
+}
+
+// SwiftCodeDiagnosis explains whether a code is syntactically valid. Code is
+// the upper-cased input, expanded to a BIC11 when it is a valid BIC8.
+type SwiftCodeDiagnosis struct {
+	Code          string
+	Valid         bool
+	LengthValid   bool
+	LengthMessage string
+	Segments      []SegmentDiagnosis
+}
+
+type segment struct {
+	name       string
+	start, end int
+	rule       string
+}
+
+var segments = []segment{
+	{"institution", 0, 4, RuleLetters},
+	{"country", 4, 6, RuleLetters},
+	{"location", 6, 8, RuleAlphanumeric},
+	{"branch", 8, 11, RuleAlphanumeric},
+}
+
+// DiagnoseSwiftCode checks the length of a code and each of its segments:
+// the institution (characters 1-4), country (5-6), location (7-8) and, for
+// a BIC11, branch (9-11). Segments are checked even when the length is wrong
+// so that every problem is reported at once.
+func DiagnoseSwiftCode(input string) SwiftCodeDiagnosis {
+	code := strings.ToUpper(strings.TrimSpace(input))
+	diagnosis := SwiftCodeDiagnosis{Code: code, LengthValid: len(code) == 8 || len(code) == SwiftCodeLength}
+	if !diagnosis.LengthValid {
+		diagnosis.LengthMessage = "Must be 8 or 11 characters, got " + strconv.Itoa(len(code))
+	}
+
+	diagnosis.Valid = diagnosis.LengthValid
+	for _, s := range segments {
+		if s.start >= len(code) {
+			if s.name != "branch" {
+				diagnosis.Segments = append(diagnosis.Segments, SegmentDiagnosis{Segment: s.name, Rule: RuleLength, Message: "Missing"})
+				diagnosis.Valid = false
+			}
+			continue
+		}
+
+		d := diagnoseSegment(s, code[s.start:min(s.end, len(code))])
+		diagnosis.Valid = diagnosis.Valid && d.Valid
+		diagnosis.Segments = append(diagnosis.Segments, d)
+	}
+
+	if diagnosis.Valid && len(code) == 8 {
+		diagnosis.Code += "XXX"
+	}
+	return diagnosis
+}
+
+func diagnoseSegment(s segment, value string) SegmentDiagnosis {
+	d := SegmentDiagnosis{Segment: s.name, Value: value, Valid: true}
+	fail := func(rule, message string) {
+		d.Valid, d.Rule, d.Message = false, rule, message
+	}
+
+	switch {
+	case len(value) < s.end-s.start:
+		fail(RuleLength, "Must be "+strconv.Itoa(s.end-s.start)+" characters")
+	case s.rule == RuleLetters && !isLetters(value):
+		fail(RuleLetters, "Must contain only letters A-Z")
+	case s.rule == RuleAlphanumeric && !isAlphanumeric(value):
+		fail(RuleAlphanumeric, "Must contain only letters A-Z and digits")
+	}
+	return d
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// ClosestSwiftCodes returns up to limit candidates within maxDistance edits
+// (insertions, deletions, substitutions or swaps of adjacent characters) of
+// code, closest first. Candidates whose length alone puts them further away
+// are skipped without computing their distance.
+func ClosestSwiftCodes(code string, candidates []string, maxDistance, limit int) []string {
+	type match struct {
+		code     string
+		distance int
+	}
+
+	var matches []match
+	for _, candidate := range candidates {
+		if abs(len(candidate)-len(code)) > maxDistance {
+			continue
+		}
+		if d := editDistance(code, candidate); d <= maxDistance {
+			matches = append(matches, match{candidate, d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].code < matches[j].code
+	})
+
+	closest := []string{}
+	for i := 0; i < len(matches) && i < limit; i++ {
+		closest = append(closest, matches[i].code)
+	}
+	return closest
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// editDistance is the optimal string alignment distance between a and b.
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
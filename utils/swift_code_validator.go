@@ -3,11 +3,15 @@ package utils
 import (
 	"regexp"
 	"strings"
+	"swift-codes-api/models"
 )
 
 const (
 	SwiftCodeLength   = 11
 	CountryCodeLength = 2
+
+	InvalidSwiftCodeMessage   = "SWIFT code must be 11 characters: a 4-letter bank code, a 2-letter country code, a 2-character location code and a 3-character branch code"
+	InvalidCountryCodeMessage = "Country code must be a 2-letter ISO 3166-1 alpha-2 code"
 )
 
 func ValidateSwiftCode(code string) bool {
@@ -20,6 +24,16 @@ func ValidateSwiftCode(code string) bool {
 	return match
 }
 
+// NormalizeSwiftCode upper-cases a BIC8 or BIC11 and expands a BIC8 to the
+// BIC11 of its headquarter, reporting whether the result is valid.
+func NormalizeSwiftCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) == 8 {
+		code += "XXX"
+	}
+	return code, ValidateSwiftCode(code)
+}
+
 func ValidateCountryCode(code string) bool {
 	if len(code) != CountryCodeLength {
 		return false
@@ -29,3 +43,60 @@ func ValidateCountryCode(code string) bool {
 	match, _ := regexp.MatchString(pattern, strings.ToUpper(code))
 	return match
 }
+
+// FieldError tells which field of a SWIFT code failed validation and why.
+// Code is one of FieldRequired, FieldFormat or FieldMismatch.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	FieldRequired = "required"
+	FieldFormat   = "format"
+	FieldMismatch = "mismatch"
+)
+
+// ValidateSwiftCodeRecord checks a SWIFT code sent for storage, normalising
+// its country code, and returns every field that is not acceptable.
+func ValidateSwiftCodeRecord(swiftCode *models.SwiftCode) []FieldError {
+	var errs []FieldError
+	fail := func(field, code, message string) {
+		errs = append(errs, FieldError{Field: field, Code: code, Message: message})
+	}
+
+	required := []struct {
+		field string
+		value string
+	}{
+		{"swiftCode", swiftCode.SwiftCode},
+		{"bankName", swiftCode.BankName},
+		{"address", swiftCode.Address},
+		{"countryISO2", swiftCode.CountryISO2},
+		{"countryName", swiftCode.CountryName},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			fail(r.field, FieldRequired, "Must not be empty")
+		}
+	}
+
+	swiftCode.CountryISO2 = strings.ToUpper(swiftCode.CountryISO2)
+
+	validCode := swiftCode.SwiftCode != "" && ValidateSwiftCode(swiftCode.SwiftCode)
+	if swiftCode.SwiftCode != "" && !validCode {
+		fail("swiftCode", FieldFormat, InvalidSwiftCodeMessage)
+	}
+
+	validCountry := swiftCode.CountryISO2 != "" && ValidateCountryCode(swiftCode.CountryISO2)
+	if swiftCode.CountryISO2 != "" && !validCountry {
+		fail("countryISO2", FieldFormat, InvalidCountryCodeMessage)
+	}
+
+	if validCode && validCountry && swiftCode.SwiftCode[4:6] != swiftCode.CountryISO2 {
+		fail("countryISO2", FieldMismatch, "Must match characters 5-6 of the SWIFT code")
+	}
+
+	return errs
+}